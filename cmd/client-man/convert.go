@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"clientinfo/internal/history"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+)
+
+var convertOut string
+
+func init() {
+	registerCommand(vaultCommand{
+		Name:    "convert",
+		Summary: "copy the vault into another storage backend (e.g. --out client_info.db)",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&convertOut, "out", "", "target file; the backend is chosen by extension (.json, .db, .sqlite)")
+		},
+		Run: runConvert,
+	})
+}
+
+// runConvert unlocks --file and writes its clients and history to --out under the same key
+func runConvert(ctx *cliContext) error {
+	if convertOut == "" {
+		return errors.New("--out is required")
+	}
+	if _, err := os.Stat(convertOut); err == nil {
+		return fmt.Errorf("%s already exists", convertOut)
+	}
+
+	src, err := store.Open(ctx.File)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	password, err := readPassword("Master password: ")
+	if err != nil {
+		return err
+	}
+	unlocked, err := src.Unlock(&vault.MigrationContext{Password: password})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.Out, "%d customers → %s\n", len(unlocked.Clients), convertOut)
+	if ctx.DryRun {
+		fmt.Fprintf(ctx.Out, "dry run: %s not created\n", convertOut)
+		return nil
+	}
+
+	dst, err := store.Open(convertOut)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	keys := store.Keys{KDF: unlocked.KDF, Key: unlocked.Key, Cipher: unlocked.Cipher}
+	if err := dst.SetKeys(keys); err != nil {
+		// SQLite tüm dosya şifrelemeyi desteklemez; alan bazlı şifrelemeye düşülür
+		keys.Cipher = vault.CipherAESGCM
+		if err := dst.SetKeys(keys); err != nil {
+			return err
+		}
+		fmt.Fprintln(ctx.Out, "whole-file encryption is not available for the target; secrets are encrypted per field")
+	}
+	if err := dst.Save(unlocked.Clients); err != nil {
+		return err
+	}
+
+	// Geçmiş aynı anahtarla şifreli; hedefin yanına olduğu gibi kopyalanır
+	if _, err := os.Stat(history.PathFor(ctx.File)); err == nil {
+		if err := store.CopyFile(history.PathFor(ctx.File), history.PathFor(convertOut), store.VaultPerm); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(ctx.Out, "wrote %s\n", convertOut)
+	return nil
+}
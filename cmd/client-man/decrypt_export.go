@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"clientinfo/internal/store"
+)
+
+var exportOut string
+
+func init() {
+	registerCommand(vaultCommand{
+		Name:    "decrypt-export",
+		Summary: "write all customers as plaintext JSON (contains every password!)",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&exportOut, "out", "", "output file (default: stdout)")
+		},
+		Run: runDecryptExport,
+	})
+}
+
+func runDecryptExport(ctx *cliContext) error {
+	st, unlocked, err := ctx.unlockStore()
+	if err != nil {
+		return err
+	}
+	st.Close()
+
+	data, err := json.MarshalIndent(unlocked.Clients, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Çıktı stdout'a gidebileceği için durum mesajları stderr'e yazılır
+	if ctx.DryRun {
+		fmt.Fprintf(os.Stderr, "dry run: would export %d customers\n", len(unlocked.Clients))
+		return nil
+	}
+	if exportOut == "" {
+		_, err := os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	if err := store.WriteFile(exportOut, data, store.VaultPerm); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "exported %d customers to %s (plaintext, delete it when done)\n", len(unlocked.Clients), exportOut)
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"clientinfo/internal/exchange"
+	"clientinfo/internal/model"
+	"clientinfo/internal/redact"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+)
+
+// minExportPassphrase matches the minimum length the desktop app accepts
+const minExportPassphrase = 12
+
+var (
+	sealOut          string
+	sealProfile      string
+	sealRecipients   string
+	sealCustomer     string
+	sealListProfiles bool
+)
+
+func init() {
+	registerCommand(vaultCommand{
+		Name:    "export",
+		Summary: "write an encrypted customer export filtered by an export profile (--profile, --out)",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&sealOut, "out", "", "export file to write (.age)")
+			fs.StringVar(&sealProfile, "profile", redact.ProfileCustomerHandover, "export profile deciding which fields leave the machine")
+			fs.StringVar(&sealRecipients, "recipient", "", "comma separated age public keys (age1...); default: prompt for a passphrase")
+			fs.StringVar(&sealCustomer, "customer", "", "export only this customer (company name or id); default: all")
+			fs.BoolVar(&sealListProfiles, "list-profiles", false, "list the available export profiles and exit")
+		},
+		Run: runExport,
+	})
+}
+
+// runExport unlocks --file, applies the profile and seals the result to --out.
+// Önizleme her zaman yazılır; --dry-run ile dosya oluşturulmaz.
+func runExport(ctx *cliContext) error {
+	profiles, err := redact.Load(ctx.File)
+	if err != nil {
+		return fmt.Errorf("profiles %s: %w", redact.PathFor(ctx.File), err)
+	}
+	if sealListProfiles {
+		for _, p := range profiles {
+			fmt.Fprintf(ctx.Out, "%-20s %s\n", p.Name, p.Description)
+		}
+		return nil
+	}
+	profile, ok := redact.Find(profiles, sealProfile)
+	if !ok {
+		return fmt.Errorf("unknown profile %q; available: %s", sealProfile, strings.Join(redact.Names(profiles), ", "))
+	}
+	if sealOut == "" && !ctx.DryRun {
+		return errors.New("--out is required")
+	}
+
+	src, err := store.Open(ctx.File)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	password, err := readPassword("Master password: ")
+	if err != nil {
+		return err
+	}
+	unlocked, err := src.Unlock(&vault.MigrationContext{Password: password})
+	if err != nil {
+		return err
+	}
+
+	clients, err := selectCustomers(unlocked.Clients, sealCustomer)
+	if err != nil {
+		return err
+	}
+	redacted, decisions, err := profile.Apply(clients)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Out, "profile %q, %d customers:\n%s\n", profile.Name, len(redacted), redact.Preview(decisions))
+	if ctx.DryRun {
+		fmt.Fprintln(ctx.Out, "dry run: nothing written")
+		return nil
+	}
+
+	var sealed []byte
+	if sealRecipients != "" {
+		sealed, err = exchange.SealToRecipients(redacted, strings.Split(sealRecipients, ","))
+	} else {
+		var passphrase string
+		if passphrase, err = readExportPassphrase(); err == nil {
+			sealed, err = exchange.SealWithPassphrase(redacted, passphrase)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := store.WriteFile(sealOut, sealed, store.VaultPerm); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Out, "wrote %s\n", sealOut)
+	return nil
+}
+
+// selectCustomers returns all clients, or the one whose id or company matches name
+func selectCustomers(clients []model.Client, name string) ([]model.Client, error) {
+	if name == "" {
+		return clients, nil
+	}
+	if i := model.IndexByID(clients, name); i >= 0 {
+		return clients[i : i+1], nil
+	}
+	for i, c := range clients {
+		if strings.EqualFold(c.Company, name) {
+			return clients[i : i+1], nil
+		}
+	}
+	return nil, fmt.Errorf("no customer %q", name)
+}
+
+// readExportPassphrase asks for the export passphrase twice
+func readExportPassphrase() (string, error) {
+	passphrase, err := readPassword("Export passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if len([]rune(passphrase)) < minExportPassphrase {
+		return "", fmt.Errorf("the passphrase must be at least %d characters", minExportPassphrase)
+	}
+	again, err := readPassword("Repeat export passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", errors.New("the passphrases do not match")
+	}
+	fmt.Fprintln(os.Stderr, "send the passphrase separately from the file")
+	return passphrase, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	"clientinfo/internal/store/sqlite"
+	"clientinfo/internal/vault"
+)
+
+func init() {
+	registerCommand(vaultCommand{
+		Name:    "inspect",
+		Summary: "show file format, version and encryption status (no password needed)",
+		Run:     runInspect,
+	})
+}
+
+func runInspect(ctx *cliContext) error {
+	st, err := store.Open(ctx.File)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	if db, ok := st.(*sqlite.DB); ok {
+		return inspectSQLite(ctx, db)
+	}
+
+	// JSON dosya: sürüm ve gizli alanlar zarfın kendisinden okunur
+	data, err := os.ReadFile(ctx.File)
+	if err != nil {
+		return err
+	}
+	env, err := vault.ParseEnvelope(data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.Out, "file:     %s\n", ctx.File)
+	if env.Version == 0 {
+		fmt.Fprintln(ctx.Out, "format:   legacy client array (shared built-in key)")
+	} else {
+		fmt.Fprintf(ctx.Out, "format:   %s\n", valueOr(env.Format, "vault header without format field"))
+	}
+	fmt.Fprintf(ctx.Out, "version:  %d (current %d)\n", env.Version, vault.FormatVersion)
+	if env.Version > 0 {
+		fmt.Fprintf(ctx.Out, "kdf:      %s time=%d memory=%dKiB threads=%d\n",
+			env.KDF.Name, env.KDF.Time, env.KDF.Memory, env.KDF.Threads)
+		fmt.Fprintf(ctx.Out, "cipher:   %s\n", valueOr(env.Cipher, vault.CipherAESGCM))
+	}
+
+	// Tüm dosya şifreliyse içerik parolasız görülemez
+	if env.Cipher == vault.CipherXChaCha {
+		fmt.Fprintln(ctx.Out, "content:  whole-file encrypted (customers and secrets hidden)")
+	} else {
+		var clients []model.Client
+		if err := json.Unmarshal(env.Clients, &clients); err != nil {
+			return err
+		}
+		encrypted, plain := 0, 0
+		model.VisitSecrets(clients, func(_ *model.Client, _ string, value *string) error {
+			if vault.IsEncrypted(*value) {
+				encrypted++
+			} else {
+				plain++
+			}
+			return nil
+		})
+		fmt.Fprintf(ctx.Out, "content:  %d customers, %d encrypted secrets, %d plaintext secrets\n",
+			len(clients), encrypted, plain)
+	}
+
+	pending := vault.Pending(env.Version)
+	if len(pending) == 0 {
+		fmt.Fprintln(ctx.Out, "status:   up to date")
+		return nil
+	}
+	fmt.Fprintln(ctx.Out, "status:   needs migration (client-man vault migrate)")
+	for _, m := range pending {
+		fmt.Fprintf(ctx.Out, "  %d→%d  %s\n", m.From, m.From+1, m.Description)
+	}
+	return nil
+}
+
+// inspectSQLite reports the header and row count of a SQLite vault
+func inspectSQLite(ctx *cliContext, db *sqlite.DB) error {
+	info, err := db.Inspect()
+	if err != nil {
+		return err
+	}
+	kdf, err := db.KDF()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(ctx.Out, "file:     %s\n", ctx.File)
+	fmt.Fprintf(ctx.Out, "format:   %s\n", sqlite.FormatName)
+	fmt.Fprintf(ctx.Out, "version:  %d (current %d)\n", info.Version, sqlite.SchemaVersion)
+	fmt.Fprintf(ctx.Out, "kdf:      %s time=%d memory=%dKiB threads=%d\n", kdf.Name, kdf.Time, kdf.Memory, kdf.Threads)
+	fmt.Fprintf(ctx.Out, "cipher:   %s per customer row\n", vault.CipherXChaCha)
+	fmt.Fprintf(ctx.Out, "content:  %d customers, secrets encrypted per row\n", info.Customers)
+
+	if info.Version >= sqlite.SchemaVersion {
+		fmt.Fprintln(ctx.Out, "status:   up to date")
+		return nil
+	}
+	fmt.Fprintln(ctx.Out, "status:   needs migration (client-man vault migrate)")
+	fmt.Fprintf(ctx.Out, "  %d→%d  bind row secrets to customer ids instead of company names\n", info.Version, sqlite.SchemaVersion)
+	return nil
+}
+
+func valueOr(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+	return v
+}
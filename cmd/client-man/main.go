@@ -0,0 +1,229 @@
+// Command client-man is the maintenance CLI for client-man vault files.
+//
+//	client-man vault <command> [--file client_info.json] [--dry-run] [flags]
+//
+// Komutlar vaultCommands kayıt listesine registerCommand ile eklenir; her komut
+// kendi dosyasında init() içinde kaydolur.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"clientinfo/internal/backup"
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	_ "clientinfo/internal/store/sqlite" // .db/.sqlite vault'ları için backend
+	"clientinfo/internal/vault"
+	"golang.org/x/term"
+)
+
+// defaultFile is the vault file name used by the desktop application
+const defaultFile = "client_info.json"
+
+// errUsage is returned when the command line is invalid; usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+// cliContext carries the parsed common flags into a command
+type cliContext struct {
+	File   string
+	DryRun bool
+	Args   []string // Bayraklardan sonra kalan argümanlar
+	Out    io.Writer
+}
+
+// vaultCommand is one `client-man vault` subcommand
+type vaultCommand struct {
+	Name    string
+	Summary string
+	// Flags registers command specific flags (opsiyonel)
+	Flags func(fs *flag.FlagSet)
+	Run   func(ctx *cliContext) error
+}
+
+var vaultCommands []vaultCommand
+
+// registerCommand adds a subcommand; names must be unique
+func registerCommand(c vaultCommand) {
+	for _, existing := range vaultCommands {
+		if existing.Name == c.Name {
+			panic(fmt.Sprintf("vault command %q registered twice", c.Name))
+		}
+	}
+	vaultCommands = append(vaultCommands, c)
+	sort.Slice(vaultCommands, func(i, j int) bool {
+		return vaultCommands[i].Name < vaultCommands[j].Name
+	})
+}
+
+func findCommand(name string) (vaultCommand, bool) {
+	for _, c := range vaultCommands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return vaultCommand{}, false
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) < 2 || args[0] != "vault" {
+		printUsage(os.Stderr)
+		return errUsage
+	}
+
+	cmd, ok := findCommand(args[1])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", args[1])
+		printUsage(os.Stderr)
+		return errUsage
+	}
+
+	ctx := &cliContext{Out: out}
+	fs := flag.NewFlagSet("client-man vault "+cmd.Name, flag.ContinueOnError)
+	fs.StringVar(&ctx.File, "file", "", "vault file (default: "+defaultFile+" in the current or parent directory)")
+	fs.BoolVar(&ctx.DryRun, "dry-run", false, "report what would change without writing anything")
+	if cmd.Flags != nil {
+		cmd.Flags(fs)
+	}
+	if err := fs.Parse(args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	ctx.Args = fs.Args()
+
+	if ctx.File == "" {
+		ctx.File = findDataFile()
+	}
+	return cmd.Run(ctx)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: client-man vault <command> [--file path] [--dry-run] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range vaultCommands {
+		fmt.Fprintf(w, "  %-15s %s\n", c.Name, c.Summary)
+	}
+}
+
+// findDataFile looks for the default vault in the current directory, then its parent
+func findDataFile() string {
+	if _, err := os.Stat(defaultFile); err == nil {
+		return defaultFile
+	}
+	parent := filepath.Join("..", defaultFile)
+	if _, err := os.Stat(parent); err == nil {
+		return parent
+	}
+	return defaultFile
+}
+
+// readPassword reads a password from the terminal without echo, or one line from
+// stdin when it is not a terminal (script kullanımı için).
+func readPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		line, err := stdinReader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("reading password: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return string(b), nil
+}
+
+// stdinReader is shared so consecutive password reads from a pipe do not lose buffered lines
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// unlockStore opens ctx.File with the backend chosen by its extension and unlocks it
+// with a prompted master password. Çağıran store'u kapatmalıdır.
+func (ctx *cliContext) unlockStore() (store.Store, *vault.Unlocked, error) {
+	st, err := store.Open(ctx.File)
+	if err != nil {
+		return nil, nil, err
+	}
+	unlocked, err := unlockOpened(st)
+	if err != nil {
+		st.Close()
+		return nil, nil, err
+	}
+	return st, unlocked, nil
+}
+
+// unlockOpened unlocks an open store with a prompted master password
+func unlockOpened(st store.Store) (*vault.Unlocked, error) {
+	if _, err := os.Stat(st.Path()); err != nil {
+		return nil, err
+	}
+	password, err := readPassword("Master password: ")
+	if err != nil {
+		return nil, err
+	}
+	return st.Unlock(&vault.MigrationContext{Password: password})
+}
+
+// writeStore backs up the current file and saves clients to st under keys.
+// --dry-run ile hiçbir şey yazılmaz.
+func (ctx *cliContext) writeStore(st store.Store, keys store.Keys, clients []model.Client) error {
+	if ctx.DryRun {
+		fmt.Fprintf(ctx.Out, "dry run: %s not modified\n", ctx.File)
+		return nil
+	}
+	snap, err := backup.New(ctx.File, backup.DefaultPolicy).Snapshot()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Out, "backup: %s\n", snap.Path)
+	if err := st.SetKeys(keys); err != nil {
+		return err
+	}
+	if err := st.Save(clients); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Out, "wrote %s\n", ctx.File)
+	return nil
+}
+
+// writeVault backs up the current file and atomically replaces it with data.
+// --dry-run ile hiçbir şey yazılmaz.
+func (ctx *cliContext) writeVault(data []byte) error {
+	if ctx.DryRun {
+		fmt.Fprintf(ctx.Out, "dry run: %s not modified\n", ctx.File)
+		return nil
+	}
+	snap, err := backup.New(ctx.File, backup.DefaultPolicy).Snapshot()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Out, "backup: %s\n", snap.Path)
+	if err := store.WriteFile(ctx.File, data, store.VaultPerm); err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Out, "wrote %s\n", ctx.File)
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	"clientinfo/internal/store/sqlite"
+	"clientinfo/internal/vault"
+)
+
+const testPassword = "correct horse battery"
+
+// runCLI runs `client-man vault args...` with stdin as the piped password input
+func runCLI(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	stdinReader = bufio.NewReader(strings.NewReader(stdin))
+	var out bytes.Buffer
+	err := run(append([]string{"vault"}, args...), &out)
+	return out.String(), err
+}
+
+// newSQLiteVault writes a SQLite vault with two customers under testPassword
+func newSQLiteVault(t *testing.T) string {
+	t.Helper()
+	return newVault(t, "client_info.db")
+}
+
+// newVault writes a vault named name with two customers under testPassword; backend uzantıdan seçilir
+func newVault(t *testing.T, name string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	params, err := vault.NewKDFParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := vault.DeriveKey(testPassword, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.SetKeys(store.Keys{KDF: params, Key: key, Cipher: vault.CipherAESGCM}); err != nil {
+		t.Fatal(err)
+	}
+	clients := []model.Client{
+		{ID: model.NewID(), Company: "ACME", VPN: model.VPNInfo{Host: "vpn.acme.example", Password: "vpn-secret"}},
+		{ID: model.NewID(), Company: "Globex", Data: model.ClientData{JiraPassword: "jira-secret"}},
+	}
+	if err := db.Save(clients); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSQLiteVaultCommands(t *testing.T) {
+	path := newSQLiteVault(t)
+
+	out, err := runCLI(t, "", "inspect", "--file", path)
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	for _, want := range []string{sqlite.FormatName, "2 customers", "up to date"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("inspect output lacks %q:\n%s", want, out)
+		}
+	}
+
+	if out, err = runCLI(t, testPassword+"\n", "verify", "--file", path); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if !strings.Contains(out, "ok:") {
+		t.Errorf("verify output: %s", out)
+	}
+	if out, err = runCLI(t, testPassword+"\n", "repair", "--file", path); err != nil || !strings.Contains(out, "nothing to repair") {
+		t.Errorf("repair: %v\n%s", err, out)
+	}
+	if out, err = runCLI(t, "", "migrate", "--file", path); err != nil || !strings.Contains(out, "already at version") {
+		t.Errorf("migrate: %v\n%s", err, out)
+	}
+
+	exported := filepath.Join(t.TempDir(), "plain.json")
+	if _, err := runCLI(t, testPassword+"\n", "decrypt-export", "--file", path, "--out", exported); err != nil {
+		t.Fatalf("decrypt-export: %v", err)
+	}
+	data, err := os.ReadFile(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clients []model.Client
+	if err := json.Unmarshal(data, &clients); err != nil {
+		t.Fatal(err)
+	}
+	if len(clients) != 2 || clients[0].VPN.Password != "vpn-secret" || clients[1].Data.JiraPassword != "jira-secret" {
+		t.Fatalf("decrypt-export wrote %+v", clients)
+	}
+}
+
+func TestSQLiteRotateKey(t *testing.T) {
+	testRotateKey(t, newSQLiteVault(t))
+}
+
+func TestJSONRotateKey(t *testing.T) {
+	testRotateKey(t, newVault(t, "client_info.json"))
+}
+
+func testRotateKey(t *testing.T, path string) {
+	const newPassword = "a brand new password"
+
+	if _, err := runCLI(t, testPassword+"\n"+newPassword+"\n"+newPassword+"\n", "rotate-key", "--file", path); err != nil {
+		t.Fatalf("rotate-key: %v", err)
+	}
+
+	if _, err := runCLI(t, testPassword+"\n", "verify", "--file", path); !errors.Is(err, vault.ErrWrongPassword) {
+		t.Fatalf("old password after rotation: err = %v, want ErrWrongPassword", err)
+	}
+	st, err := store.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	unlocked, err := st.Unlock(&vault.MigrationContext{Password: newPassword})
+	if err != nil {
+		t.Fatalf("new password after rotation: %v", err)
+	}
+	if len(unlocked.Clients) != 2 || unlocked.Clients[0].VPN.Password != "vpn-secret" {
+		t.Fatalf("rotated vault holds %+v", unlocked.Clients)
+	}
+}
+
+func TestSQLiteRotateKeyDryRun(t *testing.T) {
+	path := newSQLiteVault(t)
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCLI(t, testPassword+"\nanother password\nanother password\n", "rotate-key", "--dry-run", "--file", path); err != nil {
+		t.Fatalf("rotate-key --dry-run: %v", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Fatal("--dry-run modified the vault")
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	"clientinfo/internal/store/sqlite"
+	"clientinfo/internal/vault"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// Eski sürümlerin anahtarı sakladığı keyring kaydı
+	oldKeyringService = "client-manager"
+	oldKeyringUser    = "encryption-key"
+)
+
+var migrateOldKeyring bool
+
+func init() {
+	registerCommand(vaultCommand{
+		Name:    "migrate",
+		Summary: "apply the registered format migrations in order",
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&migrateOldKeyring, "old-keyring", false,
+				"first decrypt a legacy file with the key the oldest releases kept in the OS keyring")
+		},
+		Run: runMigrate,
+	})
+}
+
+func runMigrate(ctx *cliContext) error {
+	st, err := store.Open(ctx.File)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	if db, ok := st.(*sqlite.DB); ok {
+		return migrateSQLite(ctx, db)
+	}
+
+	data, err := os.ReadFile(ctx.File)
+	if err != nil {
+		return err
+	}
+	env, err := vault.ParseEnvelope(data)
+	if err != nil {
+		return err
+	}
+
+	pending := vault.Pending(env.Version)
+	if len(pending) == 0 {
+		fmt.Fprintf(ctx.Out, "%s is already at version %d\n", ctx.File, env.Version)
+		return nil
+	}
+	for _, m := range pending {
+		fmt.Fprintf(ctx.Out, "%d→%d  %s\n", m.From, m.From+1, m.Description)
+	}
+
+	if migrateOldKeyring {
+		if env.Version != 0 {
+			return errors.New("--old-keyring only applies to legacy client arrays")
+		}
+		if data, err = decryptWithOldKeyring(ctx, data); err != nil {
+			return err
+		}
+	}
+
+	// Eski dosyada master password yok: girilen şifre yeni master password olur
+	var password string
+	if env.Version == 0 {
+		password, err = readNewPassword()
+	} else {
+		password, err = readPassword("Master password: ")
+	}
+	if err != nil {
+		return err
+	}
+
+	unlocked, err := vault.Unlock(data, &vault.MigrationContext{Password: password})
+	if err != nil {
+		return err
+	}
+	out, err := vault.Marshal(unlocked.KDF, unlocked.Key, unlocked.Cipher, unlocked.Clients)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Out, "migrated %d customers to version %d\n", len(unlocked.Clients), vault.FormatVersion)
+	return ctx.writeVault(out)
+}
+
+// migrateSQLite rewrites every row of an older SQLite schema.
+// Şema sütunları açılışta eklenir; secrets'ın uid'ye bağlanması ise anahtarla tam bir kayıt gerektirir.
+func migrateSQLite(ctx *cliContext, db *sqlite.DB) error {
+	if migrateOldKeyring {
+		return errors.New("--old-keyring only applies to legacy client arrays")
+	}
+	info, err := db.Inspect()
+	if err != nil {
+		return err
+	}
+	if info.Version >= sqlite.SchemaVersion {
+		fmt.Fprintf(ctx.Out, "%s is already at version %d\n", ctx.File, info.Version)
+		return nil
+	}
+	fmt.Fprintf(ctx.Out, "%d→%d  bind row secrets to customer ids instead of company names\n", info.Version, sqlite.SchemaVersion)
+
+	unlocked, err := unlockOpened(db)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(ctx.Out, "migrated %d customers to version %d\n", len(unlocked.Clients), sqlite.SchemaVersion)
+	keys := store.Keys{KDF: unlocked.KDF, Key: unlocked.Key, Cipher: unlocked.Cipher}
+	return ctx.writeStore(db, keys, unlocked.Clients)
+}
+
+// decryptWithOldKeyring decrypts the secrets of a legacy array with the key from the old keyring entry.
+// Çözülemeyen değerler olduğu gibi bırakılır ve raporlanır.
+func decryptWithOldKeyring(ctx *cliContext, data []byte) ([]byte, error) {
+	encoded, err := keyring.Get(oldKeyringService, oldKeyringUser)
+	if err != nil {
+		return nil, fmt.Errorf("old keyring key not found: %w", err)
+	}
+	oldKey, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("old keyring key: %w", err)
+	}
+	if len(oldKey) != vault.KeyLength {
+		return nil, fmt.Errorf("old keyring key has length %d, expected %d", len(oldKey), vault.KeyLength)
+	}
+
+	var clients []model.Client
+	if err := json.Unmarshal(data, &clients); err != nil {
+		return nil, err
+	}
+
+	decrypted := 0
+	model.VisitSecrets(clients, func(c *model.Client, field string, value *string) error {
+		if !vault.IsEncrypted(*value) {
+			return nil
+		}
+		plain, err := vault.DecryptString(*value, oldKey)
+		if err != nil {
+			fmt.Fprintf(ctx.Out, "  could not decrypt %s %s with the old keyring key: %v\n", c.Company, field, err)
+			return nil
+		}
+		*value = plain
+		decrypted++
+		return nil
+	})
+	fmt.Fprintf(ctx.Out, "decrypted %d secrets with the old keyring key\n", decrypted)
+
+	return json.Marshal(clients)
+}
+
+// readNewPassword asks for a new master password twice
+func readNewPassword() (string, error) {
+	password, err := readPassword("New master password: ")
+	if err != nil {
+		return "", err
+	}
+	if len([]rune(password)) < minPasswordLength {
+		return "", fmt.Errorf("master password must be at least %d characters", minPasswordLength)
+	}
+	confirm, err := readPassword("Repeat new master password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != confirm {
+		return "", errors.New("passwords do not match")
+	}
+	return password, nil
+}
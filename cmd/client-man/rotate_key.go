@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"clientinfo/internal/history"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+)
+
+// minPasswordLength uygulamadaki master password alt sınırıyla aynı
+const minPasswordLength = 8
+
+func init() {
+	registerCommand(vaultCommand{
+		Name:    "rotate-key",
+		Summary: "re-encrypt under a new master password with a fresh salt",
+		Run:     runRotateKey,
+	})
+}
+
+// runRotateKey re-encrypts the vault under a new master password and a fresh salt
+func runRotateKey(ctx *cliContext) error {
+	st, unlocked, err := ctx.unlockStore()
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	fmt.Fprintf(ctx.Out, "decrypted %d customers\n", len(unlocked.Clients))
+
+	newPassword, err := readNewPassword()
+	if err != nil {
+		return err
+	}
+
+	// Değişiklik geçmişi vault anahtarıyla şifreli; okunamıyorsa rotate etmeden dur
+	log, err := history.Load(ctx.File, unlocked.Key)
+	if err != nil {
+		return fmt.Errorf("history %s: %w", history.PathFor(ctx.File), err)
+	}
+
+	// Store yeni anahtarla tümünü yeniden yazar; Seal her gizli alan için yeni nonce üretir
+	params, err := vault.NewKDFParams()
+	if err != nil {
+		return err
+	}
+	key, err := vault.DeriveKey(newPassword, params)
+	if err != nil {
+		return err
+	}
+	if err := ctx.writeStore(st, store.Keys{KDF: params, Key: key, Cipher: unlocked.Cipher}, unlocked.Clients); err != nil {
+		return err
+	}
+	if !ctx.DryRun {
+		if err := log.Save(key); err != nil {
+			return err
+		}
+		fmt.Fprintln(ctx.Out, "keys remembered in the OS keyring for this file are no longer valid")
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+)
+
+func init() {
+	registerCommand(vaultCommand{
+		Name:    "verify",
+		Summary: "check that every secret decrypts with the master password",
+		Run:     runVerify,
+	})
+	registerCommand(vaultCommand{
+		Name:    "repair",
+		Summary: "encrypt plaintext secrets, recover or clear secrets that do not decrypt",
+		Run:     runRepair,
+	})
+}
+
+// secretProblem is one secret that is stored in plaintext or does not decrypt
+type secretProblem struct {
+	Company string
+	Field   string
+	Issue   string
+}
+
+// openedVault is a current-version vault whose key was checked but whose secrets are still raw
+type openedVault struct {
+	env     *vault.Envelope
+	key     []byte
+	clients []model.Client
+	// sealed names what was authenticated as a whole (tüm dosya, SQLite satırları); boşsa gizli alanlar tek tek denetlenir
+	sealed string
+}
+
+// openForCheck unlocks the header only; alan bazlı dosyalarda gizli alanlar şifreli kalır
+// ki her biri ayrı ayrı denetlenebilsin. Diğer backend'ler kayıtları bütün olarak doğrular.
+func openForCheck(ctx *cliContext) (*openedVault, error) {
+	st, err := store.Open(ctx.File)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := st.(*store.JSONFile); !ok {
+		defer st.Close()
+		// Unlock her satırı çözer; başarılıysa içindeki her değer de doğrulanmıştır
+		unlocked, err := unlockOpened(st)
+		if err != nil {
+			return nil, err
+		}
+		return &openedVault{key: unlocked.Key, clients: unlocked.Clients, sealed: "every customer row"}, nil
+	}
+	st.Close()
+
+	data, err := os.ReadFile(ctx.File)
+	if err != nil {
+		return nil, err
+	}
+	env, err := vault.ParseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(vault.Pending(env.Version)) > 0 {
+		return nil, fmt.Errorf("%s is at version %d, run `client-man vault migrate` first", ctx.File, env.Version)
+	}
+
+	password, err := readPassword("Master password: ")
+	if err != nil {
+		return nil, err
+	}
+	key, err := vault.DeriveKey(password, env.KDF)
+	if err != nil {
+		return nil, err
+	}
+	if err := vault.CheckKey(env, key); err != nil {
+		return nil, err
+	}
+
+	opened := &openedVault{env: env, key: key}
+	if env.Cipher == vault.CipherXChaCha {
+		// Tek blok: doğrulama başarılıysa içindeki her değer de doğrulanmıştır
+		opened.sealed = "whole-file payload"
+		opened.clients, err = vault.Open(env, key)
+		return opened, err
+	}
+	if err := json.Unmarshal(env.Clients, &opened.clients); err != nil {
+		return nil, err
+	}
+	return opened, nil
+}
+
+// checkSecrets decrypts every per-field secret in place and reports the ones that fail.
+// repair true ise çözülemeyen değerler önce eski ortak anahtarla denenir, o da
+// olmazsa temizlenir; plaintext değerler kaydederken şifrelenmek üzere bırakılır.
+func checkSecrets(clients []model.Client, key []byte, repair bool) []secretProblem {
+	var problems []secretProblem
+	model.VisitSecrets(clients, func(c *model.Client, field string, value *string) error {
+		if !vault.IsEncrypted(*value) {
+			problems = append(problems, secretProblem{c.Company, field, "stored in plaintext"})
+			return nil
+		}
+		plain, err := vault.DecryptString(*value, key)
+		if err == nil {
+			*value = plain
+			return nil
+		}
+		if !repair {
+			problems = append(problems, secretProblem{c.Company, field, "does not decrypt"})
+			return nil
+		}
+		if plain, err := vault.DecryptString(*value, vault.LegacyKey()); err == nil {
+			*value = plain
+			problems = append(problems, secretProblem{c.Company, field, "recovered with the legacy shared key"})
+			return nil
+		}
+		*value = ""
+		problems = append(problems, secretProblem{c.Company, field, "does not decrypt, cleared"})
+		return nil
+	})
+	return problems
+}
+
+func printProblems(ctx *cliContext, problems []secretProblem) {
+	for _, p := range problems {
+		fmt.Fprintf(ctx.Out, "  %s %s: %s\n", p.Company, p.Field, p.Issue)
+	}
+}
+
+func runVerify(ctx *cliContext) error {
+	opened, err := openForCheck(ctx)
+	if err != nil {
+		return err
+	}
+	if opened.sealed != "" {
+		fmt.Fprintf(ctx.Out, "ok: %s authenticated, %d customers\n", opened.sealed, len(opened.clients))
+		return nil
+	}
+
+	problems := checkSecrets(opened.clients, opened.key, false)
+	if len(problems) == 0 {
+		fmt.Fprintf(ctx.Out, "ok: every secret of %d customers decrypts\n", len(opened.clients))
+		return nil
+	}
+	printProblems(ctx, problems)
+	return fmt.Errorf("%d problems found, see `client-man vault repair`", len(problems))
+}
+
+func runRepair(ctx *cliContext) error {
+	opened, err := openForCheck(ctx)
+	if err != nil {
+		return err
+	}
+	if opened.sealed != "" {
+		fmt.Fprintf(ctx.Out, "nothing to repair: %s authenticated\n", opened.sealed)
+		return nil
+	}
+
+	problems := checkSecrets(opened.clients, opened.key, true)
+	if len(problems) == 0 {
+		fmt.Fprintln(ctx.Out, "nothing to repair")
+		return nil
+	}
+	printProblems(ctx, problems)
+
+	out, err := vault.Marshal(opened.env.KDF, opened.key, opened.env.Cipher, opened.clients)
+	if err != nil {
+		return err
+	}
+	return ctx.writeVault(out)
+}
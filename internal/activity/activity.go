@@ -0,0 +1,95 @@
+// Package activity tracks user input for the auto-lock.
+//
+// Fyne'da pencere düzeyinde ortak bir olay kancası yoktur: klavye olayları odaktaki
+// widget'a, tıklamalar en üstteki widget'a gider ve canvas'ın kendi klavye kancaları
+// yalnızca hiçbir şey odakta değilken çalışır. Tracker bu yüzden birkaç yoldan beslenir:
+// canvas olayları (Watch), odaktaki widget'ın değişmesi veya içine yazılması (Poll)
+// ve Tap ile sarılan buton geri çağrıları.
+package activity
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Tracker remembers when the user last did something
+type Tracker struct {
+	last atomic.Int64 // UnixNano
+
+	mu      sync.Mutex
+	focused fyne.Focusable
+	state   [sha256.Size]byte // Odaktaki widget'ın durumu; yazılan metin açık tutulmaz
+}
+
+// NewTracker returns a tracker that counts from now
+func NewTracker() *Tracker {
+	t := &Tracker{}
+	t.Touch()
+	return t
+}
+
+// Touch records activity now
+func (t *Tracker) Touch() {
+	t.last.Store(time.Now().UnixNano())
+}
+
+// Idle returns how long ago the last activity was
+func (t *Tracker) Idle() time.Duration {
+	return time.Since(time.Unix(0, t.last.Load()))
+}
+
+// Tap returns f wrapped so that calling it counts as activity, buton geri çağrıları için
+func (t *Tracker) Tap(f func()) func() {
+	return func() {
+		t.Touch()
+		if f != nil {
+			f()
+		}
+	}
+}
+
+// Watch hooks the key events of c; bunlar yalnızca odakta widget yokken gelir
+func (t *Tracker) Watch(c fyne.Canvas) {
+	c.SetOnTypedKey(func(*fyne.KeyEvent) { t.Touch() })
+	c.SetOnTypedRune(func(rune) { t.Touch() })
+}
+
+// Poll records activity when focus moved or the focused widget changed since the last Poll.
+// Odaktaki bir entry'ye yazmak metni veya imleci değiştirir; olaylar canvas'a hiç uğramasa da fark edilir.
+func (t *Tracker) Poll(c fyne.Canvas) {
+	focused := c.Focused()
+	state := focusState(focused)
+
+	t.mu.Lock()
+	changed := focused != t.focused || state != t.state
+	t.focused, t.state = focused, state
+	t.mu.Unlock()
+
+	if changed {
+		t.Touch()
+	}
+}
+
+// Reset forgets the focused widget, örn. kilitlenince içerik değiştiğinde
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	t.focused, t.state = nil, [sha256.Size]byte{}
+	t.mu.Unlock()
+}
+
+// focusState summarizes what typing changes in a focused widget
+func focusState(f fyne.Focusable) [sha256.Size]byte {
+	switch w := f.(type) {
+	case *widget.Entry:
+		return sha256.Sum256([]byte(fmt.Sprintf("%d:%d:%s\x00%s", w.CursorRow, w.CursorColumn, w.SelectedText(), w.Text)))
+	case *widget.SelectEntry:
+		return focusState(&w.Entry)
+	}
+	return [sha256.Size]byte{}
+}
@@ -0,0 +1,95 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+// idleFor makes t look idle for d
+func idleFor(t *Tracker, d time.Duration) {
+	t.last.Store(time.Now().Add(-d).UnixNano())
+}
+
+func TestTypingInFocusedEntryResetsTimer(t *testing.T) {
+	test.NewTempApp(t)
+	entry := widget.NewEntry()
+	w := test.NewTempWindow(t, container.NewVBox(entry, widget.NewLabel("other")))
+	c := w.Canvas()
+
+	tr := NewTracker()
+	tr.Watch(c)
+	c.Focus(entry)
+	tr.Poll(c)
+
+	idleFor(tr, time.Hour)
+	tr.Poll(c)
+	if tr.Idle() < time.Hour {
+		t.Fatal("poll without input counted as activity")
+	}
+
+	// Odaktaki entry'ye yazılan karakterler canvas'ın OnTypedRune'una gitmez
+	test.Type(entry, "hunter2")
+	if tr.Idle() < time.Hour {
+		t.Fatal("canvas hook saw typing in a focused entry; test no longer covers Poll")
+	}
+	tr.Poll(c)
+	if idle := tr.Idle(); idle > time.Minute {
+		t.Fatalf("typing in a focused entry did not reset the timer, idle %v", idle)
+	}
+}
+
+func TestCursorMoveResetsTimer(t *testing.T) {
+	test.NewTempApp(t)
+	entry := widget.NewEntry()
+	entry.SetText("text")
+	w := test.NewTempWindow(t, entry)
+	c := w.Canvas()
+
+	tr := NewTracker()
+	c.Focus(entry)
+	tr.Poll(c)
+	idleFor(tr, time.Hour)
+
+	entry.CursorColumn = 1
+	tr.Poll(c)
+	if idle := tr.Idle(); idle > time.Minute {
+		t.Fatalf("moving the cursor did not reset the timer, idle %v", idle)
+	}
+}
+
+func TestTypingWithoutFocusResetsTimer(t *testing.T) {
+	test.NewTempApp(t)
+	w := test.NewTempWindow(t, widget.NewLabel("content"))
+
+	tr := NewTracker()
+	tr.Watch(w.Canvas())
+	idleFor(tr, time.Hour)
+
+	test.TypeOnCanvas(w.Canvas(), "a")
+	if idle := tr.Idle(); idle > time.Minute {
+		t.Fatalf("typing on the canvas did not reset the timer, idle %v", idle)
+	}
+}
+
+func TestButtonTapResetsTimer(t *testing.T) {
+	test.NewTempApp(t)
+	tapped := false
+	button := widget.NewButton("Save", nil)
+
+	tr := NewTracker()
+	button.OnTapped = tr.Tap(func() { tapped = true })
+	test.NewTempWindow(t, button)
+	idleFor(tr, time.Hour)
+
+	test.Tap(button)
+	if !tapped {
+		t.Fatal("wrapped callback not called")
+	}
+	if idle := tr.Idle(); idle > time.Minute {
+		t.Fatalf("tapping a button did not reset the timer, idle %v", idle)
+	}
+}
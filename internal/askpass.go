@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"clientinfo/internal/askpass"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Harici terminalde açılan ssh şifreleri SSH_ASKPASS ile bu uygulamadan ister; protokol
+// askpass paketindedir. Şifre panoya, ortam değişkenlerine veya komut satırına girmez.
+
+// sshPasswordPrompt is the prompt OpenSSH shows for the password of user at host
+func sshPasswordPrompt(user, host string) string {
+	return fmt.Sprintf("%s@%s's password:", user, host)
+}
+
+// startAskpass listens for the prompts of one ssh command; istemler kayıtlı değilse kullanıcıya sorulur
+func (s *AppState) startAskpass(passwords map[string]string) (*askpass.Session, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	return askpass.Start(exe, passwords, s.askUser)
+}
+
+// askUser shows an ssh prompt in the app and waits for the answer; iptal edilirse false döner
+func (s *AppState) askUser(prompt string) (string, bool) {
+	type answer struct {
+		text string
+		ok   bool
+	}
+	done := make(chan answer, 1)
+	fyne.Do(func() {
+		if strings.Contains(prompt, "(yes/no") {
+			// Host anahtarı onayı: ssh "yes" veya "no" bekler
+			dialog.ShowConfirm(DialogTitleSSH, prompt, func(ok bool) {
+				if ok {
+					done <- answer{"yes", true}
+				} else {
+					done <- answer{"no", true}
+				}
+			}, s.window)
+			return
+		}
+		entry := widget.NewPasswordEntry()
+		items := []*widget.FormItem{
+			{Widget: widget.NewLabel(prompt)},
+			{Text: SSHAskpassLabel, Widget: entry},
+		}
+		d := dialog.NewForm(DialogTitleSSH, "OK", "Cancel", items, func(ok bool) {
+			done <- answer{entry.Text, ok}
+		}, s.window)
+		d.Show()
+		s.window.Canvas().Focus(entry)
+	})
+	a := <-done
+	return a.text, a.ok
+}
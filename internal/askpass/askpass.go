@@ -0,0 +1,198 @@
+// Package askpass answers the password prompts of an external ssh over SSH_ASKPASS.
+//
+// Uygulama kendi exe'sini askpass programı olarak verir; ssh onu istem metniyle
+// çalıştırınca exe pencere açmadan 127.0.0.1'deki dinleyiciye bağlanır ve cevabı
+// yazdırır. Dinleyicinin adresi ve jetonu yalnızca sahibinin okuyabildiği bir dosyada
+// durur; ortamda ve dolayısıyla terminalin komut satırında sadece dosyanın yolu bulunur.
+// Yardımcı dosyayı okuyunca siler, her jeton tek kullanımlıktır ve uygulama bir sonraki
+// istem için dosyayı yeni jetonla yeniden yazar.
+package askpass
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvVar holds the path of the file with "address token" of the listener that answers one ssh command's prompts
+	EnvVar = "CLIENT_MAN_ASKPASS"
+	// IdleTimeout closes the listener when ssh has not asked anything for this long
+	IdleTimeout = 2 * time.Minute
+	// DialTimeout bounds how long the helper waits for the app
+	DialTimeout = 5 * time.Second
+)
+
+// specFile is the name of the file in the session directory
+const specFile = "spec"
+
+// AskFunc asks the user to answer prompt; iptal edilirse ok false döner
+type AskFunc func(prompt string) (answer string, ok bool)
+
+type request struct {
+	Token  string `json:"token"`
+	Prompt string `json:"prompt"`
+}
+
+type response struct {
+	OK     bool   `json:"ok"`
+	Answer string `json:"answer"`
+}
+
+// Session answers the prompts of one ssh command
+type Session struct {
+	// Env points ssh at the session, "KEY=value" girdileri; jeton içermez
+	Env []string
+
+	ln        *net.TCPListener
+	dir       string
+	token     string
+	passwords map[string]string
+	ask       AskFunc
+	done      chan struct{}
+}
+
+// Start listens for the prompts of one ssh command that runs exe as its askpass program.
+// passwords maps prompts to the passwords answered without asking, her biri bir kez; diğer istemler
+// (host anahtarı onayı, anahtar parolası, tekrar sorulan şifre) ask ile kullanıcıya sorulur.
+func Start(exe string, passwords map[string]string, ask AskFunc) (*Session, error) {
+	// MkdirTemp dizini 0700 açar; dosya yeniden yazılırken başka bir kullanıcı araya giremez
+	dir, err := os.MkdirTemp("", "client-man-askpass-")
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	s := &Session{
+		ln:        ln.(*net.TCPListener),
+		dir:       dir,
+		passwords: passwords,
+		ask:       ask,
+		done:      make(chan struct{}),
+	}
+	if err := s.rotate(); err != nil {
+		ln.Close()
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	s.Env = []string{
+		"SSH_ASKPASS=" + exe,
+		"SSH_ASKPASS_REQUIRE=force",
+		EnvVar + "=" + s.specPath(),
+	}
+	go s.serve()
+	return s, nil
+}
+
+// Close stops answering and removes the spec file
+func (s *Session) Close() {
+	s.ln.Close()
+	<-s.done
+}
+
+func (s *Session) specPath() string {
+	return filepath.Join(s.dir, specFile)
+}
+
+// rotate writes a new single-use token to the spec file
+func (s *Session) rotate() error {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	s.token = hex.EncodeToString(raw)
+	return os.WriteFile(s.specPath(), []byte(s.ln.Addr().String()+" "+s.token), 0o600)
+}
+
+// serve answers prompts one at a time until ssh stays quiet for IdleTimeout
+func (s *Session) serve() {
+	defer close(s.done)
+	defer os.RemoveAll(s.dir)
+	defer s.ln.Close()
+	for {
+		s.ln.SetDeadline(time.Now().Add(IdleTimeout))
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.answer(conn)
+	}
+}
+
+func (s *Session) answer(conn net.Conn) {
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(DialTimeout))
+	var req request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+	if s.token == "" || subtle.ConstantTimeCompare([]byte(req.Token), []byte(s.token)) != 1 {
+		return
+	}
+	// Jeton kullanıldı; ssh'in bir sonraki istemi yeni dosyayı okur. Dosya yazılamazsa
+	// sonraki istemler cevapsız kalır ve ssh şifreyi terminalde sorar.
+	if err := s.rotate(); err != nil {
+		s.token = ""
+	}
+	prompt := strings.TrimSpace(req.Prompt)
+	resp := response{}
+	if password, ok := s.passwords[prompt]; ok {
+		// Kayıtlı şifre bir kez verilir; ssh yine sorarsa şifre yanlıştır ve kullanıcı yazar
+		delete(s.passwords, prompt)
+		resp = response{OK: true, Answer: password}
+	} else {
+		resp.Answer, resp.OK = s.ask(prompt)
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// Run is the askpass program: it reads and deletes the spec file at path, asks the app to answer
+// prompt and prints the answer to out. Cevap alınamazsa sıfırdan farklı döner; ssh bunu iptal olarak görür.
+func Run(path, prompt string, out io.Writer) int {
+	answer, err := query(path, prompt)
+	if err != nil {
+		return 1
+	}
+	fmt.Fprintln(out, answer)
+	return 0
+}
+
+func query(path, prompt string) (string, error) {
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	os.Remove(path)
+	addr, token, ok := strings.Cut(string(spec), " ")
+	if !ok {
+		return "", errors.New("malformed askpass spec")
+	}
+	conn, err := net.DialTimeout("tcp", addr, DialTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if err := json.NewEncoder(conn).Encode(request{Token: token, Prompt: prompt}); err != nil {
+		return "", err
+	}
+	var resp response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return "", err
+	}
+	if !resp.OK {
+		return "", errors.New("prompt cancelled")
+	}
+	return resp.Answer, nil
+}
@@ -0,0 +1,91 @@
+package askpass
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// specPath returns the spec file the env of s points at
+func specPath(t *testing.T, s *Session) string {
+	t.Helper()
+	for _, kv := range s.Env {
+		if path, ok := strings.CutPrefix(kv, EnvVar+"="); ok {
+			return path
+		}
+	}
+	t.Fatalf("%s missing from %q", EnvVar, s.Env)
+	return ""
+}
+
+func TestAnswersFromSpecFile(t *testing.T) {
+	const prompt = "ops@10.0.0.1's password:"
+	var asked []string
+	s, err := Start("/usr/bin/client-man", map[string]string{prompt: "s3cret"}, func(p string) (string, bool) {
+		asked = append(asked, p)
+		return "typed", true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	path := specPath(t, s)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Windows'ta izinler ACL ile tutulur; Mode yalnızca salt okunurluğu gösterir
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0o077 != 0 {
+		t.Fatalf("spec file mode %o, want no access for group and others", perm)
+	}
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, token, _ := strings.Cut(string(first), " ")
+	for _, kv := range s.Env {
+		if strings.Contains(kv, token) {
+			t.Fatalf("env entry %q holds the token", kv)
+		}
+	}
+
+	var out bytes.Buffer
+	if code := Run(path, prompt, &out); code != 0 || out.String() != "s3cret\n" {
+		t.Fatalf("Run = %d %q, want the stored password", code, out.String())
+	}
+	// Kayıtlı şifre bir kez verilir; ikinci istem kullanıcıya gider
+	out.Reset()
+	if code := Run(path, prompt, &out); code != 0 || out.String() != "typed\n" {
+		t.Fatalf("second Run = %d %q, want the typed answer", code, out.String())
+	}
+	if len(asked) != 1 || asked[0] != prompt {
+		t.Fatalf("asked %q", asked)
+	}
+
+	// Kullanılmış jeton tekrar kabul edilmez
+	if err := os.WriteFile(path, first, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code := Run(path, prompt, &out); code == 0 {
+		t.Fatal("a used token was answered")
+	}
+}
+
+func TestRunDeletesSpecFile(t *testing.T) {
+	s, err := Start("/usr/bin/client-man", nil, func(string) (string, bool) { return "", false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := specPath(t, s)
+	var out bytes.Buffer
+	if code := Run(path, "Enter passphrase:", &out); code == 0 {
+		t.Fatal("a cancelled prompt was answered")
+	}
+	s.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("spec file left behind after Close: %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"clientinfo/internal/activity"
+	"clientinfo/internal/backup"
+	"clientinfo/internal/vault"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// autoLockOptions idle süresi seçenekleri (dakika, 0 = kapalı)
+var autoLockOptions = []int{0, 1, 5, 10, 15, 30, 60}
+
+// userActivity is the auto-lock's idle timer (widget'lar AppState'e erişemediği için paket düzeyinde).
+// Butonlar geri çağrılarını userActivity.Tap ile sarar; odaktaki entry'ye yazmak Poll ile fark edilir.
+var userActivity = activity.NewTracker()
+
+// activityMonitor is a transparent layer behind the main content that reports mouse activity.
+// Fyne olayları en üstteki uygun nesneye gider; bu katman içerikte karşılanmayan
+// hareket, tıklama ve kaydırmaları yakalar.
+type activityMonitor struct {
+	widget.BaseWidget
+	onActivity func()
+}
+
+func newActivityMonitor(onActivity func()) *activityMonitor {
+	m := &activityMonitor{onActivity: onActivity}
+	m.ExtendBaseWidget(m)
+	return m
+}
+
+func (m *activityMonitor) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+
+func (m *activityMonitor) MouseIn(*desktop.MouseEvent)      { m.onActivity() }
+func (m *activityMonitor) MouseMoved(*desktop.MouseEvent)   { m.onActivity() }
+func (m *activityMonitor) MouseOut()                        { m.onActivity() }
+func (m *activityMonitor) Tapped(*fyne.PointEvent)          { m.onActivity() }
+func (m *activityMonitor) TappedSecondary(*fyne.PointEvent) { m.onActivity() }
+func (m *activityMonitor) Scrolled(*fyne.ScrollEvent)       { m.onActivity() }
+
+// touchActivity records user activity and postpones the auto-lock
+func (s *AppState) touchActivity() {
+	userActivity.Touch()
+}
+
+// autoLockTimeout returns the configured idle timeout, 0 if auto-lock is disabled
+func (s *AppState) autoLockTimeout() time.Duration {
+	minutes := s.myApp.Preferences().IntWithFallback(PrefAutoLockMinutes, DefaultAutoLockMinutes)
+	return time.Duration(minutes) * time.Minute
+}
+
+// watchActivity wraps the main content with the activity monitor and hooks canvas key events.
+// Canvas kancaları yalnızca odakta widget yokken çalışır; odaktaki widget'a yazmayı
+// auto-lock kontrolündeki Poll yakalar.
+func (s *AppState) watchActivity(content fyne.CanvasObject) fyne.CanvasObject {
+	userActivity.Watch(s.window.Canvas())
+	userActivity.Reset()
+
+	s.touchActivity()
+	s.startAutoLock()
+	return container.NewStack(newActivityMonitor(s.touchActivity), content)
+}
+
+// startAutoLock starts the idle checker once for the lifetime of the window
+func (s *AppState) startAutoLock() {
+	if s.autoLockStarted {
+		return
+	}
+	s.autoLockStarted = true
+
+	go func() {
+		ticker := time.NewTicker(AutoLockCheckInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(func() {
+				timeout := s.autoLockTimeout()
+				if s.vaultKey == nil || timeout <= 0 {
+					return
+				}
+				userActivity.Poll(s.window.Canvas())
+				if userActivity.Idle() >= timeout {
+					s.lockVault()
+				}
+			})
+		}
+	}()
+}
+
+// lockVault drops all decrypted data and key material and shows the unlock screen.
+// Açık/kapalı firma ve aktif tab durumu (expandedClients, expandedApps, activeTabIndex)
+// korunur, kilit açıldığında arayüz aynı haliyle geri gelir.
+func (s *AppState) lockVault() {
+	if s.vaultKey == nil {
+		return
+	}
+
+	// SSH oturumları ve tüneller kilitli vault'un arkasında açık kalmasın
+	s.closeTerminals()
+	s.stopTunnels()
+
+	// Açık dialog ve menüler şifre gösteriyor olabilir
+	overlays := s.window.Canvas().Overlays()
+	for top := overlays.Top(); top != nil; top = overlays.Top() {
+		overlays.Remove(top)
+	}
+
+	for i := range s.clients {
+		s.clients[i] = Client{}
+	}
+	for i := range s.filteredClients {
+		s.filteredClients[i] = Client{}
+	}
+	for i := range s.syncedClients {
+		s.syncedClients[i] = Client{}
+	}
+	s.clients = nil
+	s.filteredClients = nil
+	s.syncedClients = nil
+
+	// Store da kendi çözülmüş kopyasını ve anahtarını tutar
+	if s.store != nil {
+		s.store.Close()
+		s.store = nil
+	}
+	if s.history != nil {
+		s.history.Clear()
+		s.history = nil
+	}
+	secretClipboard.clearNow()
+
+	for i := range s.vaultKey {
+		s.vaultKey[i] = 0
+	}
+	s.vaultKey = nil
+
+	userActivity.Reset()
+	s.showUnlockScreen(s.currentFile, vault.StatusProtected, nil)
+}
+
+// showSecuritySettings auto-lock, pano temizleme ve yedekleme ayarları dialogunu gösterir
+func (s *AppState) showSecuritySettings() {
+	prefs := s.myApp.Preferences()
+
+	autoLockSelect := newDurationSelect(autoLockOptions, autoLockLabel,
+		prefs.IntWithFallback(PrefAutoLockMinutes, DefaultAutoLockMinutes))
+	clipboardSelect := newDurationSelect(clipboardOptions, clipboardLabel,
+		prefs.IntWithFallback(PrefClipboardClearSeconds, DefaultClipboardClearSeconds))
+
+	backupSelect := newDurationSelect(backupIntervalOptions, backupIntervalLabel,
+		prefs.IntWithFallback(PrefBackupIntervalMinutes, DefaultBackupIntervalMinutes))
+	keepLastSelect := newDurationSelect(backupKeepLastOptions, keepLastLabel,
+		prefs.IntWithFallback(PrefBackupKeepLast, backup.DefaultPolicy.KeepLast))
+	keepDailySelect := newDurationSelect(backupDailyOptions, keepDailyLabel,
+		prefs.IntWithFallback(PrefBackupKeepDaily, backup.DefaultPolicy.KeepDaily))
+	keepWeeklySelect := newDurationSelect(backupWeeklyOptions, keepWeeklyLabel,
+		prefs.IntWithFallback(PrefBackupKeepWeekly, backup.DefaultPolicy.KeepWeekly))
+
+	form := container.NewVBox(
+		widget.NewLabel(AutoLockSettingsInfo),
+		autoLockSelect,
+		widget.NewLabel(ClipboardSettingsInfo),
+		clipboardSelect,
+		widget.NewSeparator(),
+		widget.NewLabel(BackupSettingsInterval),
+		backupSelect,
+		widget.NewLabel(BackupSettingsRetention),
+		container.NewGridWithColumns(3, keepLastSelect, keepDailySelect, keepWeeklySelect),
+	)
+
+	dialog.ShowCustomConfirm(SecuritySettingsTitle, "Save", "Cancel", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		prefs.SetInt(PrefAutoLockMinutes, autoLockOptions[autoLockSelect.SelectedIndex()])
+		prefs.SetInt(PrefClipboardClearSeconds, clipboardOptions[clipboardSelect.SelectedIndex()])
+		prefs.SetInt(PrefBackupIntervalMinutes, backupIntervalOptions[backupSelect.SelectedIndex()])
+		prefs.SetInt(PrefBackupKeepLast, backupKeepLastOptions[keepLastSelect.SelectedIndex()])
+		prefs.SetInt(PrefBackupKeepDaily, backupDailyOptions[keepDailySelect.SelectedIndex()])
+		prefs.SetInt(PrefBackupKeepWeekly, backupWeeklyOptions[keepWeeklySelect.SelectedIndex()])
+		s.touchActivity()
+	}, s.window)
+}
+
+// newDurationSelect builds a select over options, preselecting current (listede yoksa ilk seçenek)
+func newDurationSelect(options []int, label func(int) string, current int) *widget.Select {
+	labels := make([]string, len(options))
+	selected := 0
+	for i, v := range options {
+		labels[i] = label(v)
+		if v == current {
+			selected = i
+		}
+	}
+	sel := widget.NewSelect(labels, nil)
+	sel.SetSelectedIndex(selected)
+	return sel
+}
+
+func autoLockLabel(minutes int) string {
+	switch minutes {
+	case 0:
+		return "Never"
+	case 1:
+		return "1 minute"
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+func clipboardLabel(seconds int) string {
+	if seconds == 0 {
+		return "Never"
+	}
+	return fmt.Sprintf("%d seconds", seconds)
+}
+
+func backupIntervalLabel(minutes int) string {
+	switch minutes {
+	case 0:
+		return "Every save"
+	case 60:
+		return "At most hourly"
+	case 24 * 60:
+		return "At most daily"
+	}
+	return fmt.Sprintf("At most every %d minutes", minutes)
+}
+
+func keepLastLabel(n int) string {
+	return fmt.Sprintf("%d newest", n)
+}
+
+func keepDailyLabel(days int) string {
+	return fmt.Sprintf("%d days", days)
+}
+
+func keepWeeklyLabel(weeks int) string {
+	return fmt.Sprintf("%d weeks", weeks)
+}
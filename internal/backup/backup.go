@@ -0,0 +1,201 @@
+// Package backup keeps rolling, timestamped snapshots of a vault file.
+//
+// Snapshot'lar vault dosyasının bayt bayt kopyasıdır, yani vault ile aynı
+// anahtarla şifrelidir. Dosyanın yanındaki "backups" dizininde
+// <dosya>.<zaman>.backup adıyla tutulur ve Policy'ye göre budanır.
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"clientinfo/internal/store"
+)
+
+const (
+	// DirName is the directory next to the vault that holds its snapshots
+	DirName = "backups"
+
+	timeFormat = "20060102-150405.000"
+	suffix     = ".backup"
+)
+
+// Policy decides which snapshots survive pruning. Bir snapshot herhangi bir
+// kurala uyuyorsa korunur.
+type Policy struct {
+	KeepLast   int // En yeni N snapshot
+	KeepDaily  int // Son N günün her birinden en yeni snapshot
+	KeepWeekly int // Son N haftanın her birinden en yeni snapshot
+}
+
+// DefaultPolicy is used when no preference is set (ve CLI'de)
+var DefaultPolicy = Policy{KeepLast: 10, KeepDaily: 7, KeepWeekly: 4}
+
+// Snapshot is one backup file
+type Snapshot struct {
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// Manager creates, lists, prunes and restores the snapshots of one vault file
+type Manager struct {
+	vaultPath string
+	dir       string
+	policy    Policy
+}
+
+// New returns a manager for the vault at vaultPath
+func New(vaultPath string, policy Policy) *Manager {
+	return &Manager{
+		vaultPath: vaultPath,
+		dir:       filepath.Join(filepath.Dir(vaultPath), DirName),
+		policy:    policy,
+	}
+}
+
+// Dir returns the directory the snapshots are stored in
+func (m *Manager) Dir() string {
+	return m.dir
+}
+
+// Snapshot copies the current vault file into a new snapshot and prunes old ones
+func (m *Manager) Snapshot() (Snapshot, error) {
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return Snapshot{}, err
+	}
+
+	now := time.Now()
+	path := m.snapshotPath(now)
+	if err := store.CopyFile(m.vaultPath, path, store.VaultPerm); err != nil {
+		return Snapshot{}, fmt.Errorf("backup oluşturulamadı: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snap := Snapshot{Path: path, Time: now, Size: info.Size()}
+	if err := m.Prune(); err != nil {
+		return snap, err
+	}
+	return snap, nil
+}
+
+// SnapshotIfDue takes a snapshot unless the newest one is younger than interval.
+// interval 0 her kayıtta snapshot alır.
+func (m *Manager) SnapshotIfDue(interval time.Duration) error {
+	if interval > 0 {
+		snaps, err := m.List()
+		if err != nil {
+			return err
+		}
+		if len(snaps) > 0 && time.Since(snaps[0].Time) < interval {
+			return nil
+		}
+	}
+	_, err := m.Snapshot()
+	return err
+}
+
+// List returns the snapshots of the vault, newest first
+func (m *Manager) List() ([]Snapshot, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	prefix := filepath.Base(m.vaultPath) + "."
+	var snaps []Snapshot
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix)
+		t, err := time.ParseInLocation(timeFormat, stamp, time.Local)
+		if err != nil {
+			// Başka bir dosyanın ya da elle kopyalanmış bir yedeğin adı olabilir
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		snaps = append(snaps, Snapshot{Path: filepath.Join(m.dir, name), Time: t, Size: info.Size()})
+	}
+
+	sort.Slice(snaps, func(i, j int) bool {
+		return snaps[i].Time.After(snaps[j].Time)
+	})
+	return snaps, nil
+}
+
+// Prune deletes the snapshots that the policy does not keep
+func (m *Manager) Prune() error {
+	snaps, err := m.List()
+	if err != nil {
+		return err
+	}
+	keep := m.policy.keep(snaps)
+	for i, snap := range snaps {
+		if !keep[i] {
+			if err := os.Remove(snap.Path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// keep marks the snapshots (newest first) retained by the policy
+func (p Policy) keep(snaps []Snapshot) []bool {
+	keep := make([]bool, len(snaps))
+	for i := 0; i < len(snaps) && i < p.KeepLast; i++ {
+		keep[i] = true
+	}
+
+	days := map[string]bool{}
+	weeks := map[string]bool{}
+	for i, snap := range snaps {
+		day := snap.Time.Format("2006-01-02")
+		if !days[day] && len(days) < p.KeepDaily {
+			days[day] = true
+			keep[i] = true
+		}
+		year, week := snap.Time.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%02d", year, week)
+		if !weeks[weekKey] && len(weeks) < p.KeepWeekly {
+			weeks[weekKey] = true
+			keep[i] = true
+		}
+	}
+	return keep
+}
+
+// Restore replaces the vault file with snap. Mevcut dosyanın önce bir snapshot'ı
+// alınır, böylece geri yükleme de geri alınabilir.
+func (m *Manager) Restore(snap Snapshot) error {
+	// Yeni snapshot budamayı tetikler; geri yüklenecek dosya önce okunur
+	data, err := os.ReadFile(snap.Path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(m.vaultPath); err == nil {
+		if _, err := m.Snapshot(); err != nil {
+			return err
+		}
+	}
+	return store.WriteFile(m.vaultPath, data, store.VaultPerm)
+}
+
+func (m *Manager) snapshotPath(t time.Time) string {
+	return filepath.Join(m.dir, fmt.Sprintf("%s.%s%s", filepath.Base(m.vaultPath), t.Format(timeFormat), suffix))
+}
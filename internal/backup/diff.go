@@ -0,0 +1,101 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+
+	"clientinfo/internal/model"
+)
+
+// ChangeKind describes how a customer differs between the current data and a snapshot
+type ChangeKind int
+
+const (
+	OnlyInBackup  ChangeKind = iota // Geri yüklenirse eklenir
+	OnlyInCurrent                   // Geri yüklenirse kaybolur
+	Changed                         // İki tarafta da var, alanları farklı
+)
+
+// Change is one customer level difference. Fields sadece alan yollarını
+// içerir (örn. "apps[0].app_server_pass"), değerleri asla içermez.
+type Change struct {
+	Company string
+	Kind    ChangeKind
+	Fields  []string
+}
+
+// Diff compares customers by ID. ID'si olmayan (eski sürümde alınmış) yedek
+// kayıtları firma adıyla eşleşir; ID'ler kullanıcı verisi olmadığından alan
+// farkı olarak gösterilmez.
+func Diff(current, snapshot []model.Client) []Change {
+	matched := make([]bool, len(snapshot))
+
+	var changes []Change
+	for _, c := range current {
+		j := match(c, snapshot, matched)
+		if j < 0 {
+			changes = append(changes, Change{Company: c.Company, Kind: OnlyInCurrent})
+			continue
+		}
+		matched[j] = true
+		if fields := model.DiffPaths(withoutIDs(c), withoutIDs(snapshot[j])); len(fields) > 0 {
+			changes = append(changes, Change{Company: c.Company, Kind: Changed, Fields: fields})
+		}
+	}
+	for j, s := range snapshot {
+		if !matched[j] {
+			changes = append(changes, Change{Company: s.Company, Kind: OnlyInBackup})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Company < changes[j].Company
+	})
+	return changes
+}
+
+// match returns the index of the unmatched snapshot client that c corresponds to, -1 if none
+func match(c model.Client, snapshot []model.Client, matched []bool) int {
+	if j := model.IndexByID(snapshot, c.ID); j >= 0 && !matched[j] {
+		return j
+	}
+	for j := range snapshot {
+		if !matched[j] && snapshot[j].ID == "" && snapshot[j].Company == c.Company {
+			return j
+		}
+	}
+	return -1
+}
+
+// withoutIDs returns a copy of c with the client, app, tunnel and bastion IDs cleared.
+// Bastion'a yapılan başvurular (ortamın Bastion'ı, bastion'ın Via'sı) sıra numarasıyla
+// değiştirilir; ID'ler yeniden üretilmiş olsa da aynı bağlantı değişiklik sayılmaz.
+func withoutIDs(c model.Client) model.Client {
+	refs := map[string]string{"": ""}
+	for i, b := range c.Bastions {
+		refs[b.ID] = fmt.Sprintf("#%d", i)
+	}
+	ref := func(id string) string {
+		if r, ok := refs[id]; ok {
+			return r
+		}
+		return id
+	}
+
+	c.ID = ""
+	c.Apps = append([]model.AppInfo(nil), c.Apps...)
+	for i := range c.Apps {
+		c.Apps[i].ID = ""
+		c.Apps[i].Bastion = ref(c.Apps[i].Bastion)
+		c.Apps[i].Tunnels = append([]model.Tunnel(nil), c.Apps[i].Tunnels...)
+		for j := range c.Apps[i].Tunnels {
+			c.Apps[i].Tunnels[j].ID = ""
+		}
+	}
+	c.Bastions = append([]model.Bastion(nil), c.Bastions...)
+	for i := range c.Bastions {
+		c.Bastions[i].ID = ""
+		c.Bastions[i].Via = ref(c.Bastions[i].Via)
+	}
+	return c
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"clientinfo/internal/backup"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+var (
+	// backupIntervalOptions kayıtlar arası en kısa snapshot aralığı (dakika, 0 = her kayıtta)
+	backupIntervalOptions = []int{0, 15, 60, 24 * 60}
+	backupKeepLastOptions = []int{5, 10, 20, 50}
+	backupDailyOptions    = []int{0, 7, 14, 30}
+	backupWeeklyOptions   = []int{0, 4, 8, 12, 52}
+)
+
+// backupManager returns the snapshot manager for the current vault with the configured retention
+func (s *AppState) backupManager() *backup.Manager {
+	prefs := s.myApp.Preferences()
+	policy := backup.Policy{
+		KeepLast:   prefs.IntWithFallback(PrefBackupKeepLast, backup.DefaultPolicy.KeepLast),
+		KeepDaily:  prefs.IntWithFallback(PrefBackupKeepDaily, backup.DefaultPolicy.KeepDaily),
+		KeepWeekly: prefs.IntWithFallback(PrefBackupKeepWeekly, backup.DefaultPolicy.KeepWeekly),
+	}
+	return backup.New(s.currentFile, policy)
+}
+
+// backupInterval returns the minimum time between snapshots, 0 for every save
+func (s *AppState) backupInterval() time.Duration {
+	minutes := s.myApp.Preferences().IntWithFallback(PrefBackupIntervalMinutes, DefaultBackupIntervalMinutes)
+	return time.Duration(minutes) * time.Minute
+}
+
+// backupEntry is a snapshot decrypted with the open vault's key for the restore list
+type backupEntry struct {
+	snap    backup.Snapshot
+	clients []Client
+	err     error // Okunamadıysa neden
+}
+
+func (e backupEntry) label() string {
+	stamp := e.snap.Time.Format("2006-01-02 15:04:05")
+	switch {
+	case errors.Is(e.err, vault.ErrWrongPassword):
+		return fmt.Sprintf("%s — %s", stamp, BackupOldKey)
+	case e.err != nil:
+		return fmt.Sprintf("%s — %s", stamp, BackupUnreadable)
+	}
+	return fmt.Sprintf("%s — %d customers", stamp, len(e.clients))
+}
+
+// loadBackupEntries lists the snapshots of the current vault and opens each with the current key
+func (s *AppState) loadBackupEntries() ([]backupEntry, error) {
+	snaps, err := s.backupManager().List()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]backupEntry, len(snaps))
+	for i, snap := range snaps {
+		entries[i].snap = snap
+		// Snapshot vault ile aynı backend'le açılır (.backup uzantısına rağmen)
+		st, err := store.OpenAs(s.currentFile, snap.Path)
+		if err != nil {
+			entries[i].err = err
+			continue
+		}
+		unlocked, err := st.Unlock(&vault.MigrationContext{Key: s.vaultKey})
+		st.Close()
+		if err != nil {
+			entries[i].err = err
+			continue
+		}
+		entries[i].clients = unlocked.Clients
+	}
+	return entries, nil
+}
+
+// describeDiff renders the differences between the current data and a snapshot
+func describeDiff(changes []backup.Change) string {
+	if len(changes) == 0 {
+		return RestoreIdentical
+	}
+	var b strings.Builder
+	for _, c := range changes {
+		switch c.Kind {
+		case backup.OnlyInBackup:
+			fmt.Fprintf(&b, "+ %s (only in backup)\n", c.Company)
+		case backup.OnlyInCurrent:
+			fmt.Fprintf(&b, "− %s (not in backup, removed on restore)\n", c.Company)
+		case backup.Changed:
+			fmt.Fprintf(&b, "~ %s: %s\n", c.Company, strings.Join(c.Fields, ", "))
+		}
+	}
+	return b.String()
+}
+
+// showRestoreBackup lists the snapshots with customer counts and a diff against the current data
+func (s *AppState) showRestoreBackup() {
+	entries, err := s.loadBackupEntries()
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	if len(entries) == 0 {
+		dialog.ShowInformation(RestoreTitle, RestoreNoBackups, s.window)
+		return
+	}
+
+	diffLabel := widget.NewLabel(RestoreSelectHint)
+	diffLabel.Wrapping = fyne.TextWrapWord
+
+	selected := -1
+	var d dialog.Dialog
+
+	restoreBtn := widget.NewButton(RestoreButton, userActivity.Tap(func() {
+		if selected < 0 {
+			return
+		}
+		entry := entries[selected]
+		msg := fmt.Sprintf(RestoreConfirm, entry.snap.Time.Format("2006-01-02 15:04:05"))
+		dialog.ShowConfirm(RestoreTitle, msg, func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := s.restoreBackup(entry.snap); err != nil {
+				dialog.ShowError(err, s.window)
+				return
+			}
+			d.Hide()
+			s.showMainUI()
+			dialog.ShowInformation(DialogTitleSuccess, DialogMsgRestored, s.window)
+		}, s.window)
+	}))
+	restoreBtn.Importance = widget.HighImportance
+	restoreBtn.Disable()
+
+	list := widget.NewList(
+		func() int { return len(entries) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(entries[id].label())
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		entry := entries[id]
+		if entry.err != nil {
+			restoreBtn.Disable()
+			if errors.Is(entry.err, vault.ErrWrongPassword) {
+				diffLabel.SetText(RestoreOldKeyHint)
+			} else {
+				diffLabel.SetText(entry.err.Error())
+			}
+			return
+		}
+		restoreBtn.Enable()
+		diffLabel.SetText(describeDiff(backup.Diff(s.clients, entry.clients)))
+	}
+
+	split := container.NewHSplit(list, container.NewVScroll(diffLabel))
+	split.Offset = 0.4
+	content := container.NewBorder(nil, container.NewHBox(restoreBtn), nil, nil, split)
+
+	d = dialog.NewCustom(RestoreTitle, "Close", content, s.window)
+	d.Resize(fyne.NewSize(760, 460))
+	d.Show()
+}
+
+// restoreBackup replaces the vault file with snap and reloads it with the current key
+func (s *AppState) restoreBackup(snap backup.Snapshot) error {
+	// Açık veritabanı bağlantısı dosyanın değiştirilmesini engellemesin
+	if s.store != nil {
+		s.store.Close()
+	}
+	restoreErr := s.backupManager().Restore(snap)
+
+	// Geri yükleme başarısız olsa da store yeniden açılır.
+	// Anahtar aynı kalır ama setVault yeni bir kopya alır; eskisi bellekten silinir
+	previous := s.vaultKey
+	if err := s.loadClientsWithKey(s.currentFile, append([]byte(nil), previous...)); err != nil {
+		return err
+	}
+	for i := range previous {
+		previous[i] = 0
+	}
+	return restoreErr
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/sshclient"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshRoute is the way to an environment's app server; bastion'lar ve SSH params'taki -J sunucuları sırayla
+type sshRoute struct {
+	hops     []routeHop
+	target   sshclient.Target
+	hostKeys []string // Ortamın güvenilen anahtarları
+}
+
+// routeHop is one jump host of an sshRoute
+type routeHop struct {
+	name      string // Bastion adı; SSH params'tan gelen sunucuda boş
+	bastionID string // Host anahtarı bu bastion'a kaydedilir; boşsa ortama
+	target    sshclient.Target
+	hostKeys  []string
+}
+
+// hostKeyCheckFunc returns the host key check of one hop of a route; bastionID boşsa anahtar ortama kaydedilir
+type hostKeyCheckFunc func(addr string, lines []string, bastionID string) (ssh.HostKeyCallback, []string)
+
+// dialTarget returns the route's target with jumps, her atlamanın anahtarı kendi adresiyle ve kendi kaydında doğrulanır
+func (r sshRoute) dialTarget(check hostKeyCheckFunc) sshclient.Target {
+	target := r.target
+	target.Jumps = nil
+	for _, hop := range r.hops {
+		jump := hop.target
+		jump.HostKeyCallback, jump.HostKeyAlgorithms = check(jump.Addr(), hop.hostKeys, hop.bastionID)
+		target.Jumps = append(target.Jumps, jump)
+	}
+	target.HostKeyCallback, target.HostKeyAlgorithms = check(target.Addr(), r.hostKeys, "")
+	return target
+}
+
+// stripFallback returns "" for the "—" shown in empty fields; boş alan düzenlenip kaydedilince "—" yazılmış olabilir
+func stripFallback(value string) string {
+	if strings.TrimSpace(value) == fallback("") {
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// bastionTarget returns the SSH connection of a bastion
+func bastionTarget(b Bastion) sshclient.Target {
+	target := sshclient.Target{
+		Host:     stripFallback(b.Host),
+		User:     stripFallback(b.User),
+		Password: b.Password,
+	}
+	if file := stripFallback(b.KeyFile); file != "" {
+		target.KeyFiles = []string{file}
+	}
+	return target
+}
+
+// bastionJump returns the bastion as a "user@host[:port]" hop of ssh -J
+func bastionJump(b Bastion) (string, error) {
+	hop := stripFallback(b.Host)
+	if user := stripFallback(b.User); user != "" {
+		hop = user + "@" + hop
+	}
+	if _, _, _, err := sshclient.SplitJumpHost(hop); err != nil {
+		return "", fmt.Errorf("%s: %w", bastionLabel(b), err)
+	}
+	return hop, nil
+}
+
+// bastionLabel is the name of a bastion in lists and diagrams
+func bastionLabel(b Bastion) string {
+	if name := stripFallback(b.Name); name != "" {
+		return name
+	}
+	if host := stripFallback(b.Host); host != "" {
+		return host
+	}
+	return BastionUnnamed
+}
+
+// appRoute returns the hops and the target of an environment's app server.
+// Önce ortamın bastion zinciri, sonra SSH params'taki -J sunucuları geçilir.
+func appRoute(c Client, app AppInfo) (sshRoute, error) {
+	target, err := appTarget(app)
+	if err != nil {
+		return sshRoute{}, err
+	}
+	params, _ := appSSHParams(app) // appTarget doğruladı
+	chain, err := c.BastionChain(app.Bastion)
+	if err != nil {
+		return sshRoute{}, err
+	}
+
+	route := sshRoute{target: target, hostKeys: app.HostKeys}
+	for _, b := range chain {
+		route.hops = append(route.hops, routeHop{name: bastionLabel(b), bastionID: b.ID, target: bastionTarget(b), hostKeys: b.HostKeys})
+	}
+	for _, jump := range params.JumpHosts {
+		user, host, port, _ := sshclient.SplitJumpHost(jump)
+		if user == "" {
+			// ssh yerel kullanıcıyı dener; burada ortamın kullanıcısı daha olası
+			user = target.User
+		}
+		// -J sunucularının anahtarları ortamda, kendi adresleriyle tutulur
+		route.hops = append(route.hops, routeHop{target: sshclient.Target{Host: host, Port: port, User: user}, hostKeys: app.HostKeys})
+	}
+	return route, nil
+}
+
+// createBastionsTab lists the bastions of a client with their forms
+func (s *AppState) createBastionsTab(client Client) fyne.CanvasObject {
+	list := container.NewVBox()
+	for _, b := range client.Bastions {
+		bastionID := b.ID
+		onBastion := func(set func(*Bastion, string)) func(*Client, string) {
+			return func(c *Client, v string) {
+				if bb := c.BastionByID(bastionID); bb != nil {
+					set(bb, v)
+				}
+			}
+		}
+
+		hostItem := s.createCustomTextBoxItem(FormLabelBastionHost, fallback(b.Host), false, false, false, client.ID, onBastion(func(bb *Bastion, v string) { bb.Host = v }))
+		hostItem.Widget.(*CustomTextBox).SetValidator(validateBastionHost)
+		form := widget.NewForm(
+			s.createCustomTextBoxItem(FormLabelBastionName, fallback(b.Name), false, false, false, client.ID, onBastion(func(bb *Bastion, v string) { bb.Name = v })),
+			hostItem,
+			s.createCustomTextBoxItem(FormLabelBastionUser, fallback(b.User), false, false, false, client.ID, onBastion(func(bb *Bastion, v string) { bb.User = v })),
+			s.createCustomTextBoxItem(FormLabelBastionPass, fallback(b.Password), true, false, false, client.ID, onBastion(func(bb *Bastion, v string) { bb.Password = v })),
+			s.createCustomTextBoxItem(FormLabelBastionKeyFile, fallback(b.KeyFile), false, false, false, client.ID, onBastion(func(bb *Bastion, v string) { bb.KeyFile = v })),
+			s.bastionSelectItem(FormLabelBastionVia, client, b.Via, bastionID, func(c *Client, id string) {
+				if bb := c.BastionByID(bastionID); bb != nil {
+					bb.Via = id
+				}
+			}),
+			s.hostKeyFormItem(client.ID, b.HostKeys, func(c *Client) {
+				if bb := c.BastionByID(bastionID); bb != nil {
+					bb.HostKeys = nil
+				}
+			}),
+			s.createCustomTextBoxItem("Note", fallback(b.Notes), false, true, false, client.ID, onBastion(func(bb *Bastion, v string) { bb.Notes = v })),
+		)
+
+		// Rozet bastion'ı doğrudan kullanan ortam sayısını gösterir
+		used := 0
+		for _, app := range client.Apps {
+			if app.Bastion == bastionID {
+				used++
+			}
+		}
+		deleteBtn := NewIconButtonSimple(theme.DeleteIcon(), "", fyne.NewSize(18, 18), BastionDeleteTip, func() {
+			s.deleteBastion(client.ID, bastionID)
+		})
+		title := bastionLabel(b)
+		if host := stripFallback(b.Host); host != "" && host != title {
+			title += " (" + host + ")"
+		}
+		header := newAccordionHeader(title, newBadge(fmt.Sprintf("%d", used), colorBadgeBlue), []fyne.CanvasObject{deleteBtn}, nil)
+		item := newExpandableItem(header, wrapWithBlueBackground(newBorderedContainer(form, colorDarkcyan, 5)))
+
+		// Açık/kapalı durumu ortamlarla aynı haritada, ID ile tutulur
+		if s.expandedApps[bastionID] {
+			item.SetExpanded(true)
+		}
+		originalOnTap := header.onTap
+		header.onTap = func() {
+			if originalOnTap != nil {
+				originalOnTap()
+			}
+			s.expandedApps[bastionID] = item.IsExpanded()
+		}
+
+		list.Add(container.NewMax(item))
+		list.Add(widget.NewSeparator())
+	}
+
+	addBtn := NewIconButtonSimple(theme.ContentAddIcon(), BastionAddText, fyne.NewSize(24, 24), BastionAddTip, func() {
+		s.addBastion(client.ID)
+	})
+	return container.NewBorder(addBtn, nil, nil, nil, list)
+}
+
+// validateBastionHost is the validator of a bastion's Host box
+func validateBastionHost(text string) error {
+	host := stripFallback(text)
+	if host == "" {
+		return nil
+	}
+	user, _, _, err := sshclient.SplitJumpHost(host)
+	if err != nil {
+		return err
+	}
+	if user != "" {
+		return fmt.Errorf(DialogMsgBastionHostUser, user)
+	}
+	return nil
+}
+
+// bastionSelectItem lets the user pick the bastion to connect through.
+// exclude bastion'ın kendisidir (Via seçerken); döngü oluşturan seçim kaydedilmez.
+func (s *AppState) bastionSelectItem(label string, client Client, current, exclude string, set func(*Client, string)) *widget.FormItem {
+	ids := map[string]string{BastionDirect: ""}
+	options := []string{BastionDirect}
+	selected := BastionDirect
+	for _, b := range client.Bastions {
+		if b.ID == exclude {
+			continue
+		}
+		option := bastionLabel(b)
+		if _, dup := ids[option]; dup {
+			option = fmt.Sprintf("%s (%s)", option, stripFallback(b.Host))
+		}
+		for n := 2; ; n++ {
+			if _, dup := ids[option]; !dup {
+				break
+			}
+			option = fmt.Sprintf("%s #%d", bastionLabel(b), n)
+		}
+		ids[option] = b.ID
+		options = append(options, option)
+		if b.ID == current {
+			selected = option
+		}
+	}
+
+	return s.createCustomComboBoxItem(label, selected, options, client.ID, func(c *Client, v string) {
+		id, ok := ids[v]
+		if !ok {
+			return
+		}
+		// Döngü kontrolü değişikliğin bir kopyası üzerinde yapılır
+		check := model.CloneClient(*c)
+		set(&check, id)
+		if exclude != "" {
+			if _, err := check.BastionChain(exclude); err != nil {
+				fyne.Do(func() {
+					dialog.ShowError(err, s.window)
+					s.filterClients(s.searchEntry.Text)
+				})
+				return
+			}
+		}
+		set(c, id)
+		fyne.Do(func() { s.filterClients(s.searchEntry.Text) })
+	})
+}
+
+// routeFormItem draws the connection path of an environment: bu bilgisayar → bastion'lar → app server
+func (s *AppState) routeFormItem(client Client, app AppInfo) *widget.FormItem {
+	route, err := appRoute(client, app)
+	if err != nil {
+		label := widget.NewLabel(err.Error())
+		label.Importance = widget.DangerImportance
+		label.Wrapping = fyne.TextWrapWord
+		return widget.NewFormItem(FormLabelRoute, label)
+	}
+
+	nodes := []fyne.CanvasObject{routeNode(theme.ComputerIcon(), RouteThisComputer, "")}
+	for _, hop := range route.hops {
+		name := hop.name
+		if name == "" {
+			name = RouteJumpHost
+		}
+		nodes = append(nodes, widget.NewIcon(theme.NavigateNextIcon()), routeNode(theme.LoginIcon(), name, hop.target.String()))
+	}
+	server := fallback("")
+	if strings.TrimSpace(route.target.Host) != "" {
+		server = route.target.String()
+	}
+	nodes = append(nodes, widget.NewIcon(theme.NavigateNextIcon()), routeNode(theme.StorageIcon(), RouteAppServer, server))
+	return widget.NewFormItem(FormLabelRoute, container.NewHScroll(container.NewHBox(nodes...)))
+}
+
+// routeNode is one box of the connection diagram
+func routeNode(icon fyne.Resource, title, detail string) fyne.CanvasObject {
+	bg := canvas.NewRectangle(colorLightBlue)
+	bg.CornerRadius = 4
+	bg.StrokeColor = colorDarkcyan
+	bg.StrokeWidth = 1
+
+	text := container.NewVBox(widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	if detail != "" {
+		text.Add(widget.NewLabelWithStyle(detail, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true}))
+	}
+	return container.NewStack(bg, container.NewHBox(widget.NewIcon(icon), text))
+}
+
+// addBastion adds an empty bastion to a client and opens it
+func (s *AppState) addBastion(clientID string) {
+	b := Bastion{ID: model.NewID(), Name: BastionNewName}
+	if err := s.applyClientEdit(clientID, func(c *Client) { c.Bastions = append(c.Bastions, b) }); err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	s.expandedApps[b.ID] = true
+	s.filterClients(s.searchEntry.Text)
+}
+
+// deleteBastion removes a bastion after confirmation; onu kullananlar bir önceki atlamaya bağlanır
+func (s *AppState) deleteBastion(clientID, bastionID string) {
+	i := model.IndexByID(s.clients, clientID)
+	if i < 0 {
+		return
+	}
+	b := s.clients[i].BastionByID(bastionID)
+	if b == nil {
+		return
+	}
+	dialog.ShowConfirm(DialogTitleDeleteBastion, fmt.Sprintf(DialogMsgDeleteBastion, bastionLabel(*b)), func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := s.applyClientEdit(clientID, func(c *Client) { c.RemoveBastion(bastionID) }); err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		delete(s.expandedApps, bastionID)
+		s.filterClients(s.searchEntry.Text)
+	}, s.window)
+}
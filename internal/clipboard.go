@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// clipboardOptions panoyu temizleme süresi seçenekleri (saniye, 0 = kapalı)
+var clipboardOptions = []int{0, 10, 20, 30, 60, 120}
+
+// clipboardGuard clears secrets from the system clipboard after a timeout,
+// but only while the clipboard still holds the value that was copied.
+type clipboardGuard struct {
+	mu         sync.Mutex
+	window     fyne.Window
+	value      string
+	generation int // Her yeni kopyalama eski geri sayımı geçersiz kılar
+
+	statusLabel *widget.Label
+}
+
+// secretClipboard is shared by every password copy path (widget'lar AppState'e erişemediği için paket düzeyinde)
+var secretClipboard = &clipboardGuard{}
+
+// copySecret puts a secret on the clipboard and schedules it to be cleared
+func copySecret(window fyne.Window, value string) {
+	if window == nil {
+		return
+	}
+	window.Clipboard().SetContent(value)
+	secretClipboard.arm(window, value, clipboardClearTimeout())
+}
+
+// clipboardClearTimeout returns the configured timeout, 0 if auto-clear is disabled
+func clipboardClearTimeout() time.Duration {
+	seconds := fyne.CurrentApp().Preferences().IntWithFallback(PrefClipboardClearSeconds, DefaultClipboardClearSeconds)
+	return time.Duration(seconds) * time.Second
+}
+
+// setStatusLabel connects the status bar label that shows the countdown
+func (g *clipboardGuard) setStatusLabel(label *widget.Label) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.statusLabel = label
+}
+
+func (g *clipboardGuard) arm(window fyne.Window, value string, timeout time.Duration) {
+	g.mu.Lock()
+	g.generation++
+	gen := g.generation
+	g.window = window
+	g.value = value
+	g.mu.Unlock()
+
+	if timeout <= 0 {
+		g.setStatus("")
+		return
+	}
+
+	deadline := time.Now().Add(timeout)
+	g.setStatus(fmt.Sprintf(ClipboardStatusCountdown, int(timeout.Seconds())))
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			if !g.isCurrent(gen) {
+				return
+			}
+			remaining := time.Until(deadline)
+			if remaining > 0 {
+				secs := int(remaining.Round(time.Second).Seconds())
+				fyne.Do(func() {
+					if g.isCurrent(gen) {
+						g.setStatus(fmt.Sprintf(ClipboardStatusCountdown, secs))
+					}
+				})
+				continue
+			}
+
+			fyne.Do(func() {
+				if g.isCurrent(gen) {
+					g.clear()
+				}
+			})
+			return
+		}
+	}()
+}
+
+func (g *clipboardGuard) isCurrent(gen int) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.generation == gen && g.window != nil
+}
+
+// clearNow clears a pending secret immediately (örn. vault kilitlendiğinde)
+func (g *clipboardGuard) clearNow() {
+	g.mu.Lock()
+	pending := g.window != nil
+	g.mu.Unlock()
+	if pending {
+		g.clear()
+	}
+}
+
+// clear empties the clipboard if it still contains our value. Must run on the UI goroutine.
+func (g *clipboardGuard) clear() {
+	g.mu.Lock()
+	window, value := g.window, g.value
+	g.generation++
+	g.window = nil
+	g.value = ""
+	g.mu.Unlock()
+
+	if window == nil {
+		return
+	}
+
+	// Kullanıcı bu arada başka bir şey kopyaladıysa dokunma
+	if window.Clipboard().Content() != value {
+		g.setStatus("")
+		return
+	}
+	window.Clipboard().SetContent("")
+	g.setStatus(ClipboardStatusCleared)
+
+	gen := g.currentGeneration()
+	time.AfterFunc(3*time.Second, func() {
+		fyne.Do(func() {
+			if g.currentGeneration() == gen {
+				g.setStatus("")
+			}
+		})
+	})
+}
+
+func (g *clipboardGuard) currentGeneration() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.generation
+}
+
+func (g *clipboardGuard) setStatus(text string) {
+	g.mu.Lock()
+	label := g.statusLabel
+	g.mu.Unlock()
+	if label != nil {
+		label.SetText(text)
+	}
+}
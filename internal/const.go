@@ -1,11 +1,17 @@
 package main
 
+import "time"
+
 const (
 	// Application
 	DefaultJSONFile = "client_info.json"
 	AppID           = "com.clientinfo.manager"
 	AppName         = "Client Info Manager"
 
+	// PrefLastVaultFile son açılan vault dosyası (JSON veya SQLite); açılışta tekrar açılır
+	PrefLastVaultFile = "lastVaultFile"
+	MenuOpenVault     = "Open Vault..."
+
 	// Window
 	DefaultWindowWidth  = 800
 	DefaultWindowHeight = 600
@@ -36,6 +42,7 @@ const (
 	DialogMsgAddClientFirst           = "Please add a customer first!"
 	DialogMsgInvalidClientSelection   = "Invalid customer selection"
 	DialogMsgClientNotFound           = "Customer not found"
+	DialogMsgClientExists             = "Customer %s already exists"
 	DialogMsgInvalidClientInfo        = "Invalid customer information"
 	DialogMsgFileNotFound             = "client_info.json file not found. A new file will be created."
 	DialogMsgJSONReadError            = "JSON read error"
@@ -53,8 +60,298 @@ const (
 	FormLabelAppUsers = "App Users"
 
 	// SSH
-	DialogTitleSSH           = "SSH"
-	DialogMsgSSHConfig       = "SSH configuration is missing. Server IP and username are required."
-	DialogMsgSSHFailed       = "SSH failed to open: %v"
-	DialogMsgSSHPasswordCopy = "SSH password copied to clipboard.\nPaste it in the terminal with Ctrl+V."
+	DialogTitleSSH     = "SSH"
+	DialogMsgSSHConfig = "SSH configuration is missing. Server IP and username are required."
+	DialogMsgSSHFailed = "SSH failed to open: %v"
+	SSHAskpassLabel    = "Answer"
+	// Harici ssh yalnızca kayıtlı host anahtarlarına güvenir
+	DialogMsgSSHNoHostKey = "No host key is stored for %s. Connect once with the built-in terminal to record it; the external ssh only trusts stored keys."
+	// ExternalSSHKnownHostsLifetime is how long the known_hosts file of an external ssh is kept; silinince ssh kapalı kalır (fail closed)
+	ExternalSSHKnownHostsLifetime = 10 * time.Minute
+
+	// Master password / unlock screen
+	MasterPasswordMinLength = 8
+	UnlockFormWidth         = 380
+	UnlockTitleUnlock       = "Unlock Vault"
+	UnlockTitleCreate       = "Create Master Password"
+	UnlockTitleMigrate      = "Set Master Password"
+	UnlockMsgUnlock         = "Enter the master password to decrypt the customer data."
+	UnlockMsgCreate         = "No data file found. Choose a master password for the new vault."
+	UnlockMsgMigrate        = "This file uses the old shared key. Choose a master password to re-encrypt it. A backup copy of the original will be kept."
+	UnlockMsgTooShort       = "Master password must be at least %d characters."
+	UnlockMsgMismatch       = "Passwords do not match."
+	UnlockMsgWrongPassword  = "Wrong master password."
+	UnlockButtonUnlock      = "Unlock"
+	UnlockButtonWorking     = "Unlocking..."
+	UnlockMsgTampered       = "The vault failed its integrity check. The file was modified or is corrupted."
+	UnlockRememberKey       = "Remember on this machine"
+	PrefKeyringSecret       = "keyringWrapSecret" // Hatırlanan anahtarları saran, bu kuruluma özel rastgele sır
+
+	// Auto-lock
+	PrefAutoLockMinutes    = "autoLockMinutes"
+	DefaultAutoLockMinutes = 5
+	AutoLockCheckInterval  = 15 * time.Second
+	MenuLockNow            = "Lock Now"
+	AutoLockSettingsInfo   = "Lock the vault after this much time without mouse or keyboard activity:"
+
+	// Clipboard auto-clear
+	PrefClipboardClearSeconds    = "clipboardClearSeconds"
+	DefaultClipboardClearSeconds = 20
+	ClipboardSettingsInfo        = "Clear copied passwords from the clipboard after:"
+	ClipboardStatusCountdown     = "📋 Clipboard clears in %ds"
+	ClipboardStatusCleared       = "📋 Clipboard cleared"
+
+	// Security settings
+	MenuSecuritySettings  = "Security Settings..."
+	SecuritySettingsTitle = "Security Settings"
+
+	// OS keyring
+	MenuForgetKey               = "Forget Saved Key"
+	DialogMsgKeyForgotten       = "The saved key was removed from this machine.\nThe master password will be asked on next start."
+	DialogMsgKeyringUnavailable = "The key could not be stored in the system keyring: %v\nThe master password will be asked on next start."
+
+	// Whole-file encryption
+	MenuWholeFileEncryption    = "Encrypt Whole File"
+	DialogMsgWholeFileEnabled  = "Whole-file encryption enabled.\nAll customer data is now stored as a single authenticated blob."
+	DialogMsgWholeFileDisabled = "Whole-file encryption disabled.\nOnly password fields are encrypted."
+
+	// Master password change / key rotation
+	MenuChangeMasterPassword = "Change Master Password..."
+	RotateKeyTitle           = "Change Master Password"
+	RotateKeyInfo            = "All data is re-encrypted under a new key with a fresh salt.\nA timestamped backup of the current file is kept."
+	RotateKeyWorking         = "Re-encrypting vault..."
+	RotateKeyButton          = "Change"
+	DialogMsgKeyRotated      = "Master password changed and vault key rotated.\nBackup: %s"
+
+	// Backups
+	PrefBackupIntervalMinutes    = "backupIntervalMinutes"
+	DefaultBackupIntervalMinutes = 0
+	PrefBackupKeepLast           = "backupKeepLast"
+	PrefBackupKeepDaily          = "backupKeepDaily"
+	PrefBackupKeepWeekly         = "backupKeepWeekly"
+	BackupSettingsInterval       = "Take an encrypted backup snapshot when saving:"
+	BackupSettingsRetention      = "Keep the newest snapshots, plus one per day and one per week:"
+	MenuRestoreBackup            = "Restore from Backup..."
+	RestoreTitle                 = "Restore from Backup"
+	RestoreNoBackups             = "There are no backups yet.\nA snapshot is taken when the data is saved."
+	RestoreSelectHint            = "Select a backup to compare it with the current data."
+	RestoreIdentical             = "Identical to the current data."
+	RestoreOldKeyHint            = "This backup was made before the master password was changed and cannot be opened with the current key."
+	RestoreButton                = "Restore"
+	RestoreConfirm               = "Replace the current data with the backup from %s?\nA snapshot of the current data is taken first."
+	BackupOldKey                 = "previous key"
+	BackupUnreadable             = "unreadable"
+	DialogMsgRestored            = "Backup restored."
+	DialogMsgBackupFailed        = "The data was saved, but the backup snapshot failed: %v"
+
+	// Change history / undo
+	TabNameHistory        = "History"
+	MenuUndo              = "Undo"
+	MenuRedo              = "Redo"
+	HistoryEmpty          = "No changes recorded for this customer yet."
+	HistoryMaxShown       = 200
+	HistoryRevertButton   = "Revert"
+	HistoryAbsent         = "(none)"
+	DialogMsgHistoryReset = "The change history could not be opened and was set aside as %s.\nA new history is started."
+
+	// External changes / conflicts
+	FileWatchInterval        = 3 * time.Second
+	ExternalChangeTitle      = "File Changed on Disk"
+	ExternalChangeReload     = "%s was changed by someone else.\nReload it now? Your view is replaced with the saved data."
+	ExternalChangeKeyChanged = "%s was changed by someone else and can no longer be opened with the current key (the master password was changed).\nUnlock it with the new password."
+	DialogMsgChangedOnDisk   = "The file was changed by someone else, so nothing was saved.\nYour edits are kept and can be merged with theirs."
+	DialogMsgExternalMerged  = "Changes from disk were merged with your edits and saved."
+	ConflictTitle            = "Resolve Conflicts"
+	ConflictInfo             = "These fields were changed both here and by someone else. Choose which value to keep:"
+	ConflictApply            = "Merge and Save"
+	ConflictKeepMine         = "Keep mine: %s"
+	ConflictTakeTheirs       = "Take theirs: %s"
+	ConflictClientDeleted    = "(customer deleted)"
+	ConflictClientChanged    = "(customer changed)"
+	ConflictFieldAbsent      = "(removed)"
+	ConflictValueMaxLength   = 80
+
+	// Import merge wizard
+	ImportMergeTitle       = "Import %s"
+	ImportMergeInfo        = "The imported customer differs from your copy in these fields. Fields filled in on only one side are merged automatically. Choose a value for each:"
+	ImportMergeEdit        = "Edit"
+	ImportMergeAddEnv      = "Add environment %s"
+	ImportMergeSkipEnv     = "Skip it"
+	ImportMergePreview     = "Preview"
+	ImportMergeBack        = "Back"
+	ImportMergeSave        = "Save"
+	ImportMergePreviewInfo = "These changes will be saved to %s:"
+	ImportMergeNoChanges   = "The imported data matches your copy of %s; nothing to change."
+	DialogMsgImportMerged  = "Customer updated from the import."
+
+	// Sealed exports
+	MenuMyExportKey               = "My Export Key..."
+	MyExportKeyTitle              = "My Export Key"
+	MyExportKeyInfo               = "Give this public key to whoever sends you customer data. Exports encrypted to it can only be opened on this computer; the private key is kept in the OS keyring."
+	ExportSealTitle               = "Protect Export"
+	ExportSealInfo                = "The whole export file is encrypted. Choose who can open it:"
+	ExportSealPassphrase          = "One-time passphrase"
+	ExportSealRecipient           = "Recipient's public key"
+	ExportSealPassphraseHint      = "Send the passphrase through another channel than the file (e.g. by phone)."
+	ExportSealRecipientHint       = "age public keys (age1...), one per line. Recipients find theirs under " + MenuMyExportKey
+	ExportSealButton              = "Export"
+	ExportPassphraseMinLength     = 12
+	ExportPassphraseLength        = 24
+	ExportFileFilter              = "Customer Export"
+	DialogMsgExportSealed         = "Export written. The passphrase was copied to the clipboard; send it separately from the file."
+	DialogMsgExportSealedTo       = "Export written. Only the holders of the given keys can open it."
+	ImportPassphraseTitle         = "Encrypted Export"
+	ImportPassphrasePrompt        = "%s is protected with a passphrase:"
+	DialogMsgExportKeyUnavailable = "The export key could not be read from or stored in the system keyring: %v"
+	DialogMsgNoExportKey          = "%s was encrypted to a public key, but this computer has no export key. Ask the sender for a passphrase protected export or give them your key under " + MenuMyExportKey
+	DialogMsgWrongExportKey       = "%s cannot be opened: the passphrase is wrong, or it was encrypted to someone else's key."
+
+	// Export profiles; özel profiller vault'un yanındaki .profiles.json dosyasından okunur
+	PrefExportProfile     = "exportProfile"
+	ExportProfileLabel    = "Profile:"
+	ExportProfilePreview  = "Preview"
+	ExportPreviewTitle    = "What Leaves This Computer"
+	ExportPreviewInfo     = "Profile %q, %d customer(s):"
+	DialogMsgProfilesLoad = "Custom export profiles could not be read, only the built-in ones are available: %v"
+
+	// Spreadsheets (CSV/XLSX); her ortam bir satır
+	MenuExportSpreadsheet         = "Export Spreadsheet..."
+	MenuImportSpreadsheet         = "Import Spreadsheet..."
+	SpreadsheetFileFilter         = "Spreadsheet"
+	SpreadsheetDefaultName        = "environments"
+	SpreadsheetExportTitle        = "Export Spreadsheet"
+	SpreadsheetExportInfo         = "One row per environment with its customer's columns, as CSV or XLSX (chosen by file name). The file is NOT encrypted."
+	SpreadsheetExportSecrets      = "Include passwords"
+	SpreadsheetImportTitle        = "Import Spreadsheet"
+	SpreadsheetMappingTitle       = "Map Columns"
+	SpreadsheetMappingInfo        = "%d rows. Choose the field each column is read into:"
+	SpreadsheetMappingNext        = "Next"
+	SpreadsheetIgnoreColumn       = "(ignore)"
+	SpreadsheetUnnamedColumn      = "Column %d"
+	SpreadsheetReviewSummary      = "%d customers with %d environments can be imported."
+	SpreadsheetReviewRejected     = "%d rows are rejected:"
+	SpreadsheetReviewExisting     = "Already in the vault, each will be reviewed in the merge wizard: %s"
+	SpreadsheetReviewSkipExisting = "Skip customers that already exist"
+	SpreadsheetReviewImport       = "Import"
+	DialogMsgSpreadsheetExported  = "%d rows written to %s."
+	DialogMsgSpreadsheetEmpty     = "The spreadsheet has no data rows."
+	DialogMsgSpreadsheetNoCompany = "Map one column to Company; it identifies the customer of each row."
+
+	// Password managers (KeePass KDBX 4, Bitwarden JSON)
+	MenuExportPasswordManager      = "Export to Password Manager..."
+	MenuImportPasswordManager      = "Import from Password Manager..."
+	PasswordExportTitle            = "Export to Password Manager"
+	PasswordExportInfo             = "Each customer becomes a group with VPN, Jira, DB, App Server and Weblogic entries."
+	PasswordFormatKeePass          = "KeePass (KDBX 4)"
+	PasswordFormatBitwarden        = "Bitwarden (JSON)"
+	PasswordFileFilter             = "Password Manager"
+	PasswordExportDefaultName      = "client-man"
+	PasswordExportTooShort         = "The password must be at least %d characters."
+	PasswordExportBitwardenWarning = "Bitwarden's JSON import format is NOT encrypted. Delete the file once it is imported."
+	PasswordImportTitle            = "Import from Password Manager"
+	PasswordImportPrompt           = "Password of the KeePass database %s:"
+	DialogMsgPasswordExported      = "%d customers written to %s."
+
+	// Embedded SSH terminal; her ortamın bir penceresi, her oturumun bir sekmesi olur
+	TerminalDefaultRows        = 24
+	TerminalDefaultCols        = 80
+	TerminalMinRows            = 5
+	TerminalMinCols            = 20
+	TerminalWindowWidth        = 900
+	TerminalWindowHeight       = 560
+	TerminalWindowTitle        = "SSH - %s / %s - %s"
+	TerminalSessionTitle       = "Session %d"
+	TerminalSessionEndedSuffix = " (closed)"
+	TerminalMsgConnecting      = "Connecting to %s ..."
+	TerminalMsgClosed          = "[Connection closed]"
+	TerminalMenuCopy           = "Copy"
+	TerminalMenuPaste          = "Paste"
+	TerminalTipNewSession      = "New session to the same server"
+	TerminalTipCopy            = "Copy selection (Ctrl+Shift+C)"
+	TerminalTipPaste           = "Paste (Ctrl+Shift+V)"
+	TerminalTipExternal        = "Open in an external terminal (copies the password to the clipboard)"
+
+	// SSH host keys; her ortam kendi güvenilen anahtarlarını known_hosts satırı olarak saklar
+	FormLabelHostKey           = "Host Key"
+	MenuImportHostKeys         = "Import Host Keys from known_hosts..."
+	HostKeyNone                = "— (recorded on first connection)"
+	HostKeyForgetTip           = "Forget - The next connection records the server's key again"
+	HostKeyForgetTitle         = "Forget Host Key"
+	HostKeyForgetConfirm       = "Forget the stored host key? The next connection trusts whatever key the server offers."
+	HostKeyChangedTitle        = "Host Key Changed"
+	HostKeyChangedStored       = "stored:  %s %s"
+	HostKeyChangedOffered      = "offered: %s %s"
+	HostKeyChangedInfo         = "Someone may be intercepting the connection (man-in-the-middle), or the server was reinstalled. Replace the stored key only after the server's administrator has confirmed the new fingerprint."
+	HostKeyChangedReplace      = "Replace Key and Connect"
+	HostKeyChangedDisconnect   = "Disconnect"
+	HostKeyImportTitle         = "Import Host Keys"
+	DialogMsgHostKeysImported  = "%d host keys imported for %d environments and bastions."
+	DialogMsgHostKeysConflict  = "%d environments or bastions already trust a different key of the same type and were left unchanged."
+	TerminalMsgHostKeyRecorded = "Host key %s %s recorded for this environment."
+	TerminalMsgHostKeyImported = "Host key %s %s taken over from ~/.ssh/known_hosts."
+	TerminalMsgHostKeyChanged  = "WARNING: THE HOST KEY OF %s HAS CHANGED!"
+
+	// SSH params; metin olarak saklanır, ayrıştırılıp argv olarak kullanılır
+	FormLabelSSHParams        = "SSH Params"
+	DialogMsgSSHParamsInvalid = "SSH params are invalid: %v"
+
+	// Bastions; firma düzeyinde tanımlanır, ortamlar ve diğer bastion'lar ID ile başvurur
+	TabNameBastions          = "Bastions"
+	FormLabelBastion         = "Via Bastion"
+	FormLabelRoute           = "Route"
+	FormLabelBastionName     = "Name"
+	FormLabelBastionHost     = "Host"
+	FormLabelBastionUser     = "User"
+	FormLabelBastionPass     = "Password"
+	FormLabelBastionKeyFile  = "Key File"
+	FormLabelBastionVia      = "Via"
+	BastionDirect            = "— (direct)"
+	BastionUnnamed           = "Unnamed bastion"
+	BastionNewName           = "New Bastion"
+	BastionAddText           = "New Bastion"
+	BastionAddTip            = "New Bastion - Add a jump host that environments of the customer connect through"
+	BastionDeleteTip         = "Delete - Remove this bastion; environments using it connect through its own Via instead"
+	DialogTitleDeleteBastion = "Delete Bastion"
+	DialogMsgDeleteBastion   = "Delete bastion %s?\nEnvironments and bastions using it will connect through its Via instead."
+	DialogMsgBastionHostUser = "Put the user %q in the User field, not in Host"
+	RouteThisComputer        = "This computer"
+	RouteJumpHost            = "Jump host"
+	RouteAppServer           = "App server"
+	TerminalMsgVia           = "  via %s"
+
+	// SSH tunnels; ortamın app server'ı üzerinden yerel port yönlendirmeleri (ssh -L)
+	TunnelRefreshInterval        = time.Second
+	TunnelsTitle                 = "Tunnels"
+	TunnelNone                   = "No tunnels. Add one to reach e.g. the database through the app server."
+	TunnelUnnamed                = "Unnamed tunnel"
+	TunnelAddText                = "New Tunnel"
+	TunnelAddTip                 = "New Tunnel - Forward a local port to a host:port seen from the app server"
+	TunnelStartTip               = "Start - Listen on the local port and forward it through the app server"
+	TunnelStopTip                = "Stop - Close the local port and its connections"
+	TunnelEditTip                = "Edit - Change the name, ports and remote host"
+	TunnelDeleteTip              = "Delete - Stop and remove this tunnel"
+	TunnelAddrFormat             = "localhost:%d → %s:%d"
+	TunnelStatsFormat            = "%d open · %d total · ↓ %s ↑ %s"
+	TunnelStateStopped           = "Stopped"
+	TunnelStateConnecting        = "Connecting"
+	TunnelStateRunning           = "Running"
+	TunnelStateReconnecting      = "Reconnecting"
+	TunnelStateFailed            = "Failed"
+	TunnelPresetDB               = "Oracle DB"
+	TunnelPresetWebLogic         = "WebLogic Console"
+	TunnelPresetPlaceholder      = "(fill in manually)"
+	TunnelMsgHostKeyChanged      = "Open an SSH terminal to the environment to check and accept the new key"
+	FormLabelTunnelPreset        = "Preset"
+	FormLabelTunnelName          = "Name"
+	FormLabelTunnelLocalPort     = "Local Port"
+	FormLabelTunnelRemoteHost    = "Remote Host"
+	FormLabelTunnelRemotePort    = "Remote Port"
+	FormLabelTunnelAutoReconnect = "Auto-reconnect"
+	DialogTitleAddTunnel         = "New Tunnel"
+	DialogTitleEditTunnel        = "Edit Tunnel"
+	DialogTitleDeleteTunnel      = "Delete Tunnel"
+	DialogMsgDeleteTunnel        = "Delete tunnel %s?"
+	DialogMsgTunnelPort          = "Port must be a number between 1 and 65535"
+	DialogMsgTunnelHost          = "Remote host must be a host name or IP address, as seen from the app server"
+	DialogMsgTunnelListen        = "Local port %s could not be opened: %v"
 )
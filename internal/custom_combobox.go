@@ -101,14 +101,14 @@ func (r *customComboBoxRenderer) buildEditUI() {
 	r.comboBox.editSelect = select_
 	r.comboBox.editSelect.SetSelected(r.comboBox.text)
 
-	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), func() {
+	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), userActivity.Tap(func() {
 		r.saveEdit()
-	})
+	}))
 	saveBtn.Importance = widget.HighImportance
 
-	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), userActivity.Tap(func() {
 		r.cancelEdit()
-	})
+	}))
 	cancelBtn.Importance = widget.LowImportance
 
 	buttonBar := container.NewHBox(saveBtn, cancelBtn)
@@ -183,13 +183,10 @@ func (r *customComboBoxRenderer) Refresh() {
 
 func (r *customComboBoxRenderer) Destroy() {}
 
-func (s *AppState) createCustomComboBoxItem(label string, text string, options []string, clientIndex int, updateFunc func(*Client, string)) *widget.FormItem {
+func (s *AppState) createCustomComboBoxItem(label string, text string, options []string, clientID string, updateFunc func(*Client, string)) *widget.FormItem {
 	comboBox := NewCustomComboBox(text, options, func(newText string) {
-		if clientIndex >= 0 && clientIndex < len(s.clients) {
-			updateFunc(&s.clients[clientIndex], newText)
-			if err := s.saveClients(); err != nil {
-				dialog.ShowError(err, s.window)
-			}
+		if err := s.applyClientEdit(clientID, func(c *Client) { updateFunc(c, newText) }); err != nil {
+			dialog.ShowError(err, s.window)
 		}
 	}, func() fyne.Window {
 		return s.window
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"clientinfo/internal/vault"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
@@ -27,6 +28,7 @@ type CustomTextBox struct {
 
 	onSave   func(string)
 	onWindow func() fyne.Window
+	validate func(string) error // nil değilse geçersiz metin kaydedilmez
 
 	displayLabel  *widget.Label
 	editEntry     *widget.Entry
@@ -57,6 +59,11 @@ func NewCustomTextBox(text string, isPassword bool, isMultiLine bool, isURL bool
 	return ctb
 }
 
+// SetValidator checks the edited text before it is saved; hata düzenleme kutusunun altında gösterilir
+func (ctb *CustomTextBox) SetValidator(validate func(string) error) {
+	ctb.validate = validate
+}
+
 func (ctb *CustomTextBox) getDisplayText() string {
 	text := ctb.text
 	if !ctb.isMultiLine {
@@ -128,6 +135,7 @@ type customTextBoxRenderer struct {
 	readOnlyContainer *fyne.Container
 	editContainer     *fyne.Container
 
+	errorLabel    *widget.Label
 	copyButton    *IconButton
 	eyeButton     *IconButton
 	hyperlink     *widget.Hyperlink
@@ -171,7 +179,12 @@ func (r *customTextBoxRenderer) buildReadOnlyUI() {
 				return
 			}
 
-			window.Clipboard().SetContent(r.textBox.text)
+			if r.textBox.isPassword {
+				// Şifreler belirli süre sonra panodan temizlenir
+				copySecret(window, r.textBox.text)
+			} else {
+				window.Clipboard().SetContent(r.textBox.text)
+			}
 			r.copyButton.SetIcon(r.copiedIcon)
 
 			go func() {
@@ -239,14 +252,14 @@ func (r *customTextBoxRenderer) buildEditUI() {
 	// Edit modunda şifreleri açık göster
 	r.textBox.editEntry.Password = false
 
-	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), func() {
+	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), userActivity.Tap(func() {
 		r.saveEdit()
-	})
+	}))
 	saveBtn.Importance = widget.HighImportance
 
-	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), userActivity.Tap(func() {
 		r.cancelEdit()
-	})
+	}))
 	cancelBtn.Importance = widget.LowImportance
 
 	buttons := []fyne.CanvasObject{saveBtn, cancelBtn}
@@ -266,13 +279,42 @@ func (r *customTextBoxRenderer) buildEditUI() {
 	}
 
 	buttonBar := container.NewHBox(buttons...)
-	r.editContainer = container.NewBorder(nil, nil, nil, buttonBar, r.textBox.editEntry)
+
+	// Doğrulama hatası yazarken güncellenir; geçerli metinde gizlidir
+	r.errorLabel = widget.NewLabel("")
+	r.errorLabel.Importance = widget.DangerImportance
+	r.errorLabel.Wrapping = fyne.TextWrapWord
+	r.errorLabel.Hide()
+	if r.textBox.validate != nil {
+		r.textBox.editEntry.Validator = r.textBox.validate
+		r.textBox.editEntry.OnChanged = func(value string) {
+			r.showError(r.textBox.validate(value))
+		}
+	}
+
+	r.editContainer = container.NewBorder(nil, r.errorLabel, nil, buttonBar, r.textBox.editEntry)
+}
+
+// showError shows err under the edit box, nil ise gizler
+func (r *customTextBoxRenderer) showError(err error) {
+	if err == nil {
+		r.errorLabel.Hide()
+		return
+	}
+	r.errorLabel.SetText(err.Error())
+	r.errorLabel.Show()
 }
 
 func (r *customTextBoxRenderer) saveEdit() {
 	if r.textBox.editEntry == nil {
 		return
 	}
+	if r.textBox.validate != nil {
+		if err := r.textBox.validate(r.textBox.editEntry.Text); err != nil {
+			r.showError(err)
+			return
+		}
+	}
 
 	r.textBox.text = r.textBox.editEntry.Text
 	r.textBox.readOnly = true
@@ -293,6 +335,7 @@ func (r *customTextBoxRenderer) cancelEdit() {
 	if r.textBox.editEntry != nil {
 		r.textBox.editEntry.SetText(r.textBox.text)
 	}
+	r.showError(nil)
 	r.textBox.displayLabel.SetText(r.textBox.getDisplayText())
 	r.textBox.BaseWidget.Refresh()
 }
@@ -373,20 +416,17 @@ func (r *customTextBoxRenderer) Refresh() {
 
 func (r *customTextBoxRenderer) Destroy() {}
 
-func (s *AppState) createCustomTextBoxItem(label string, text string, isPassword bool, isMultiLine bool, isURL bool, clientIndex int, updateFunc func(*Client, string)) *widget.FormItem {
-	if isEncrypted(text) {
-		decrypted, err := decryptString(text)
+func (s *AppState) createCustomTextBoxItem(label string, text string, isPassword bool, isMultiLine bool, isURL bool, clientID string, updateFunc func(*Client, string)) *widget.FormItem {
+	if vault.IsEncrypted(text) {
+		decrypted, err := vault.DecryptString(text, s.vaultKey)
 		if err == nil {
 			text = decrypted
 		}
 	}
 
 	textBox := NewCustomTextBox(text, isPassword, isMultiLine, isURL, func(newText string) {
-		if clientIndex >= 0 && clientIndex < len(s.clients) {
-			updateFunc(&s.clients[clientIndex], newText)
-			if err := s.saveClients(); err != nil {
-				dialog.ShowError(err, s.window)
-			}
+		if err := s.applyClientEdit(clientID, func(c *Client) { updateFunc(c, newText) }); err != nil {
+			dialog.ShowError(err, s.window)
 		}
 	}, func() fyne.Window {
 		return s.window
@@ -0,0 +1,178 @@
+// Package exchange seals customer exports so that only the holder of a one-time
+// passphrase or of the recipient's private key can open them.
+//
+// Dosyalar age formatındadır (https://age-encryption.org, ASCII armor ile):
+// içerik, gizli alanları açık olan client listesinin JSON halidir; dosyanın
+// tamamı passphrase (scrypt) veya alıcının X25519 açık anahtarı ile şifrelenir.
+// Eski sürümlerin ortak (legacy) anahtarla yazdığı JSON export'lar da okunur.
+package exchange
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/vault"
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// Extension is the file extension of sealed exports
+const Extension = ".age"
+
+// ErrWrongKey is returned when neither the passphrase nor the local identity opens the file
+var ErrWrongKey = errors.New("the export cannot be opened with this passphrase or key")
+
+// ageMagic is the first line of a binary (armor'suz) age file
+const ageMagic = "age-encryption.org/v1"
+
+// SealWithPassphrase encrypts clients with a passphrase
+func SealWithPassphrase(clients []model.Client, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+	r, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return seal(clients, r)
+}
+
+// SealToRecipients encrypts clients to one or more age public keys ("age1...")
+func SealToRecipients(clients []model.Client, publicKeys []string) ([]byte, error) {
+	var recipients []age.Recipient
+	for _, key := range publicKeys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		r, err := age.ParseX25519Recipient(key)
+		if err != nil {
+			return nil, fmt.Errorf("public key %q: %w", key, err)
+		}
+		recipients = append(recipients, r)
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("no recipient public key given")
+	}
+	return seal(clients, recipients...)
+}
+
+func seal(clients []model.Client, recipients ...age.Recipient) ([]byte, error) {
+	payload, err := json.MarshalIndent(clients, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	armored := armor.NewWriter(&buf)
+	w, err := age.Encrypt(armored, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := armored.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// IsSealed reports whether data is an age file (armor'lu veya ikili)
+func IsSealed(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return bytes.HasPrefix(trimmed, []byte(armor.Header)) || bytes.HasPrefix(trimmed, []byte(ageMagic))
+}
+
+// NeedsPassphrase reports whether a sealed file was encrypted with a passphrase
+// rather than to public keys. Sadece başlık okunur; şifre çözülmez.
+func NeedsPassphrase(data []byte) (bool, error) {
+	src := ageReader(data)
+	lines := bufio.NewScanner(src)
+	for lines.Scan() {
+		line := lines.Text()
+		if strings.HasPrefix(line, "-> scrypt ") {
+			return true, nil
+		}
+		if strings.HasPrefix(line, "---") {
+			return false, nil
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return false, err
+	}
+	return false, errors.New("not an age file")
+}
+
+// OpenWithPassphrase decrypts a file sealed with SealWithPassphrase
+func OpenWithPassphrase(data []byte, passphrase string) ([]model.Client, error) {
+	id, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return open(data, id)
+}
+
+// OpenWithIdentity decrypts a file sealed to the public key of identity
+func OpenWithIdentity(data []byte, identity *age.X25519Identity) ([]model.Client, error) {
+	return open(data, identity)
+}
+
+func open(data []byte, identities ...age.Identity) ([]model.Client, error) {
+	r, err := age.Decrypt(ageReader(data), identities...)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, ErrWrongKey
+		}
+		return nil, err
+	}
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseClients(payload)
+}
+
+// OpenLegacy reads an unsealed JSON export whose secrets are encrypted with the shared legacy key
+func OpenLegacy(data []byte) ([]model.Client, error) {
+	clients, err := parseClients(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := vault.DecryptClients(clients, vault.LegacyKey()); err != nil {
+		return nil, fmt.Errorf("decrypt error: %w", err)
+	}
+	return clients, nil
+}
+
+// parseClients accepts a list of clients or a single client object
+func parseClients(data []byte) ([]model.Client, error) {
+	var clients []model.Client
+	if err := json.Unmarshal(data, &clients); err == nil {
+		return clients, nil
+	}
+	var single model.Client
+	if err := json.Unmarshal(data, &single); err != nil {
+		return nil, err
+	}
+	return []model.Client{single}, nil
+}
+
+// ageReader returns the binary age stream of data, armor varsa çözülerek
+func ageReader(data []byte) io.Reader {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte(armor.Header)) {
+		return armor.NewReader(bytes.NewReader(trimmed))
+	}
+	return bytes.NewReader(data)
+}
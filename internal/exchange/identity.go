@@ -0,0 +1,22 @@
+package exchange
+
+import (
+	"strings"
+
+	"filippo.io/age"
+)
+
+// NewIdentity generates a new X25519 key pair for receiving sealed exports
+func NewIdentity() (*age.X25519Identity, error) {
+	return age.GenerateX25519Identity()
+}
+
+// ParseIdentity parses an "AGE-SECRET-KEY-1..." private key as stored by the app
+func ParseIdentity(s string) (*age.X25519Identity, error) {
+	return age.ParseX25519Identity(strings.TrimSpace(s))
+}
+
+// PublicKey returns the "age1..." public key others seal exports to
+func PublicKey(identity *age.X25519Identity) string {
+	return identity.Recipient().String()
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"clientinfo/internal/exchange"
+	"clientinfo/internal/keystore"
+	"clientinfo/internal/redact"
+	"clientinfo/internal/store"
+	"filippo.io/age"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	nativeDialog "github.com/sqweek/dialog"
+)
+
+// exportIdentityAccount is the keyring account of this computer's export private key
+const exportIdentityAccount = "export-identity"
+
+// exportIdentity returns the private key that opens exports sealed to this computer.
+// create true ise ve henüz yoksa yeni bir anahtar üretilip keyring'e yazılır.
+func (s *AppState) exportIdentity(create bool) (*age.X25519Identity, error) {
+	if s.keys == nil {
+		return nil, errors.New("keyring is not available")
+	}
+	stored, err := s.keys.Get(exportIdentityAccount)
+	if err == nil && stored != "" {
+		return exchange.ParseIdentity(stored)
+	}
+	if err != nil && !errors.Is(err, keystore.ErrNotFound) {
+		return nil, fmt.Errorf(DialogMsgExportKeyUnavailable, err)
+	}
+	if !create {
+		return nil, nil
+	}
+
+	identity, err := exchange.NewIdentity()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.keys.Set(exportIdentityAccount, identity.String()); err != nil {
+		return nil, fmt.Errorf(DialogMsgExportKeyUnavailable, err)
+	}
+	return identity, nil
+}
+
+// showMyExportKey shows the public key others seal exports to, creating the key pair on first use
+func (s *AppState) showMyExportKey() {
+	identity, err := s.exportIdentity(true)
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	publicKey := exchange.PublicKey(identity)
+
+	info := widget.NewLabel(MyExportKeyInfo)
+	info.Wrapping = fyne.TextWrapWord
+	keyEntry := widget.NewEntry()
+	keyEntry.SetText(publicKey)
+	keyEntry.Disable()
+	copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), userActivity.Tap(func() {
+		// Açık anahtar gizli değil; pano temizlenmez
+		s.window.Clipboard().SetContent(publicKey)
+	}))
+
+	content := container.NewVBox(info, container.NewBorder(nil, nil, nil, copyBtn, keyEntry))
+	d := dialog.NewCustom(MyExportKeyTitle, "Close", content, s.window)
+	d.Resize(fyne.NewSize(620, 0))
+	d.Show()
+}
+
+// exportProfiles returns the built-in and custom export profiles of the open vault.
+// Özel profil dosyası okunamazsa uyarı gösterilir ve yerleşik profillerle devam edilir.
+func (s *AppState) exportProfiles() []redact.Profile {
+	profiles, err := redact.Load(s.currentFile)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf(DialogMsgProfilesLoad, err), s.window)
+	}
+	return profiles
+}
+
+// showExportPreview lists what profile keeps, masks and leaves out of clients
+func (s *AppState) showExportPreview(profile redact.Profile, clients []Client) {
+	_, decisions, err := profile.Apply(clients)
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+
+	info := widget.NewLabel(fmt.Sprintf(ExportPreviewInfo, profile.Name, len(clients)))
+	preview := widget.NewLabel(redact.Preview(decisions))
+	preview.TextStyle = fyne.TextStyle{Monospace: true}
+	preview.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(preview)
+	scroll.SetMinSize(fyne.NewSize(600, 320))
+
+	dialog.ShowCustom(ExportPreviewTitle, "Close", container.NewBorder(info, nil, nil, nil, scroll), s.window)
+}
+
+// showSealExport asks for an export profile and how to protect the export, then seals clients
+// and writes them to a file of the user's choice.
+// Profilin bıraktığı gizli alanlar dosyada açık yazılır; dosyanın tamamı passphrase veya alıcı anahtarıyla şifrelenir.
+func (s *AppState) showSealExport(clients []Client, startName string) {
+	profiles := s.exportProfiles()
+	prefs := s.myApp.Preferences()
+	profileSelect := widget.NewSelect(redact.Names(profiles), func(name string) {
+		prefs.SetString(PrefExportProfile, name)
+	})
+	if p, ok := redact.Find(profiles, prefs.StringWithFallback(PrefExportProfile, redact.ProfileCustomerHandover)); ok {
+		profileSelect.SetSelected(p.Name)
+	} else {
+		profileSelect.SetSelectedIndex(0)
+	}
+	selectedProfile := func() redact.Profile {
+		p, _ := redact.Find(profiles, profileSelect.Selected)
+		return p
+	}
+	previewBtn := widget.NewButtonWithIcon(ExportProfilePreview, theme.VisibilityIcon(), userActivity.Tap(func() {
+		s.showExportPreview(selectedProfile(), clients)
+	}))
+	profileRow := container.NewBorder(nil, nil, widget.NewLabel(ExportProfileLabel), previewBtn, profileSelect)
+
+	passphrase, err := GeneratePassword(PasswordGeneratorConfig{
+		Length:       ExportPassphraseLength,
+		UseUppercase: true,
+		UseLowercase: true,
+		UseNumbers:   true,
+	})
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+
+	info := widget.NewLabel(ExportSealInfo)
+	info.Wrapping = fyne.TextWrapWord
+
+	passEntry := widget.NewEntry()
+	passEntry.SetText(passphrase)
+	passEntry.Validator = func(text string) error {
+		if len([]rune(text)) < ExportPassphraseMinLength {
+			return fmt.Errorf(UnlockMsgTooShort, ExportPassphraseMinLength)
+		}
+		return nil
+	}
+	passHint := widget.NewLabel(ExportSealPassphraseHint)
+	passHint.Wrapping = fyne.TextWrapWord
+	passBox := container.NewVBox(passEntry, passHint)
+
+	keysEntry := widget.NewMultiLineEntry()
+	keysEntry.SetPlaceHolder("age1...")
+	keysEntry.SetMinRowsVisible(3)
+	keysHint := widget.NewLabel(ExportSealRecipientHint)
+	keysHint.Wrapping = fyne.TextWrapWord
+	keysBox := container.NewVBox(keysEntry, keysHint)
+	keysBox.Hide()
+
+	mode := widget.NewRadioGroup([]string{ExportSealPassphrase, ExportSealRecipient}, func(selected string) {
+		if selected == ExportSealRecipient {
+			passBox.Hide()
+			keysBox.Show()
+		} else {
+			keysBox.Hide()
+			passBox.Show()
+		}
+	})
+	mode.Required = true
+	mode.SetSelected(ExportSealPassphrase)
+
+	content := container.NewVBox(profileRow, widget.NewSeparator(), info, mode, passBox, keysBox)
+	d := dialog.NewCustomConfirm(ExportSealTitle, ExportSealButton, "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		redacted, _, err := selectedProfile().Apply(clients)
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+
+		var sealed []byte
+		byPassphrase := mode.Selected == ExportSealPassphrase
+		if byPassphrase {
+			if err = passEntry.Validate(); err == nil {
+				sealed, err = exchange.SealWithPassphrase(redacted, passEntry.Text)
+			}
+		} else {
+			sealed, err = exchange.SealToRecipients(redacted, strings.Split(keysEntry.Text, "\n"))
+		}
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+
+		filename, err := nativeDialog.File().
+			Title(DialogTitleSaveData).
+			Filter(ExportFileFilter, strings.TrimPrefix(exchange.Extension, ".")).
+			SetStartFile(startName + exchange.Extension).
+			Save()
+		if err != nil {
+			// Kullanıcı iptal etti
+			return
+		}
+		if err := store.WriteFile(filename, sealed, store.VaultPerm); err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+
+		if byPassphrase {
+			copySecret(s.window, passEntry.Text)
+			dialog.ShowInformation(DialogTitleSuccess, DialogMsgExportSealed, s.window)
+			return
+		}
+		dialog.ShowInformation(DialogTitleSuccess, DialogMsgExportSealedTo, s.window)
+	}, s.window)
+	d.Resize(fyne.NewSize(560, 0))
+	d.Show()
+}
+
+// readImportFile opens an export file and passes its clients to then.
+// Mühürlü dosyalar passphrase sorularak veya bu bilgisayarın anahtarıyla açılır;
+// eski ortak anahtarlı JSON export'lar da okunur.
+func (s *AppState) readImportFile(filename string, then func([]Client)) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf(DialogMsgFileReadError+": %v", err), s.window)
+		return
+	}
+	name := filepath.Base(filename)
+
+	if !exchange.IsSealed(data) {
+		clients, err := exchange.OpenLegacy(data)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf(DialogMsgJSONReadError+": %v", err), s.window)
+			return
+		}
+		then(clients)
+		return
+	}
+
+	needsPassphrase, err := exchange.NeedsPassphrase(data)
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	if !needsPassphrase {
+		identity, err := s.exportIdentity(false)
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		if identity == nil {
+			dialog.ShowError(fmt.Errorf(DialogMsgNoExportKey, name), s.window)
+			return
+		}
+		clients, err := exchange.OpenWithIdentity(data, identity)
+		if err != nil {
+			s.showExportOpenError(name, err)
+			return
+		}
+		then(clients)
+		return
+	}
+
+	passEntry := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		{Text: "", Widget: widget.NewLabel(fmt.Sprintf(ImportPassphrasePrompt, name))},
+		{Text: "Passphrase:", Widget: passEntry},
+	}
+	d := dialog.NewForm(ImportPassphraseTitle, "Open", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		progress := dialog.NewCustomWithoutButtons(ImportPassphraseTitle, widget.NewProgressBarInfinite(), s.window)
+		progress.Show()
+
+		// scrypt bilerek yavaştır; UI donmasın diye arka planda
+		go func() {
+			clients, err := exchange.OpenWithPassphrase(data, passEntry.Text)
+			fyne.Do(func() {
+				progress.Hide()
+				if err != nil {
+					s.showExportOpenError(name, err)
+					return
+				}
+				then(clients)
+			})
+		}()
+	}, s.window)
+	d.Resize(fyne.NewSize(UnlockFormWidth, 0))
+	d.Show()
+	s.window.Canvas().Focus(passEntry)
+}
+
+// showExportOpenError reports a sealed export that could not be opened
+func (s *AppState) showExportOpenError(name string, err error) {
+	if errors.Is(err, exchange.ErrWrongKey) {
+		err = fmt.Errorf(DialogMsgWrongExportKey, name)
+	}
+	dialog.ShowError(err, s.window)
+}
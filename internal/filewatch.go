@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"clientinfo/internal/backup"
+	"clientinfo/internal/merge"
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// errChangedOnDisk blocks a save that would overwrite someone else's changes
+var errChangedOnDisk = errors.New(DialogMsgChangedOnDisk)
+
+// markSynced records the vault file and client list as they are on disk now.
+// Kayıtlı liste, dışarıdan gelen değişiklikle birleştirmede ortak tabandır.
+func (s *AppState) markSynced() {
+	s.syncedClients = vault.CloneClients(s.clients)
+	if fp, err := store.FingerprintOf(s.currentFile); err == nil {
+		s.syncedFile = fp
+	}
+}
+
+// changedOnDisk reports whether the vault file was written by someone else since it was loaded or saved.
+// Dosya okunamıyorsa değişmemiş sayılır; kayıt kendi hatasını verir.
+func (s *AppState) changedOnDisk() (store.Fingerprint, bool) {
+	current, changed, err := s.syncedFile.Changed(s.currentFile)
+	if err != nil {
+		return current, false
+	}
+	return current, changed
+}
+
+// checkDisk is called before every save; dosya dışarıdan değiştiyse kayıt yapılmaz,
+// yeniden yükleme veya birleştirme önerilir
+func (s *AppState) checkDisk() error {
+	if _, changed := s.changedOnDisk(); !changed {
+		return nil
+	}
+	if !s.conflictOpen {
+		s.onExternalChange()
+	}
+	return errChangedOnDisk
+}
+
+// hasPendingEdits reports whether the clients differ from what was last loaded or saved
+func (s *AppState) hasPendingEdits() bool {
+	return len(backup.Diff(s.syncedClients, s.clients)) > 0
+}
+
+// startFileWatch polls the vault file once for the lifetime of the window.
+// Paylaşılan ağ sürücülerinde dosya sistemi bildirimleri güvenilir olmadığından
+// değişiklik zamanı ve hash ile yoklanır.
+func (s *AppState) startFileWatch() {
+	if s.fileWatchStarted {
+		return
+	}
+	s.fileWatchStarted = true
+
+	go func() {
+		ticker := time.NewTicker(FileWatchInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(func() {
+				if s.vaultKey == nil || s.conflictOpen {
+					return
+				}
+				current, changed := s.changedOnDisk()
+				if changed && current != s.dismissedFile {
+					s.onExternalChange()
+				}
+			})
+		}
+	}()
+}
+
+// onExternalChange offers to reload the file, or to merge when there are unsaved local edits
+func (s *AppState) onExternalChange() {
+	current, _ := s.changedOnDisk()
+	s.conflictOpen = true
+
+	if s.hasPendingEdits() {
+		s.mergeExternalChange(current)
+		return
+	}
+
+	msg := fmt.Sprintf(ExternalChangeReload, filepath.Base(s.currentFile))
+	dialog.ShowConfirm(ExternalChangeTitle, msg, func(ok bool) {
+		s.conflictOpen = false
+		if !ok {
+			// Aynı değişiklik için tekrar sorulmaz; sonraki kayıt yine birleştirme ister
+			s.dismissedFile = current
+			return
+		}
+		if err := s.reloadFromDisk(); err != nil {
+			s.showDiskError(err)
+		}
+	}, s.window)
+}
+
+// reloadFromDisk replaces the clients with the file on disk, keeping the current key
+func (s *AppState) reloadFromDisk() error {
+	// setVault anahtarın yeni bir kopyasını alır; eskisi bellekten silinir
+	previous := s.vaultKey
+	if err := s.loadClientsWithKey(s.currentFile, append([]byte(nil), previous...)); err != nil {
+		return err
+	}
+	for i := range previous {
+		previous[i] = 0
+	}
+	s.refreshAfterHistoryChange()
+	return nil
+}
+
+// readDiskClients opens the vault file separately from the open store and decrypts it with the current key
+func (s *AppState) readDiskClients() ([]Client, error) {
+	st, err := store.Open(s.currentFile)
+	if err != nil {
+		return nil, err
+	}
+	defer st.Close()
+	unlocked, err := st.Unlock(&vault.MigrationContext{Key: s.vaultKey})
+	if err != nil {
+		return nil, err
+	}
+	return unlocked.Clients, nil
+}
+
+// mergeExternalChange three-way merges the local edits with the file on disk.
+// Çakışma yoksa sonuç doğrudan kaydedilir, varsa alan alan seçim dialogu açılır.
+func (s *AppState) mergeExternalChange(current store.Fingerprint) {
+	remote, err := s.readDiskClients()
+	if err != nil {
+		s.conflictOpen = false
+		s.showDiskError(err)
+		return
+	}
+
+	result := merge.Clients(s.syncedClients, s.clients, remote)
+	if len(result.Conflicts) == 0 {
+		s.applyMerge(result, current, remote)
+		return
+	}
+	s.showConflicts(result, current, remote)
+}
+
+// applyMerge installs the merged clients and saves them over the file they were merged with
+func (s *AppState) applyMerge(result *merge.Result, current store.Fingerprint, remote []Client) {
+	s.conflictOpen = false
+	merged, err := result.Clients()
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	// Diğer taraftan gelen ID'siz kayıtlara ID verilir
+	model.EnsureIDs(merged)
+
+	s.syncedFile = current
+	s.syncedClients = remote
+	s.clients = merged
+	s.refreshAfterHistoryChange()
+
+	if err := s.saveClients(); err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	dialog.ShowInformation(ExternalChangeTitle, DialogMsgExternalMerged, s.window)
+}
+
+// showConflicts lets the user pick a side for every conflicting field
+func (s *AppState) showConflicts(result *merge.Result, current store.Fingerprint, remote []Client) {
+	info := widget.NewLabel(ConflictInfo)
+	info.Wrapping = fyne.TextWrapWord
+
+	rows := container.NewVBox()
+	radios := make([]*widget.RadioGroup, len(result.Conflicts))
+	for i, c := range result.Conflicts {
+		mine := fmt.Sprintf(ConflictKeepMine, conflictValue(c, c.Local))
+		theirs := fmt.Sprintf(ConflictTakeTheirs, conflictValue(c, c.Remote))
+		radios[i] = widget.NewRadioGroup([]string{mine, theirs}, nil)
+		radios[i].Required = true
+		radios[i].SetSelected(mine)
+
+		title := c.Company
+		if c.Path != "" {
+			title += " — " + c.Path
+		}
+		heading := widget.NewLabel(title)
+		heading.TextStyle = fyne.TextStyle{Bold: true}
+		rows.Add(heading)
+		rows.Add(radios[i])
+		rows.Add(widget.NewSeparator())
+	}
+
+	content := container.NewBorder(info, nil, nil, nil, container.NewVScroll(rows))
+	d := dialog.NewCustomConfirm(ConflictTitle, ConflictApply, "Cancel", content, func(ok bool) {
+		if !ok {
+			// Düzenlemeler bellekte kalır; sonraki kayıt birleştirmeyi yeniden açar
+			s.conflictOpen = false
+			s.dismissedFile = current
+			return
+		}
+		for i := range result.Conflicts {
+			result.Conflicts[i].Choice = merge.Local
+			if radios[i].Selected != radios[i].Options[0] {
+				result.Conflicts[i].Choice = merge.Remote
+			}
+		}
+		s.applyMerge(result, current, remote)
+	}, s.window)
+	d.Resize(fyne.NewSize(720, 480))
+	d.Show()
+}
+
+// conflictValue renders one side of a conflict; gizli alanlar maskelenir
+func conflictValue(c merge.Conflict, v *string) string {
+	switch {
+	case v == nil && c.Path == "":
+		return ConflictClientDeleted
+	case v == nil:
+		return ConflictFieldAbsent
+	case c.Path == "":
+		return ConflictClientChanged
+	case c.Secret():
+		return "••••"
+	}
+
+	text := *v
+	var plain string
+	if err := json.Unmarshal([]byte(text), &plain); err == nil {
+		text = plain
+	}
+	if r := []rune(text); len(r) > ConflictValueMaxLength {
+		text = string(r[:ConflictValueMaxLength]) + "…"
+	}
+	return text
+}
+
+// showDiskError reports a failed reload or merge; anahtar değiştiyse vault kilitlenir
+func (s *AppState) showDiskError(err error) {
+	if errors.Is(err, vault.ErrWrongPassword) {
+		msg := fmt.Sprintf(ExternalChangeKeyChanged, filepath.Base(s.currentFile))
+		s.lockVault()
+		dialog.ShowError(errors.New(msg), s.window)
+		return
+	}
+	dialog.ShowError(err, s.window)
+}
@@ -1,35 +1,35 @@
 package main
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"runtime"
+	"path/filepath"
 	"strings"
-
+	"time"
+
+	"clientinfo/internal/exchange"
+	"clientinfo/internal/history"
+	"clientinfo/internal/launch"
+	"clientinfo/internal/model"
+	"clientinfo/internal/sshclient"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
 	nativeDialog "github.com/sqweek/dialog"
 )
 
 // filterClients filtreler client listesini arama sorgusuna göre
 func (s *AppState) filterClients(query string) {
-	query = strings.ToLower(strings.TrimSpace(query))
-
-	if query == "" {
+	if strings.TrimSpace(query) == "" {
 		s.filteredClients = make([]Client, len(s.clients))
 		copy(s.filteredClients, s.clients)
 	} else {
 		s.filteredClients = []Client{}
 		for _, client := range s.clients {
-			if strings.Contains(strings.ToLower(client.Company), query) ||
-				strings.Contains(strings.ToLower(client.EBSVersion), query) ||
-				strings.Contains(strings.ToLower(client.Notes), query) {
+			if store.MatchesQuery(client, query) {
 				s.filteredClients = append(s.filteredClients, client)
 			}
 		}
@@ -38,7 +38,7 @@ func (s *AppState) filterClients(query string) {
 	s.buildAccordion()
 }
 
-// openFile dosya açma dialogu gösterir ve client'ları yükler
+// openFile dosya açma dialogu gösterir ve seçilen vault için master password ister
 func (s *AppState) openFile() {
 	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
 		if err != nil || reader == nil {
@@ -47,15 +47,124 @@ func (s *AppState) openFile() {
 		defer reader.Close()
 
 		path := reader.URI().Path()
-		if err := s.loadClients(path); err != nil {
+		status, err := store.DetectStatus(path)
+		if err != nil {
 			dialog.ShowError(err, s.window)
 			return
 		}
 
-		s.filterClients("")
-		s.searchEntry.SetText("")
-		dialog.ShowInformation(DialogTitleSuccess, DialogMsgFileLoaded, s.window)
+		onUnlocked := func() {
+			dialog.ShowInformation(DialogTitleSuccess, DialogMsgFileLoaded, s.window)
+		}
+		if status == vault.StatusProtected && s.unlockFromKeyring(path) {
+			s.showMainUI()
+			onUnlocked()
+			return
+		}
+		s.showUnlockScreen(path, status, onUnlocked)
+	}, s.window)
+}
+
+// toggleWholeFileEncryption alan bazlı şifreleme ile tüm dosya şifreleme modu arasında geçiş yapar
+func (s *AppState) toggleWholeFileEncryption() {
+	if s.store == nil {
+		return
+	}
+	previous := s.vaultCipher
+	next := vault.CipherXChaCha
+	msg := DialogMsgWholeFileEnabled
+	if previous == vault.CipherXChaCha {
+		next = vault.CipherAESGCM
+		msg = DialogMsgWholeFileDisabled
+	}
+
+	// SQLite backend tüm dosya şifrelemeyi desteklemez; SetKeys burada reddeder
+	if err := s.store.SetKeys(store.Keys{KDF: s.vaultKDF, Key: s.vaultKey, Cipher: next}); err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	s.vaultCipher = next
+
+	if err := s.saveClients(); err != nil {
+		s.vaultCipher = previous
+		s.store.SetKeys(s.storeKeys())
+		dialog.ShowError(err, s.window)
+		return
+	}
+
+	dialog.ShowInformation(DialogTitleSuccess, msg, s.window)
+}
+
+// forgetRememberedKey bu bilgisayarda keyring'de saklanan vault anahtarını siler
+func (s *AppState) forgetRememberedKey() {
+	if err := s.forgetVaultKey(s.currentFile); err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	dialog.ShowInformation(DialogTitleSuccess, DialogMsgKeyForgotten, s.window)
+}
+
+// showChangeMasterPassword master password değiştirme / anahtar rotasyonu dialogunu gösterir
+func (s *AppState) showChangeMasterPassword() {
+	currentEntry := widget.NewPasswordEntry()
+	newEntry := widget.NewPasswordEntry()
+	newEntry.Validator = func(text string) error {
+		if len([]rune(text)) < MasterPasswordMinLength {
+			return fmt.Errorf(UnlockMsgTooShort, MasterPasswordMinLength)
+		}
+		return nil
+	}
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.Validator = func(text string) error {
+		if text != newEntry.Text {
+			return errors.New(UnlockMsgMismatch)
+		}
+		return nil
+	}
+
+	info := widget.NewLabel(RotateKeyInfo)
+	info.Wrapping = fyne.TextWrapWord
+
+	items := []*widget.FormItem{
+		{Text: "", Widget: info},
+		{Text: "Current password:", Widget: currentEntry},
+		{Text: "New password:", Widget: newEntry},
+		{Text: "Repeat new password:", Widget: confirmEntry},
+	}
+
+	d := dialog.NewForm(RotateKeyTitle, RotateKeyButton, "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		progress := dialog.NewCustomWithoutButtons(RotateKeyWorking, widget.NewProgressBarInfinite(), s.window)
+		progress.Show()
+
+		// Argon2id iki kez çalışır (eski ve yeni anahtar), UI donmasın diye arka planda
+		go func() {
+			backupPath, err := s.rotateVaultKey(currentEntry.Text, newEntry.Text)
+
+			fyne.Do(func() {
+				progress.Hide()
+				if err != nil && backupPath == "" {
+					if errors.Is(err, vault.ErrWrongPassword) {
+						err = errors.New(UnlockMsgWrongPassword)
+					}
+					dialog.ShowError(err, s.window)
+					return
+				}
+
+				msg := fmt.Sprintf(DialogMsgKeyRotated, filepath.Base(backupPath))
+				if err != nil {
+					// Rotasyon tamamlandı, sadece keyring güncellenemedi
+					msg += "\n\n" + err.Error()
+				}
+				dialog.ShowInformation(DialogTitleSuccess, msg, s.window)
+			})
+		}()
 	}, s.window)
+	d.Resize(fyne.NewSize(UnlockFormWidth+120, 0))
+	d.Show()
 }
 
 // addClient yeni firma ekleme dialogu gösterir
@@ -88,6 +197,7 @@ func (s *AppState) addClient() {
 		}
 
 		newClient := Client{
+			ID:         model.NewID(),
 			Company:    companyEntry.Text,
 			EBSVersion: ebsSelect.Selected,
 			Notes:      notesEntry.Text,
@@ -97,7 +207,7 @@ func (s *AppState) addClient() {
 		s.clients = append(s.clients, newClient)
 		s.filterClients(s.searchEntry.Text)
 
-		if err := s.saveClients(); err != nil {
+		if err := s.saveClient(len(s.clients) - 1); err != nil {
 			dialog.ShowError(err, s.window)
 			return
 		}
@@ -110,8 +220,9 @@ func (s *AppState) addClient() {
 }
 
 // editClient firma düzenleme dialogu gösterir
-func (s *AppState) editClient(index int) {
-	if index >= len(s.clients) {
+func (s *AppState) editClient(clientID string) {
+	index := model.IndexByID(s.clients, clientID)
+	if index < 0 {
 		return
 	}
 
@@ -139,13 +250,15 @@ func (s *AppState) editClient(index int) {
 			return
 		}
 
-		s.clients[index].Company = companyEntry.Text
-		s.clients[index].EBSVersion = ebsEntry.Text
-		s.clients[index].Notes = notesEntry.Text
+		err := s.applyClientEdit(clientID, func(c *Client) {
+			c.Company = companyEntry.Text
+			c.EBSVersion = ebsEntry.Text
+			c.Notes = notesEntry.Text
+		})
 
 		s.filterClients(s.searchEntry.Text)
 
-		if err := s.saveClients(); err != nil {
+		if err != nil {
 			dialog.ShowError(err, s.window)
 			return
 		}
@@ -155,8 +268,9 @@ func (s *AppState) editClient(index int) {
 }
 
 // deleteClient firma silme onay dialogu gösterir
-func (s *AppState) deleteClient(index int) {
-	if index >= len(s.clients) {
+func (s *AppState) deleteClient(clientID string) {
+	index := model.IndexByID(s.clients, clientID)
+	if index < 0 {
 		return
 	}
 
@@ -170,10 +284,15 @@ func (s *AppState) deleteClient(index int) {
 				return
 			}
 
-			s.clients = append(s.clients[:index], s.clients[index+1:]...)
+			// Dialog açıkken liste değişmiş olabilir; client yeniden ID ile bulunur
+			index := model.IndexByID(s.clients, clientID)
+			if index < 0 {
+				return
+			}
+			// Önce store'dan silinir; başarısız olursa client listede kalır. Silme Ctrl+Z ile geri alınabilir
+			err := s.removeClient(index, history.ActionDelete, 0)
 			s.filterClients(s.searchEntry.Text)
-
-			if err := s.saveClients(); err != nil {
+			if err != nil {
 				dialog.ShowError(err, s.window)
 				return
 			}
@@ -184,49 +303,16 @@ func (s *AppState) deleteClient(index int) {
 	)
 }
 
-// exportClientForCustomer müşteri için VPN bilgileri olmadan export eder (şifreli)
-func (s *AppState) exportClientForCustomer(index int) {
-	if index >= len(s.clients) {
-		return
-	}
-
-	client := s.clients[index]
-
-	// VPN bilgilerini temizle
-	clientCopy := client
-	clientCopy.VPN = VPNInfo{}
-
-	// Şifreleme yap (export dosyasında da şifre tutulsun)
-	if err := encryptClientsInPlace([]Client{clientCopy}); err != nil {
-		dialog.ShowError(fmt.Errorf("encryption error: %w", err), s.window)
-		return
-	}
-
-	// Native Windows dialog kullan
-	filename, err := nativeDialog.File().
-		Title(DialogTitleSaveData).
-		Filter("JSON File", "json").
-		SetStartFile(client.Company + "_export.json").
-		Save()
-
-	if err != nil {
-		// Kullanıcı iptal etti
+// exportClientForCustomer müşteriyi seçilen export profiline göre export eder (şifreli)
+func (s *AppState) exportClientForCustomer(clientID string) {
+	index := model.IndexByID(s.clients, clientID)
+	if index < 0 {
 		return
 	}
 
-	// JSON'a çevir ve kaydet
-	data, err := json.MarshalIndent([]Client{clientCopy}, "", "  ")
-	if err != nil {
-		dialog.ShowError(err, s.window)
-		return
-	}
-
-	if err := os.WriteFile(filename, data, 0644); err != nil {
-		dialog.ShowError(err, s.window)
-		return
-	}
-
-	dialog.ShowInformation(DialogTitleSuccess, DialogMsgDataExported, s.window)
+	// Profil (VPN'siz, gizli alansız...) ve passphrase/alıcı anahtarı diyalogda seçilir
+	exported := vault.CloneClients(s.clients[index : index+1])
+	s.showSealExport(exported, exported[0].Company+"_export")
 }
 
 // importClientFromCustomer müşteriden gelen JSON'u import eder (VPN bilgisi eklemeden)
@@ -234,7 +320,7 @@ func (s *AppState) importClientFromCustomer() {
 	// Native Windows dialog kullan
 	filename, err := nativeDialog.File().
 		Title(DialogTitleOpenData).
-		Filter("JSON File", "json").
+		Filter(ExportFileFilter, strings.TrimPrefix(exchange.Extension, "."), "json").
 		Load()
 
 	if err != nil {
@@ -242,34 +328,18 @@ func (s *AppState) importClientFromCustomer() {
 		return
 	}
 
-	// Dosyayı oku
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		dialog.ShowError(fmt.Errorf(DialogMsgFileReadError+": %v", err), s.window)
-		return
-	}
-
-	// Try to unmarshal into an array first (export may contain []Client)
-	var importedClients []Client
-	if err := json.Unmarshal(data, &importedClients); err != nil {
-		// Fallback: try single Client object
-		var single Client
-		if err2 := json.Unmarshal(data, &single); err2 != nil {
-			dialog.ShowError(fmt.Errorf(DialogMsgJSONReadError+": %v", err), s.window)
-			return
-		}
-		importedClients = []Client{single}
-	}
-
-	// Şifreli alanları decrypt et (eğer şifreliyse)
-	if err := decryptClientsInPlace(importedClients); err != nil {
-		dialog.ShowError(fmt.Errorf("decrypt error: %v", err), s.window)
-		return
-	}
+	// Dosya mühürlüyse passphrase veya bu bilgisayarın anahtarıyla açılır;
+	// eşleşen firmalar birleştirme sihirbazında sırayla ele alınır
+	s.readImportFile(filename, s.importNext)
+}
 
-	// Process each imported client
-	for _, imp := range importedClients {
-		client := imp // capture
+// importNext adds the next imported clients until one matches an existing client,
+// opens the merge wizard for it and continues with the rest once it closes
+func (s *AppState) importNext(queue []Client) {
+	added := 0
+	for len(queue) > 0 {
+		client := queue[0]
+		queue = queue[1:]
 
 		// Firma adını kontrol et
 		if strings.TrimSpace(client.Company) == "" {
@@ -278,127 +348,63 @@ func (s *AppState) importClientFromCustomer() {
 		}
 
 		// Mevcut firmayı kontrol et
-		found := false
-		foundIndex := -1
-		for i, c := range s.clients {
-			if c.Company == client.Company {
-				found = true
-				foundIndex = i
-				break
-			}
+		if foundIndex := s.importMatch(client); foundIndex >= 0 {
+			rest := queue
+			s.showImportMerge(s.clients[foundIndex].ID, client, func() { s.importNext(rest) })
+			return
 		}
 
-		if found {
-			// capture index and client for closure
-			idx := foundIndex
-			localClient := client
-			dialog.ShowConfirm(
-				"Customer Already Exists",
-				fmt.Sprintf("'%s' Customer Already Exists. Do you want to overwrite it?\n\nWarning: Existing VPN information will be preserved!", localClient.Company),
-				func(ok bool) {
-					if ok {
-						// Mevcut VPN bilgilerini koru
-						localClient.VPN = s.clients[idx].VPN
-						s.clients[idx] = localClient
-						s.filterClients(s.searchEntry.Text)
-						if err := s.saveClients(); err != nil {
-							dialog.ShowError(err, s.window)
-							return
-						}
-						dialog.ShowInformation(DialogTitleSuccess, "Customer updated!", s.window)
-					}
-				},
-				s.window,
-			)
-		} else {
-			// Yeni firma olarak ekle
-			s.clients = append(s.clients, client)
-			s.filterClients(s.searchEntry.Text)
-			if err := s.saveClients(); err != nil {
-				dialog.ShowError(err, s.window)
-				continue
-			}
-			dialog.ShowInformation(DialogTitleSuccess, DialogMsgDataImported, s.window)
+		// Yeni firma olarak ekle; eksik veya çakışan ID'ler yenilenir
+		s.clients = append(s.clients, client)
+		model.EnsureIDs(s.clients)
+		s.filterClients(s.searchEntry.Text)
+		if err := s.saveClient(len(s.clients) - 1); err != nil {
+			dialog.ShowError(err, s.window)
+			continue
 		}
+		added++
 	}
-}
 
-// exportAllClientsForCustomer tüm firmaları VPN bilgisi olmadan export eder (şifreli)
-func (s *AppState) exportAllClientsForCustomer() {
-	if len(s.clients) == 0 {
-		dialog.ShowInformation(DialogTitleInfo, DialogMsgNoClientsToExport, s.window)
-		return
+	// Tablo import'larında onlarca firma olabilir; tek bir bilgi mesajı yeterli
+	if added > 0 {
+		dialog.ShowInformation(DialogTitleSuccess, DialogMsgDataImported, s.window)
 	}
+}
 
-	// Tüm client'ları kopyala ve VPN bilgilerini temizle
-	clientsCopy := make([]Client, len(s.clients))
-	for i, client := range s.clients {
-		clientsCopy[i] = client
-		clientsCopy[i].VPN = VPNInfo{}
+// importMatch returns the index of the local client an imported one replaces, -1 if it is new.
+// Önce ID ile, ID'si olmayan veya bu vault'ta bulunmayan kayıtlar firma adıyla eşleşir.
+func (s *AppState) importMatch(c Client) int {
+	if i := model.IndexByID(s.clients, c.ID); i >= 0 {
+		return i
+	}
+	for i := range s.clients {
+		if s.clients[i].Company == c.Company {
+			return i
+		}
 	}
+	return -1
+}
 
-	// Şifreleme yap (export dosyasında da şifreler tutulsun)
-	if err := encryptClientsInPlace(clientsCopy); err != nil {
-		dialog.ShowError(fmt.Errorf("encryption error: %w", err), s.window)
+// exportAllClientsForCustomer tüm firmaları seçilen export profiline göre export eder (şifreli)
+func (s *AppState) exportAllClientsForCustomer() {
+	if len(s.clients) == 0 {
+		dialog.ShowInformation(DialogTitleInfo, DialogMsgNoClientsToExport, s.window)
 		return
 	}
 
-	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
-		if err != nil || writer == nil {
-			return
-		}
-		defer writer.Close()
-
-		data, err := json.MarshalIndent(clientsCopy, "", "  ")
-		if err != nil {
-			dialog.ShowError(err, s.window)
-			return
-		}
-
-		if _, err := writer.Write(data); err != nil {
-			dialog.ShowError(err, s.window)
-			return
-		}
-
-		dialog.ShowInformation(DialogTitleSuccess, fmt.Sprintf(DialogMsgExportForClient, len(clientsCopy)), s.window)
-	}, s.window)
-
-	saveDialog.SetFileName("all_clients_export.json")
-	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
-	saveDialog.Show()
+	s.showSealExport(vault.CloneClients(s.clients), "all_clients_export")
 }
 
 // addApp boş yeni ortam ekler
-func (s *AppState) addApp(filteredIndex int) {
+func (s *AppState) addApp(clientID string) {
 	if len(s.clients) == 0 {
 		dialog.ShowInformation(DialogTitleInfo, DialogMsgAddClientFirst, s.window)
 		return
 	}
 
-	if filteredIndex < 0 || filteredIndex >= len(s.filteredClients) {
-		dialog.ShowError(errors.New(DialogMsgInvalidClientSelection), s.window)
-		return
-	}
-
-	// filteredClients'tan firma adını al
-	companyName := s.filteredClients[filteredIndex].Company
-
-	// Gerçek clients dizisinde firma adına göre bul
-	realClientIndex := -1
-	for i, c := range s.clients {
-		if c.Company == companyName {
-			realClientIndex = i
-			break
-		}
-	}
-
-	if realClientIndex == -1 {
-		dialog.ShowError(errors.New(DialogMsgClientNotFound), s.window)
-		return
-	}
-
 	// Seçili client'a boş ortam ekle
 	newApp := AppInfo{
+		ID:           model.NewID(),
 		Type:         "TEST",
 		Name:         "Yeni Ortam",
 		AppServerURI: "",
@@ -406,11 +412,8 @@ func (s *AppState) addApp(filteredIndex int) {
 		AppUsers:     []string{}, // Boş slice ile başlat
 	}
 
-	// Seçili client'a ekle
-	s.clients[realClientIndex].Apps = append(s.clients[realClientIndex].Apps, newApp)
-
-	// Sadece kaydet ve UI'ı yenile
-	if err := s.saveClients(); err != nil {
+	// Seçili client'a ekle, kaydet ve UI'ı yenile
+	if err := s.applyClientEdit(clientID, func(c *Client) { c.Apps = append(c.Apps, newApp) }); err != nil {
 		dialog.ShowError(err, s.window)
 		return
 	}
@@ -420,33 +423,17 @@ func (s *AppState) addApp(filteredIndex int) {
 }
 
 // deleteApp ortamı siler
-func (s *AppState) deleteApp(filteredIndex, appIndex int) {
-	if filteredIndex < 0 || filteredIndex >= len(s.filteredClients) {
-		return
-	}
-
-	// filteredClients'tan firma adını al
-	companyName := s.filteredClients[filteredIndex].Company
-
-	// Gerçek clients dizisinde firma adına göre bul
-	realClientIndex := -1
-	for i, c := range s.clients {
-		if c.Company == companyName {
-			realClientIndex = i
-			break
-		}
-	}
-
-	if realClientIndex == -1 {
+func (s *AppState) deleteApp(clientID, appID string) {
+	index := model.IndexByID(s.clients, clientID)
+	if index < 0 {
 		return
 	}
-
-	client := &s.clients[realClientIndex]
-	if appIndex < 0 || appIndex >= len(client.Apps) {
+	app := s.clients[index].AppByID(appID)
+	if app == nil {
 		return
 	}
 
-	appName := client.Apps[appIndex].Name
+	appName := app.Name
 
 	// Onay dialogu göster
 	dialog.ShowConfirm(DialogTitleDeleteEnv,
@@ -456,75 +443,104 @@ func (s *AppState) deleteApp(filteredIndex, appIndex int) {
 				return
 			}
 
-			// Ortamı listeden çıkar
-			client.Apps = append(client.Apps[:appIndex], client.Apps[appIndex+1:]...)
-
-			// Kaydet ve UI'ı yenile
-			if err := s.saveClients(); err != nil {
+			// Ortamı listeden çıkar, kaydet ve UI'ı yenile
+			// Ortam ID ile bulunur; dialog açıkken sıra değişmiş olabilir
+			err := s.applyClientEdit(clientID, func(c *Client) {
+				if i := c.AppIndex(appID); i >= 0 {
+					c.Apps = append(c.Apps[:i], c.Apps[i+1:]...)
+				}
+			})
+			if err != nil {
 				dialog.ShowError(err, s.window)
 				return
 			}
+			s.stopAppTunnels(appID)
 
 			// Filtreyi yeniden uygula
 			s.filterClients(s.searchEntry.Text)
 		}, s.window)
 }
 
-// openSSHShell SSH shell'i açar
-func (s *AppState) openSSHShell(app AppInfo) {
+// openSSHShell SSH shell'i harici terminalde açar.
+// ssh komutu argv olarak kurulur; SSH params hiçbir zaman bir kabuk satırına eklenmez.
+func (s *AppState) openSSHShell(c Client, app AppInfo) {
 	// Validasyon: IP ve User gerekli
 	if strings.TrimSpace(app.AppServerIP) == "" || strings.TrimSpace(app.AppServerUser) == "" {
 		dialog.ShowInformation(DialogTitleSSH, DialogMsgSSHConfig, s.window)
 		return
 	}
-
-	// SSH komutunu oluştur - SSHParams varsa ekle
-	sshCmd := fmt.Sprintf("ssh %s@%s", app.AppServerUser, app.AppServerIP)
-	if strings.TrimSpace(app.SSHParams) != "" {
-		sshCmd = fmt.Sprintf("ssh %s %s@%s", app.SSHParams, app.AppServerUser, app.AppServerIP)
+	params, err := appSSHParams(app)
+	if err != nil {
+		dialog.ShowInformation(DialogTitleSSH, err.Error(), s.window)
+		return
 	}
-
-	// Şifreyi önceden panoya kopyala ki kullanıcı her koşulda hazır bulunsun
-	if s.window != nil {
-		s.window.Clipboard().SetContent(app.AppServerPass)
+	// Bastion zinciri -J ile geçilir; ara sunucularda parola gerekirse ssh kendisi sorar
+	chain, err := c.BastionChain(app.Bastion)
+	if err != nil {
+		dialog.ShowInformation(DialogTitleSSH, err.Error(), s.window)
+		return
+	}
+	var jumps []string
+	for _, b := range chain {
+		jump, err := bastionJump(b)
+		if err != nil {
+			dialog.ShowInformation(DialogTitleSSH, err.Error(), s.window)
+			return
+		}
+		jumps = append(jumps, jump)
 	}
+	params.JumpHosts = append(jumps, params.JumpHosts...)
 
-	// Platform'a göre terminal aç
-	if runtime.GOOS == "windows" {
-		// Windows: cmd ile SSH aç, yeni window'da, exit yazınca window kapatılır
-		// "start" komutu yeni window açar
-		// "/c" flag'ı: komut bitince window'u kapat
-		psCmd := fmt.Sprintf("start cmd /c %s", sshCmd)
+	// ssh yalnızca ortamın ve bastion'ların kayıtlı anahtarlarına güvenir; ~/.ssh/known_hosts'a yazmaz ve sormaz
+	pinned, err := externalKnownHosts(c, app)
+	if err != nil {
+		dialog.ShowInformation(DialogTitleSSH, err.Error(), s.window)
+		return
+	}
+	params.PinHostKeys(pinned)
+	argv, err := sshclient.Command(params, app.AppServerUser, app.AppServerIP)
+	if err != nil {
+		pinned.Remove()
+		dialog.ShowInformation(DialogTitleSSH, err.Error(), s.window)
+		return
+	}
 
-		cmd := exec.Command("cmd", "/c", psCmd)
-		if err := cmd.Start(); err != nil {
-			// Hata: şifreyi panoya kopyala
-			s.window.Clipboard().SetContent(app.AppServerPass)
-			dialog.ShowInformation(DialogTitleSSH, DialogMsgSSHPasswordCopy, s.window)
+	// Şifreler panoya kopyalanmaz; ssh onları SSH_ASKPASS ile uygulamadan ister.
+	// Askpass başlatılamazsa ssh şifreyi terminalde sorar.
+	var env []string
+	session, err := s.startAskpass(sshPasswords(chain, app, len(params.JumpHosts) == len(jumps)))
+	if err == nil {
+		env = session.Env
+	}
+	if err := launch.InTerminal(argv, env); err != nil {
+		if session != nil {
+			session.Close()
 		}
-	} else if runtime.GOOS == "darwin" {
-		// macOS: Terminal.app ile aç
-		script := fmt.Sprintf("tell app \"Terminal\" to do script \"%s; exit\"", sshCmd)
-		cmd := exec.Command("osascript", "-e", script)
-		if err := cmd.Start(); err != nil {
-			// Hata: şifreyi panoya kopyala
-			s.window.Clipboard().SetContent(app.AppServerPass)
-			dialog.ShowInformation(DialogTitleSSH, DialogMsgSSHPasswordCopy, s.window)
+		pinned.Remove()
+		dialog.ShowError(fmt.Errorf(DialogMsgSSHFailed, err), s.window)
+		return
+	}
+	// ssh dosyayı her atlamanın el sıkışmasında okur; bitişini bilemediğimiz için belirli bir süre sonra silinir
+	time.AfterFunc(ExternalSSHKnownHostsLifetime, pinned.Remove)
+}
+
+// sshPasswords maps the password prompts of an ssh through chain to app's server to the stored passwords.
+// Kullanıcı adı içermeyen "Password:" istemi yalnızca araya başka sunucu girmiyorsa (direct) hedefe aittir.
+func sshPasswords(chain []Bastion, app AppInfo, direct bool) map[string]string {
+	passwords := map[string]string{}
+	add := func(user, host, password string) {
+		if user != "" && host != "" && password != "" {
+			passwords[sshPasswordPrompt(user, host)] = password
 		}
-	} else {
-		// Linux: xterm veya gnome-terminal ile aç
-		termCmd := fmt.Sprintf("%s; exit", sshCmd)
-
-		// xterm dene
-		cmd := exec.Command("xterm", "-hold", "-e", "bash", "-c", termCmd)
-		if err := cmd.Start(); err != nil {
-			// xterm başarısız, gnome-terminal dene
-			cmd = exec.Command("gnome-terminal", "--", "bash", "-c", termCmd)
-			if err := cmd.Start(); err != nil {
-				// Her iki terminal de başarısız: şifreyi panoya kopyala
-				s.window.Clipboard().SetContent(app.AppServerPass)
-				dialog.ShowInformation(DialogTitleSSH, DialogMsgSSHPasswordCopy, s.window)
-			}
+	}
+	for _, b := range chain {
+		if _, host, _, err := sshclient.SplitJumpHost(stripFallback(b.Host)); err == nil {
+			add(stripFallback(b.User), host, b.Password)
 		}
 	}
+	add(strings.TrimSpace(app.AppServerUser), strings.TrimSpace(app.AppServerIP), app.AppServerPass)
+	if direct && len(chain) == 0 && app.AppServerPass != "" {
+		passwords["Password:"] = app.AppServerPass
+	}
+	return passwords
 }
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"clientinfo/internal/history"
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// loadHistory opens the change history of the vault at path with key.
+// Okunamayan bir log kaybolmasın diye kenara kopyalanır ve boş bir log ile devam edilir.
+func (s *AppState) loadHistory(path string, key []byte) {
+	log, err := history.Load(path, key)
+	s.history = log
+	if err == nil {
+		return
+	}
+	aside := history.PathFor(path) + ".unreadable"
+	if copyErr := store.CopyFile(history.PathFor(path), aside, store.VaultPerm); copyErr != nil {
+		aside = copyErr.Error()
+	}
+	fyne.Do(func() {
+		dialog.ShowError(fmt.Errorf(DialogMsgHistoryReset, aside), s.window)
+	})
+}
+
+// saveHistory writes the change history with the current vault key
+func (s *AppState) saveHistory() error {
+	if s.history == nil || s.vaultKey == nil {
+		return nil
+	}
+	return s.history.Save(s.vaultKey)
+}
+
+// applyClientEdit runs update on the client with clientID, records the field changes and saves.
+// Alan düzenlemeleri bu yoldan geçer; böylece her değişiklik geri alınabilir.
+func (s *AppState) applyClientEdit(clientID string, update func(*Client)) error {
+	index := model.IndexByID(s.clients, clientID)
+	if index < 0 {
+		return errors.New(DialogMsgClientNotFound)
+	}
+	before := vault.CloneClients(s.clients[index : index+1])[0]
+	update(&s.clients[index])
+	return s.commitClient(index, history.ActionEdit, 0, before)
+}
+
+// commitClient writes the edited client at index and records how it changed from before.
+// Geçmiş ancak store yazması başarılı olursa kaydedilir; yazma başarısız olursa client
+// eski haline döner, böylece bellek, geçmiş ve disk ayrışmaz.
+func (s *AppState) commitClient(index int, action string, ref int64, before Client) error {
+	if err := s.putClient(index); err != nil {
+		s.clients[index] = before
+		return err
+	}
+	s.recordChanges(action, ref, before, s.clients[index])
+	return s.afterSave()
+}
+
+// removeClient deletes the client at index from the store, then from memory, and records the deletion.
+// Kayıt client'ın tüm alanlarını tutar; geri alınınca client aynı ID ile geri gelir.
+func (s *AppState) removeClient(index int, action string, ref int64) error {
+	c := s.clients[index]
+	if err := s.removeStoredClient(c.ID); err != nil {
+		return err
+	}
+	s.clients = append(s.clients[:index], s.clients[index+1:]...)
+	s.recordChanges(action, ref, c, deletedClient(c))
+	return s.afterSave()
+}
+
+// restoreClient puts back the client removed by entry, listenin sonuna
+func (s *AppState) restoreClient(entry history.Entry, action string) error {
+	c := deletedClient(Client{ID: entry.ClientID, Company: entry.Company})
+	if err := history.Apply(&c, entry.Changes, true); err != nil {
+		return err
+	}
+	s.clients = append(s.clients, c)
+	index := len(s.clients) - 1
+	if err := s.putClient(index); err != nil {
+		s.clients = s.clients[:index]
+		dialog.ShowError(err, s.window)
+		return nil
+	}
+	s.recordChanges(action, entry.ID, deletedClient(c), c)
+	s.refreshAfterHistoryChange()
+	if err := s.afterSave(); err != nil {
+		dialog.ShowError(err, s.window)
+	}
+	return nil
+}
+
+// deletedClient is what remains of c in the history after it is deleted
+func deletedClient(c Client) Client {
+	return Client{ID: c.ID, Company: c.Company}
+}
+
+// recordChanges adds the difference between before and after to the history, if any
+func (s *AppState) recordChanges(action string, ref int64, before, after Client) {
+	if s.history == nil {
+		return
+	}
+	changes := history.Compute(before, after)
+	if len(changes) == 0 && action == history.ActionEdit {
+		return
+	}
+	s.history.Record(after.ID, after.Company, action, ref, changes)
+}
+
+// clientIndexForEntry returns the index of the client entry was recorded for, -1 if missing.
+// ID'siz eski kayıtlar firma adıyla eşleşir.
+func (s *AppState) clientIndexForEntry(entry history.Entry) int {
+	for i := range s.clients {
+		if entry.Belongs(s.clients[i].ID, s.clients[i].Company) {
+			return i
+		}
+	}
+	return -1
+}
+
+// undoLastChange reverses the most recent edit (Ctrl+Z)
+func (s *AppState) undoLastChange() {
+	if s.history == nil || s.vaultKey == nil {
+		return
+	}
+	entry, ok := s.history.NextUndo()
+	if !ok {
+		return
+	}
+	if err := s.replayEntry(entry, history.ActionUndo); err != nil {
+		// Uygulanamayan adım yığında kalırsa sonraki undo'lar da takılır
+		s.history.DropUndo()
+		dialog.ShowError(err, s.window)
+	}
+}
+
+// redoLastChange re-applies the most recently undone edit (Ctrl+Y)
+func (s *AppState) redoLastChange() {
+	if s.history == nil || s.vaultKey == nil {
+		return
+	}
+	entry, ok := s.history.NextRedo()
+	if !ok {
+		return
+	}
+	if err := s.replayEntry(entry, history.ActionRedo); err != nil {
+		s.history.DropRedo()
+		dialog.ShowError(err, s.window)
+	}
+}
+
+// replayEntry undoes (ActionUndo) or re-applies (ActionRedo) entry on its client and saves.
+// Sadece adım uygulanamazsa hata döner; kayıt hatası burada gösterilir.
+func (s *AppState) replayEntry(entry history.Entry, action string) error {
+	index := s.clientIndexForEntry(entry)
+	if entry.Action == history.ActionDelete {
+		// Silme geri alınınca client döner, tekrarlanınca yeniden silinir
+		if action == history.ActionUndo {
+			if index >= 0 {
+				return fmt.Errorf(DialogMsgClientExists, entry.Company)
+			}
+			return s.restoreClient(entry, action)
+		}
+		if index >= 0 {
+			if err := s.removeClient(index, action, entry.ID); err != nil {
+				dialog.ShowError(err, s.window)
+			}
+			s.refreshAfterHistoryChange()
+			return nil
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("%s: %s", DialogMsgClientNotFound, entry.Company)
+	}
+	before := s.clients[index]
+	after := before
+	if err := history.Apply(&after, entry.Changes, action == history.ActionUndo); err != nil {
+		return err
+	}
+	s.clients[index] = after
+	err := s.commitClient(index, action, entry.ID, before)
+	s.refreshAfterHistoryChange()
+	if err != nil {
+		dialog.ShowError(err, s.window)
+	}
+	return nil
+}
+
+// revertChange puts a single field of an older entry back to its previous value
+func (s *AppState) revertChange(entry history.Entry, change history.Change) error {
+	index := s.clientIndexForEntry(entry)
+	if index < 0 {
+		return fmt.Errorf("%s: %s", DialogMsgClientNotFound, entry.Company)
+	}
+	after := s.clients[index]
+	applied, err := history.Revert(&after, change)
+	if err != nil {
+		return err
+	}
+	if applied.Path == "" {
+		// Alan zaten eski değerinde
+		return nil
+	}
+	before := s.clients[index]
+	s.clients[index] = after
+	if err := s.putClient(index); err != nil {
+		s.clients[index] = before
+		return err
+	}
+	s.history.Record(after.ID, after.Company, history.ActionRevert, entry.ID, []history.Change{applied})
+	s.refreshAfterHistoryChange()
+	return s.afterSave()
+}
+
+// refreshAfterHistoryChange rebuilds the list so the edited fields show their new values
+func (s *AppState) refreshAfterHistoryChange() {
+	if s.searchEntry != nil {
+		s.filterClients(s.searchEntry.Text)
+	}
+}
+
+// installHistoryShortcuts binds Ctrl+Z / Ctrl+Y (macOS'ta Cmd) to undo and redo.
+// Odaktaki bir Entry kendi undo'sunu kullanır; kısayol sadece canvas'a ulaşırsa çalışır.
+func (s *AppState) installHistoryShortcuts() {
+	c := s.window.Canvas()
+	undo := func(fyne.Shortcut) { s.undoLastChange() }
+	redo := func(fyne.Shortcut) { s.redoLastChange() }
+	c.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault}, undo)
+	c.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: fyne.KeyModifierShortcutDefault}, redo)
+	c.AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}, redo)
+}
+
+// createHistoryTab lists the recorded changes of a client, newest first, with a revert button per field
+func (s *AppState) createHistoryTab(client Client) *fyne.Container {
+	box := container.NewVBox()
+	s.fillHistoryTab(box, client)
+	return box
+}
+
+// fillHistoryTab (re)builds the history rows into box
+func (s *AppState) fillHistoryTab(box *fyne.Container, client Client) {
+	box.Objects = nil
+	var entries []history.Entry
+	if s.history != nil {
+		entries = s.history.ForClient(client.ID, client.Company)
+	}
+	if len(entries) == 0 {
+		box.Add(widget.NewLabel(HistoryEmpty))
+		box.Refresh()
+		return
+	}
+	if len(entries) > HistoryMaxShown {
+		entries = entries[:HistoryMaxShown]
+	}
+
+	for _, entry := range entries {
+		e := entry
+		title := widget.NewLabel(fmt.Sprintf("%s · %s · %s", e.Time.Format("2006-01-02 15:04:05"), fallback(e.User), e.Action))
+		title.TextStyle = fyne.TextStyle{Bold: true}
+		rows := container.NewVBox(title)
+
+		for _, change := range e.Changes {
+			ch := change
+			text := widget.NewLabel(fmt.Sprintf("%s: %s → %s", ch.Path, formatHistoryValue(ch, ch.Old), formatHistoryValue(ch, ch.New)))
+			text.Wrapping = fyne.TextWrapWord
+			revertBtn := widget.NewButtonWithIcon(HistoryRevertButton, theme.ContentUndoIcon(), userActivity.Tap(func() {
+				if err := s.revertChange(e, ch); err != nil {
+					dialog.ShowError(err, s.window)
+				}
+			}))
+			rows.Add(container.NewBorder(nil, nil, nil, revertBtn, text))
+		}
+		box.Add(rows)
+		box.Add(widget.NewSeparator())
+	}
+	box.Refresh()
+}
+
+// formatHistoryValue renders a JSON encoded history value; gizli alanlar maskelenir
+func formatHistoryValue(ch history.Change, raw *string) string {
+	if raw == nil {
+		return HistoryAbsent
+	}
+	var str string
+	if err := json.Unmarshal([]byte(*raw), &str); err != nil {
+		// Liste elemanı veya nesne; gizli alan içerebilir
+		if ch.Secret() {
+			return "{…}"
+		}
+		return *raw
+	}
+	if !ch.Secret() {
+		return fallback(str)
+	}
+	if strings.Contains(ch.Path, "app_users[") {
+		return model.SecretLineMask(str)
+	}
+	if str == "" {
+		return "—"
+	}
+	return "••••"
+}
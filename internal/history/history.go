@@ -0,0 +1,163 @@
+// Package history records field level changes to clients so they can be undone,
+// redone or reverted one by one.
+//
+// Her kayıt bir veya daha fazla alan değişikliği içerir; değerler JSON olarak
+// tutulur ve model paketindeki alan yollarıyla (örn. "apps[1].pass") adreslenir.
+// Log dosyası vault'un yanında, vault anahtarıyla şifreli olarak saklanır.
+package history
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"sort"
+	"time"
+
+	"clientinfo/internal/model"
+)
+
+// Actions stored in Entry.Action
+const (
+	ActionEdit   = "edit"
+	ActionUndo   = "undo"
+	ActionRedo   = "redo"
+	ActionRevert = "revert"
+	ActionDelete = "delete" // Client silindi; Changes tüm alanlarını tutar
+)
+
+// ErrConflict is returned when a change cannot be reverted because the field changed shape since
+var ErrConflict = errors.New("the field has changed since, revert it manually")
+
+// Change is a single field change. Old nil ise alan (liste elemanı) yeni eklenmiştir,
+// New nil ise silinmiştir.
+type Change struct {
+	Path string  `json:"path"`
+	Old  *string `json:"old,omitempty"`
+	New  *string `json:"new,omitempty"`
+}
+
+// Reversed returns the change that undoes c
+func (c Change) Reversed() Change {
+	return Change{Path: c.Path, Old: c.New, New: c.Old}
+}
+
+// Secret reports whether the change touches a `secret` tagged field
+func (c Change) Secret() bool {
+	return model.IsSecretPath(c.Path)
+}
+
+// Entry is one recorded operation on a client
+type Entry struct {
+	ID       int64     `json:"id"`
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	ClientID string    `json:"client_id,omitempty"` // Eski kayıtlarda boş; firma adıyla eşleşir
+	Company  string    `json:"company"`             // İşlemden sonraki firma adı
+	Action   string    `json:"action"`              // ActionEdit, ActionUndo, ActionRedo, ActionRevert veya ActionDelete
+	Ref      int64     `json:"ref,omitempty"`       // Geri alınan / tekrarlanan / revert edilen kayıt
+	Changes  []Change  `json:"changes"`
+}
+
+// Belongs reports whether e was recorded for the client with id (veya ID'siz kayıtta firma adıyla)
+func (e Entry) Belongs(id, company string) bool {
+	if e.ClientID != "" {
+		return e.ClientID == id
+	}
+	return e.Company == company
+}
+
+// Compute returns the field changes that turn before into after
+func Compute(before, after model.Client) []Change {
+	var changes []Change
+	for _, path := range model.DiffPaths(before, after) {
+		ch := Change{Path: path}
+		if v, ok := model.GetPath(before, path); ok {
+			ch.Old = &v
+		}
+		if v, ok := model.GetPath(after, path); ok {
+			ch.New = &v
+		}
+		changes = append(changes, ch)
+	}
+	return changes
+}
+
+// Apply applies changes to c, or undoes them when reverse is true.
+// Önce değer güncellemeleri, sonra silmeler (büyük indeksten küçüğe), en son
+// eklemeler (küçükten büyüğe) uygulanır; böylece liste indeksleri kaymaz.
+func Apply(c *model.Client, changes []Change, reverse bool) error {
+	var sets, deletes, inserts []Change
+	for _, ch := range changes {
+		if reverse {
+			ch = ch.Reversed()
+		}
+		switch {
+		case ch.Old != nil && ch.New != nil:
+			sets = append(sets, ch)
+		case ch.New == nil && ch.Old != nil:
+			deletes = append(deletes, ch)
+		case ch.New != nil:
+			inserts = append(inserts, ch)
+		}
+	}
+	sort.Slice(deletes, func(i, j int) bool { return model.LessPath(deletes[j].Path, deletes[i].Path) })
+	sort.Slice(inserts, func(i, j int) bool { return model.LessPath(inserts[i].Path, inserts[j].Path) })
+
+	for _, ch := range sets {
+		if err := model.SetPath(c, ch.Path, *ch.New); err != nil {
+			return err
+		}
+	}
+	for _, ch := range deletes {
+		if err := model.DeletePath(c, ch.Path); err != nil {
+			return err
+		}
+	}
+	for _, ch := range inserts {
+		if err := model.InsertPath(c, ch.Path, *ch.New); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Revert undoes a single change of an older entry on c and returns the change actually made.
+// Değer değişiklikleri her zaman eski değerine döner; eklenmiş bir eleman ancak
+// hâlâ aynı değerdeyse silinir.
+func Revert(c *model.Client, ch Change) (Change, error) {
+	back := ch.Reversed()
+	current, exists := model.GetPath(*c, ch.Path)
+	switch {
+	case back.Old != nil && back.New != nil:
+		if !exists {
+			return Change{}, ErrConflict
+		}
+		back.Old = &current
+	case back.New == nil:
+		// Eklenmiş elemanı sil
+		if !exists || current != *back.Old {
+			return Change{}, ErrConflict
+		}
+	}
+	if back.Old != nil && back.New != nil && *back.Old == *back.New {
+		return Change{}, nil
+	}
+	if err := Apply(c, []Change{back}, false); err != nil {
+		if errors.Is(err, model.ErrNoSuchPath) {
+			return Change{}, ErrConflict
+		}
+		return Change{}, err
+	}
+	return back, nil
+}
+
+// CurrentUser returns the OS user name recorded with each entry
+func CurrentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USERNAME"); name != "" {
+		return name
+	}
+	return os.Getenv("USER")
+}
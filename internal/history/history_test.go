@@ -0,0 +1,68 @@
+package history
+
+import (
+	"testing"
+
+	"clientinfo/internal/model"
+)
+
+func baseClient() model.Client {
+	return model.Client{
+		ID:      "client-1",
+		Company: "ACME",
+		Apps:    []model.AppInfo{{ID: "app-1", Type: "PROD", Name: "EBS"}},
+	}
+}
+
+// TestUndoRedoListEdges undoes and redoes edits that add the first or remove the last element of a list.
+// Listeler boşken JSON'da da bulunmalı; yoksa yol yazılamaz ve geri alma/yineleme başarısız olur.
+func TestUndoRedoListEdges(t *testing.T) {
+	withTunnel := baseClient()
+	withTunnel.Apps[0].Tunnels = []model.Tunnel{{ID: "tunnel-1", Name: "db", LocalPort: 15210, RemoteHost: "10.0.0.1", RemotePort: 1521}}
+
+	withHostKey := baseClient()
+	withHostKey.Apps[0].HostKeys = []string{"10.0.0.2 ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIExample"}
+
+	withBastion := baseClient()
+	withBastion.Bastions = []model.Bastion{{ID: "bastion-1", Name: "jump", Host: "jump.acme.example", User: "ops"}}
+	withBastion.Apps[0].Bastion = "bastion-1"
+
+	withBastionKey := withBastion
+	withBastionKey.Bastions = []model.Bastion{withBastion.Bastions[0]}
+	withBastionKey.Bastions[0].HostKeys = []string{"jump.acme.example ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJump"}
+
+	tests := []struct {
+		name          string
+		before, after model.Client
+	}{
+		{"add first tunnel", baseClient(), withTunnel},
+		{"remove last tunnel", withTunnel, baseClient()},
+		{"add first host key", baseClient(), withHostKey},
+		{"remove last host key", withHostKey, baseClient()},
+		{"add first bastion", baseClient(), withBastion},
+		{"remove last bastion", withBastion, baseClient()},
+		{"add first bastion host key", withBastion, withBastionKey},
+		{"remove last bastion host key", withBastionKey, withBastion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Compute(tt.before, tt.after)
+			if len(changes) == 0 {
+				t.Fatal("no changes computed")
+			}
+			c := tt.after
+			if err := Apply(&c, changes, true); err != nil {
+				t.Fatalf("undo: %v", err)
+			}
+			if diff := model.DiffPaths(c, tt.before); len(diff) > 0 {
+				t.Fatalf("undo left differences at %v", diff)
+			}
+			if err := Apply(&c, changes, false); err != nil {
+				t.Fatalf("redo: %v", err)
+			}
+			if diff := model.DiffPaths(c, tt.after); len(diff) > 0 {
+				t.Fatalf("redo left differences at %v", diff)
+			}
+		})
+	}
+}
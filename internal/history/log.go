@@ -0,0 +1,247 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+)
+
+const (
+	// FormatName identifies client-man history files
+	FormatName = "client-man-history"
+	// FormatVersion is the file version written by Save
+	FormatVersion = 1
+	// MaxEntries is the number of entries kept; daha eskileri kayıtta atılır
+	MaxEntries = 2000
+
+	suffix = ".history"
+)
+
+// file is the on-disk container. Kayıtların tamamı vault anahtarıyla tek bir
+// XChaCha20-Poly1305 bloğu olarak şifrelenir; gizli alanlar da dahil hiçbir değer açıkta kalmaz.
+type file struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	Payload string `json:"payload"`
+}
+
+// Log is the change history of one vault together with the session's undo and redo stacks.
+// Undo/redo yığınları sadece bellekte tutulur, uygulama kapanınca sıfırlanır.
+type Log struct {
+	path    string
+	entries []Entry
+	nextID  int64
+	undo    []int64
+	redo    []int64
+}
+
+// PathFor returns the history file that belongs to the vault at vaultPath
+func PathFor(vaultPath string) string {
+	return vaultPath + suffix
+}
+
+// New returns an empty log for the vault at vaultPath
+func New(vaultPath string) *Log {
+	return &Log{path: PathFor(vaultPath), nextID: 1}
+}
+
+// Load reads and decrypts the history of the vault at vaultPath. Dosya yoksa boş log döner.
+func Load(vaultPath string, key []byte) (*Log, error) {
+	l := New(vaultPath)
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return l, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return l, fmt.Errorf("history file is corrupt: %w", err)
+	}
+	if f.Format != FormatName || f.Version > FormatVersion {
+		return l, fmt.Errorf("unsupported history file version %d", f.Version)
+	}
+	plain, err := vault.OpenBlob(f.Payload, aad(f.Version), key)
+	if err != nil {
+		return l, err
+	}
+	if err := json.Unmarshal(plain, &l.entries); err != nil {
+		return l, fmt.Errorf("history file is corrupt: %w", err)
+	}
+	for _, e := range l.entries {
+		if e.ID >= l.nextID {
+			l.nextID = e.ID + 1
+		}
+	}
+	return l, nil
+}
+
+// Save encrypts the log with key and writes it atomically next to the vault
+func (l *Log) Save(key []byte) error {
+	plain, err := json.Marshal(l.entries)
+	if err != nil {
+		return err
+	}
+	payload, err := vault.SealBlob(plain, aad(FormatVersion), key)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(file{Format: FormatName, Version: FormatVersion, Payload: payload}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.WriteFile(l.path, data, store.VaultPerm)
+}
+
+func aad(version int) []byte {
+	return []byte(FormatName + "/" + strconv.Itoa(version))
+}
+
+// Record appends an entry and updates the undo and redo stacks:
+// edit ve revert yeni bir undo adımıdır ve redo yığınını temizler, undo Ref'i
+// redo yığınına, redo ise undo yığınına taşır.
+func (l *Log) Record(clientID, company, action string, ref int64, changes []Change) Entry {
+	e := Entry{
+		ID:       l.nextID,
+		Time:     time.Now(),
+		User:     CurrentUser(),
+		ClientID: clientID,
+		Company:  company,
+		Action:   action,
+		Ref:      ref,
+		Changes:  changes,
+	}
+	l.nextID++
+
+	// Firma adı değiştiyse eski kayıtlar yeni ada taşınır
+	for _, ch := range changes {
+		if ch.Path != "company" || ch.Old == nil || ch.New == nil {
+			continue
+		}
+		var from string
+		if json.Unmarshal([]byte(*ch.Old), &from) == nil {
+			l.renameCompany(from, company)
+		}
+	}
+	l.entries = append(l.entries, e)
+
+	switch action {
+	case ActionUndo:
+		l.undo = remove(l.undo, ref)
+		l.redo = append(l.redo, ref)
+	case ActionRedo:
+		l.redo = remove(l.redo, ref)
+		l.undo = append(l.undo, ref)
+	default:
+		l.undo = append(l.undo, e.ID)
+		l.redo = nil
+	}
+
+	l.trim()
+	return e
+}
+
+// NextUndo returns the entry Ctrl+Z would undo. nil log'da false döner.
+func (l *Log) NextUndo() (Entry, bool) {
+	if l == nil || len(l.undo) == 0 {
+		return Entry{}, false
+	}
+	return l.Get(l.undo[len(l.undo)-1])
+}
+
+// NextRedo returns the entry Ctrl+Y would redo
+func (l *Log) NextRedo() (Entry, bool) {
+	if l == nil || len(l.redo) == 0 {
+		return Entry{}, false
+	}
+	return l.Get(l.redo[len(l.redo)-1])
+}
+
+// DropUndo forgets the next undo step, örn. alan artık uygulanamıyorsa
+func (l *Log) DropUndo() {
+	if len(l.undo) > 0 {
+		l.undo = l.undo[:len(l.undo)-1]
+	}
+}
+
+// DropRedo forgets the next redo step
+func (l *Log) DropRedo() {
+	if len(l.redo) > 0 {
+		l.redo = l.redo[:len(l.redo)-1]
+	}
+}
+
+// Get returns the entry with id
+func (l *Log) Get(id int64) (Entry, bool) {
+	for _, e := range l.entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// ForClient returns the entries of a client, newest first. ID'siz eski kayıtlar firma adıyla eşleşir.
+func (l *Log) ForClient(id, company string) []Entry {
+	var out []Entry
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if l.entries[i].Belongs(id, company) {
+			out = append(out, l.entries[i])
+		}
+	}
+	return out
+}
+
+// Clear drops all entries from memory (kilitlenirken gizli değerler bellekte kalmasın)
+func (l *Log) Clear() {
+	for i := range l.entries {
+		l.entries[i] = Entry{}
+	}
+	l.entries = nil
+	l.undo = nil
+	l.redo = nil
+}
+
+func (l *Log) renameCompany(from, to string) {
+	for i := range l.entries {
+		if l.entries[i].Company == from {
+			l.entries[i].Company = to
+		}
+	}
+}
+
+// trim keeps the newest MaxEntries entries and forgets stack steps that point to dropped ones
+func (l *Log) trim() {
+	if len(l.entries) <= MaxEntries {
+		return
+	}
+	l.entries = append([]Entry(nil), l.entries[len(l.entries)-MaxEntries:]...)
+	oldest := l.entries[0].ID
+	keep := func(ids []int64) []int64 {
+		out := ids[:0]
+		for _, id := range ids {
+			if id >= oldest {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+	l.undo = keep(l.undo)
+	l.redo = keep(l.redo)
+}
+
+func remove(ids []int64, id int64) []int64 {
+	for i := len(ids) - 1; i >= 0; i-- {
+		if ids[i] == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,343 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"clientinfo/internal/sshclient"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	nativeDialog "github.com/sqweek/dialog"
+	"golang.org/x/crypto/ssh"
+)
+
+// hostKeyCheck returns the host key callback and key algorithms of a session to addr.
+// bastionID boş değilse anahtarlar o bastion'da, değilse ortamda tutulur. Kayıtlı anahtar
+// yoksa ilk bağlantıda sunulan anahtar kaydedilir. Farklı bir anahtar bağlantıyı kullanıcı
+// açıkça onaylamadıkça durdurur.
+func (w *terminalWindow) hostKeyCheck(sess *terminalSession, addr string, lines []string, bastionID string) (ssh.HostKeyCallback, []string) {
+	trusted, fromUserFile := trustedHostKeys(addr, lines)
+
+	callback := func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+		switch sshclient.CheckHostKey(trusted, key) {
+		case sshclient.HostKeyTrusted:
+			if fromUserFile {
+				w.state.recordHostKey(w.clientID, w.appID, bastionID, addr, key, false)
+				fmt.Fprintf(sess.view, TerminalMsgHostKeyImported+"\r\n", key.Type(), sshclient.Fingerprint(key))
+			}
+			return nil
+		case sshclient.HostKeyNew:
+			w.state.recordHostKey(w.clientID, w.appID, bastionID, addr, key, false)
+			fmt.Fprintf(sess.view, TerminalMsgHostKeyRecorded+"\r\n", key.Type(), sshclient.Fingerprint(key))
+			return nil
+		}
+
+		// Kırmızı, kalın uyarı terminalde de kalsın
+		fmt.Fprintf(sess.view, "\r\n\x1b[1;37;41m "+TerminalMsgHostKeyChanged+" \x1b[0m\r\n", hostname)
+		for _, t := range trusted {
+			fmt.Fprintf(sess.view, "\x1b[1;31m  "+HostKeyChangedStored+"\x1b[0m\r\n", t.Type(), sshclient.Fingerprint(t))
+		}
+		fmt.Fprintf(sess.view, "\x1b[1;31m  "+HostKeyChangedOffered+"\x1b[0m\r\n", key.Type(), sshclient.Fingerprint(key))
+
+		if w.confirmChangedHostKey(sess, hostname, key, trusted) {
+			w.state.recordHostKey(w.clientID, w.appID, bastionID, addr, key, true)
+			return nil
+		}
+		return sshclient.ErrHostKeyChanged
+	}
+	return callback, sshclient.HostKeyAlgorithms(trusted)
+}
+
+// tunnelHostKeyCheck is the host key check of tunnels, which have no terminal to ask in.
+// Yeni anahtar terminaldeki gibi kaydedilir; değişmiş anahtar sorulmadan reddedilir.
+func (s *AppState) tunnelHostKeyCheck(clientID, appID string) hostKeyCheckFunc {
+	return func(addr string, lines []string, bastionID string) (ssh.HostKeyCallback, []string) {
+		trusted, fromUserFile := trustedHostKeys(addr, lines)
+		callback := func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+			switch sshclient.CheckHostKey(trusted, key) {
+			case sshclient.HostKeyTrusted:
+				if fromUserFile {
+					s.recordHostKey(clientID, appID, bastionID, addr, key, false)
+				}
+				return nil
+			case sshclient.HostKeyNew:
+				s.recordHostKey(clientID, appID, bastionID, addr, key, false)
+				return nil
+			}
+			return fmt.Errorf("%s: %w. "+TunnelMsgHostKeyChanged, hostname, sshclient.ErrHostKeyChanged)
+		}
+		return callback, sshclient.HostKeyAlgorithms(trusted)
+	}
+}
+
+// confirmChangedHostKey warns that the host key changed and asks whether to replace the stored key.
+// Bağlantı goroutine'inden çağrılır ve cevabı bekler; varsayılan cevap bağlanmamaktır.
+func (w *terminalWindow) confirmChangedHostKey(sess *terminalSession, hostname string, key ssh.PublicKey, trusted []ssh.PublicKey) bool {
+	answer := make(chan bool, 1)
+	fyne.Do(func() {
+		var stored []string
+		for _, t := range trusted {
+			stored = append(stored, fmt.Sprintf(HostKeyChangedStored, t.Type(), sshclient.Fingerprint(t)))
+		}
+		title := widget.NewLabelWithStyle(fmt.Sprintf(TerminalMsgHostKeyChanged, hostname), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		title.Importance = widget.DangerImportance
+		keys := widget.NewLabelWithStyle(strings.Join(stored, "\n")+"\n"+fmt.Sprintf(HostKeyChangedOffered, key.Type(), sshclient.Fingerprint(key)),
+			fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+		info := widget.NewLabel(HostKeyChangedInfo)
+		info.Wrapping = fyne.TextWrapWord
+		content := container.NewBorder(container.NewHBox(widget.NewIcon(theme.WarningIcon()), title), nil, nil, nil,
+			container.NewVBox(keys, info))
+
+		d := dialog.NewCustomConfirm(HostKeyChangedTitle, HostKeyChangedReplace, HostKeyChangedDisconnect, content,
+			func(ok bool) { answer <- ok }, w.window)
+		d.Resize(fyne.NewSize(560, 0))
+		d.Show()
+	})
+	select {
+	case ok := <-answer:
+		return ok
+	case <-sess.done:
+		return false
+	}
+}
+
+// trustedHostKeys returns the keys trusted for addr in lines.
+// Kayıtlı anahtar yoksa ~/.ssh/known_hosts'a bakılır; fromUserFile o zaman true olur.
+func trustedHostKeys(addr string, lines []string) (trusted []ssh.PublicKey, fromUserFile bool) {
+	trusted = sshclient.KeysFor(sshclient.ParseHostKeyLines(lines), addr)
+	if len(trusted) > 0 {
+		return trusted, false
+	}
+	if entries, err := sshclient.ReadKnownHosts(sshclient.UserKnownHostsFile()); err == nil {
+		trusted = sshclient.KeysFor(entries, addr)
+	}
+	return trusted, len(trusted) > 0
+}
+
+// externalKnownHosts writes the trusted keys of every hop to app's server to a temporary known_hosts file
+// for an external ssh. Anahtarı bilinmeyen bir sunucu varsa hata döner; ssh ona StrictHostKeyChecking=yes
+// ile zaten bağlanmazdı ve ilk anahtarı kaydeden uygulama içi terminaldir.
+func externalKnownHosts(c Client, app AppInfo) (*sshclient.PinnedHostKeys, error) {
+	route, err := appRoute(c, app)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	trust := func(name, addr string, stored []string) error {
+		trusted, _ := trustedHostKeys(addr, stored)
+		if len(trusted) == 0 {
+			return fmt.Errorf(DialogMsgSSHNoHostKey, name)
+		}
+		for _, key := range trusted {
+			lines = append(lines, sshclient.HostKeyLine(addr, key))
+		}
+		return nil
+	}
+	for _, hop := range route.hops {
+		name := hop.name
+		if name == "" {
+			name = hop.target.Addr()
+		}
+		if err := trust(name, hop.target.Addr(), hop.hostKeys); err != nil {
+			return nil, err
+		}
+	}
+	if err := trust(route.target.Addr(), route.target.Addr(), route.hostKeys); err != nil {
+		return nil, err
+	}
+	return sshclient.TempPinnedHostKeys(lines)
+}
+
+// recordHostKey stores key as trusted for addr in an environment, or in its client's bastion bastionID.
+// Bağlantı goroutine'lerinden çağrılabilir.
+func (s *AppState) recordHostKey(clientID, appID, bastionID, addr string, key ssh.PublicKey, replace bool) {
+	fyne.Do(func() {
+		if s.vaultKey == nil {
+			return
+		}
+		err := s.applyClientEdit(clientID, func(c *Client) {
+			if bastionID != "" {
+				if b := c.BastionByID(bastionID); b != nil {
+					b.HostKeys = withHostKey(b.HostKeys, addr, key, replace)
+				}
+			} else if a := c.AppByID(appID); a != nil {
+				a.HostKeys = withHostKey(a.HostKeys, addr, key, replace)
+			}
+		})
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		s.filterClients(s.searchEntry.Text)
+	})
+}
+
+// withHostKey returns lines with key trusted for addr; replace ise adresin diğer anahtarları çıkarılır
+func withHostKey(lines []string, addr string, key ssh.PublicKey, replace bool) []string {
+	var out []string
+	for _, line := range lines {
+		entries := sshclient.ParseHostKeyLines([]string{line})
+		if len(entries) == 1 && entries[0].Matches(addr) {
+			if bytes.Equal(entries[0].Key.Marshal(), key.Marshal()) {
+				return lines
+			}
+			if replace {
+				continue
+			}
+		}
+		out = append(out, line)
+	}
+	return append(out, sshclient.HostKeyLine(addr, key))
+}
+
+// hostKeyFormItem shows trusted host keys, örn. bir ortamın App Server kartında; forget anahtarları siler
+func (s *AppState) hostKeyFormItem(clientID string, lines []string, forget func(*Client)) *widget.FormItem {
+	entries := sshclient.ParseHostKeyLines(lines)
+	if len(entries) == 0 {
+		return widget.NewFormItem(FormLabelHostKey, widget.NewLabel(HostKeyNone))
+	}
+
+	var shown []string
+	for _, e := range entries {
+		shown = append(shown, fmt.Sprintf("%s %s (%s)", e.Key.Type(), sshclient.Fingerprint(e.Key), strings.Join(e.Hosts, ",")))
+	}
+	label := widget.NewLabelWithStyle(strings.Join(shown, "\n"), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	label.Truncation = fyne.TextTruncateEllipsis
+
+	forgetBtn := NewIconButtonSimple(theme.DeleteIcon(), "", fyne.NewSize(18, 18), HostKeyForgetTip, func() {
+		dialog.ShowConfirm(HostKeyForgetTitle, HostKeyForgetConfirm, func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := s.applyClientEdit(clientID, forget); err != nil {
+				dialog.ShowError(err, s.window)
+				return
+			}
+			s.filterClients(s.searchEntry.Text)
+		}, s.window)
+	})
+	return widget.NewFormItem(FormLabelHostKey, container.NewBorder(nil, nil, nil, forgetBtn, label))
+}
+
+// importKnownHosts copies the keys of every environment's app server and every bastion from a known_hosts file.
+// Ortamda aynı türde farklı bir anahtar kayıtlıysa dokunulmaz ve sayısı bildirilir.
+func (s *AppState) importKnownHosts() {
+	file := sshclient.UserKnownHostsFile()
+	filename, err := nativeDialog.File().
+		Title(HostKeyImportTitle).
+		SetStartDir(filepath.Dir(file)).
+		SetStartFile(filepath.Base(file)).
+		Load()
+	if err != nil {
+		// Kullanıcı iptal etti
+		return
+	}
+	entries, err := sshclient.ReadKnownHosts(filename)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf(DialogMsgFileReadError+": %v", err), s.window)
+		return
+	}
+
+	imported, environments, conflicts := 0, 0, 0
+	for i := range s.clients {
+		updated := map[string][]string{} // App ID -> yeni HostKeys
+		for _, app := range s.clients[i].Apps {
+			if strings.TrimSpace(app.AppServerIP) == "" {
+				continue
+			}
+			target, err := appTarget(app)
+			if err != nil {
+				continue
+			}
+			addr := target.Addr()
+			lines, added, conflict := importHostKeys(app.HostKeys, addr, sshclient.KeysFor(entries, addr))
+			if conflict {
+				conflicts++
+			}
+			if added > 0 {
+				updated[app.ID] = lines
+				imported += added
+				environments++
+			}
+		}
+		updatedBastions := map[string][]string{} // Bastion ID -> yeni HostKeys
+		for _, b := range s.clients[i].Bastions {
+			if strings.TrimSpace(b.Host) == "" {
+				continue
+			}
+			addr := bastionTarget(b).Addr()
+			lines, added, conflict := importHostKeys(b.HostKeys, addr, sshclient.KeysFor(entries, addr))
+			if conflict {
+				conflicts++
+			}
+			if added > 0 {
+				updatedBastions[b.ID] = lines
+				imported += added
+				environments++
+			}
+		}
+		if len(updated) == 0 && len(updatedBastions) == 0 {
+			continue
+		}
+		err := s.applyClientEdit(s.clients[i].ID, func(c *Client) {
+			for appID, lines := range updated {
+				if a := c.AppByID(appID); a != nil {
+					a.HostKeys = lines
+				}
+			}
+			for bastionID, lines := range updatedBastions {
+				if b := c.BastionByID(bastionID); b != nil {
+					b.HostKeys = lines
+				}
+			}
+		})
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+	}
+
+	s.filterClients(s.searchEntry.Text)
+	msg := fmt.Sprintf(DialogMsgHostKeysImported, imported, environments)
+	if conflicts > 0 {
+		msg += "\n" + fmt.Sprintf(DialogMsgHostKeysConflict, conflicts)
+	}
+	dialog.ShowInformation(HostKeyImportTitle, msg, s.window)
+}
+
+// importHostKeys adds the keys of addr that are missing from lines.
+// Aynı türde farklı anahtar kayıtlıysa o anahtar eklenmez ve conflict true döner.
+func importHostKeys(lines []string, addr string, keys []ssh.PublicKey) (out []string, added int, conflict bool) {
+	existing := sshclient.KeysFor(sshclient.ParseHostKeyLines(lines), addr)
+	out = lines
+	for _, key := range keys {
+		switch sshclient.CheckHostKey(existing, key) {
+		case sshclient.HostKeyTrusted:
+			continue
+		case sshclient.HostKeyChanged:
+			if hasKeyType(existing, key.Type()) {
+				conflict = true
+				continue
+			}
+		}
+		out = append(out[:len(out):len(out)], sshclient.HostKeyLine(addr, key))
+		existing = append(existing, key)
+		added++
+	}
+	return out, added, conflict
+}
+
+func hasKeyType(keys []ssh.PublicKey, keyType string) bool {
+	for _, k := range keys {
+		if k.Type() == keyType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"clientinfo/internal/history"
+	"clientinfo/internal/merge"
+	"clientinfo/internal/model"
+	"clientinfo/internal/vault"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showImportMerge merges an imported copy of the client with localID field by field.
+// Önce çakışan alanlar için seçim, ardından kaydedilecek değişikliklerin önizlemesi
+// gösterilir; hiçbir şey önizlemeden önce kaydedilmez. done dialog kapanınca çağrılır.
+func (s *AppState) showImportMerge(localID string, incoming Client, done func()) {
+	index := model.IndexByID(s.clients, localID)
+	if index < 0 {
+		dialog.ShowError(errors.New(DialogMsgClientNotFound), s.window)
+		done()
+		return
+	}
+	local := vault.CloneClients(s.clients[index : index+1])[0]
+	result := merge.Import(local, incoming)
+
+	var d dialog.Dialog
+	closeWith := func() {
+		d.Hide()
+		done()
+	}
+
+	// Çakışmalar sayfası
+	info := widget.NewLabel(ImportMergeInfo)
+	info.Wrapping = fyne.TextWrapWord
+	rows := container.NewVBox()
+	pickers := make([]func(), len(result.Conflicts))
+	for i := range result.Conflicts {
+		rows.Add(s.importConflictRow(local, &result.Conflicts[i], &pickers[i]))
+		rows.Add(widget.NewSeparator())
+	}
+	reviewPage := container.NewBorder(info, nil, nil, nil, container.NewVScroll(rows))
+
+	// Önizleme sayfası
+	previewInfo := widget.NewLabel(fmt.Sprintf(ImportMergePreviewInfo, local.Company))
+	previewInfo.Wrapping = fyne.TextWrapWord
+	previewRows := container.NewVBox()
+	previewPage := container.NewBorder(previewInfo, nil, nil, nil, container.NewVScroll(previewRows))
+
+	var merged Client
+	cancelBtn := widget.NewButton("Cancel", userActivity.Tap(closeWith))
+	backBtn := widget.NewButton(ImportMergeBack, nil)
+	previewBtn := widget.NewButton(ImportMergePreview, nil)
+	saveBtn := widget.NewButton(ImportMergeSave, nil)
+	saveBtn.Importance = widget.HighImportance
+	previewBtn.Importance = widget.HighImportance
+
+	showReview := func() {
+		previewPage.Hide()
+		backBtn.Hide()
+		saveBtn.Hide()
+		reviewPage.Show()
+		previewBtn.Show()
+	}
+	showPreview := func() {
+		for _, pick := range pickers {
+			pick()
+		}
+		clients, err := result.Clients()
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		merged = clients[0]
+		merged.ID = local.ID
+		s.fillImportPreview(previewRows, local, merged)
+
+		reviewPage.Hide()
+		previewBtn.Hide()
+		previewPage.Show()
+		saveBtn.Show()
+		backBtn.Show()
+		if len(result.Conflicts) == 0 {
+			backBtn.Hide()
+		}
+	}
+	backBtn.OnTapped = userActivity.Tap(showReview)
+	previewBtn.OnTapped = userActivity.Tap(showPreview)
+	saveBtn.OnTapped = userActivity.Tap(func() {
+		d.Hide()
+		s.saveImportMerge(local.ID, merged)
+		done()
+	})
+
+	buttons := container.NewHBox(cancelBtn, backBtn, previewBtn, saveBtn)
+	content := container.NewBorder(nil, container.NewCenter(buttons), nil, nil, container.NewStack(reviewPage, previewPage))
+	d = dialog.NewCustomWithoutButtons(fmt.Sprintf(ImportMergeTitle, local.Company), content, s.window)
+	d.Resize(fyne.NewSize(760, 520))
+
+	if len(result.Conflicts) == 0 {
+		showPreview()
+	} else {
+		showReview()
+	}
+	d.Show()
+}
+
+// importConflictRow builds the keep / take / edit choice for one conflict.
+// *pick seçimi conflict'e yazar; önizlemeye geçerken çağrılır.
+func (s *AppState) importConflictRow(local Client, c *merge.Conflict, pick *func()) fyne.CanvasObject {
+	heading := widget.NewLabel(importFieldTitle(local, c))
+	heading.TextStyle = fyne.TextStyle{Bold: true}
+
+	// Gelen ortam: eklenir veya atlanır
+	if c.Local == nil {
+		add := fmt.Sprintf(ImportMergeAddEnv, importEnvName(c.Remote))
+		radio := widget.NewRadioGroup([]string{add, ImportMergeSkipEnv}, nil)
+		radio.Required = true
+		radio.SetSelected(add)
+		*pick = func() {
+			c.Choice = merge.Remote
+			if radio.Selected != add {
+				c.Choice = merge.Local
+			}
+		}
+		return container.NewVBox(heading, radio)
+	}
+
+	mine := fmt.Sprintf(ConflictKeepMine, conflictValue(*c, c.Local))
+	theirs := fmt.Sprintf(ConflictTakeTheirs, conflictValue(*c, c.Remote))
+	radio := widget.NewRadioGroup([]string{mine, theirs, ImportMergeEdit}, nil)
+	radio.Required = true
+
+	list := isJSONList(c.Local) || isJSONList(c.Remote)
+	var editor *widget.Entry
+	switch {
+	case list:
+		editor = widget.NewMultiLineEntry()
+	case c.Secret():
+		editor = widget.NewPasswordEntry()
+	default:
+		editor = widget.NewEntry()
+	}
+	editor.SetText(importEditText(c, list))
+	editor.Hide()
+	radio.OnChanged = func(selected string) {
+		if selected == ImportMergeEdit {
+			editor.Show()
+		} else {
+			editor.Hide()
+		}
+	}
+	radio.SetSelected(mine)
+
+	*pick = func() {
+		switch radio.Selected {
+		case theirs:
+			c.Choice = merge.Remote
+		case ImportMergeEdit:
+			c.Choice = merge.Edited
+			c.Edit = encodeImportEdit(editor.Text, list)
+		default:
+			c.Choice = merge.Local
+		}
+	}
+	return container.NewVBox(heading, radio, editor)
+}
+
+// fillImportPreview lists the changes that saving the merge makes to local
+func (s *AppState) fillImportPreview(box *fyne.Container, local, merged Client) {
+	box.Objects = nil
+	changes := history.Compute(local, merged)
+	if len(changes) == 0 {
+		box.Add(widget.NewLabel(fmt.Sprintf(ImportMergeNoChanges, local.Company)))
+	}
+	for _, ch := range changes {
+		title := importFieldTitle(merged, &merge.Conflict{Path: ch.Path})
+		text := widget.NewLabel(fmt.Sprintf("%s: %s → %s", title, formatHistoryValue(ch, ch.Old), formatHistoryValue(ch, ch.New)))
+		text.Wrapping = fyne.TextWrapWord
+		box.Add(text)
+	}
+	box.Refresh()
+}
+
+// saveImportMerge replaces the client with id by merged, records the changes and saves
+func (s *AppState) saveImportMerge(id string, merged Client) {
+	index := model.IndexByID(s.clients, id)
+	if index < 0 {
+		dialog.ShowError(errors.New(DialogMsgClientNotFound), s.window)
+		return
+	}
+	before := vault.CloneClients(s.clients[index : index+1])[0]
+	s.clients[index] = merged
+	// Eklenen ortamlara bu vault'ta kullanılmayan ID verilir
+	model.EnsureIDs(s.clients)
+
+	// Bellek ve geçmiş ancak kayıt başarılı olursa değişir
+	err := s.commitClient(index, history.ActionEdit, 0, before)
+	s.filterClients(s.searchEntry.Text)
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	dialog.ShowInformation(DialogTitleSuccess, DialogMsgImportMerged, s.window)
+}
+
+// importFieldTitle names a field path, "apps[1]" yerine ortamın tip ve adıyla
+func importFieldTitle(c Client, conflict *merge.Conflict) string {
+	path := conflict.Path
+	if !strings.HasPrefix(path, "apps[") {
+		return path
+	}
+	end := strings.IndexByte(path, ']')
+	i, err := strconv.Atoi(path[len("apps["):end])
+	if err != nil || i < 0 || i >= len(c.Apps) {
+		if conflict.Local == nil && conflict.Remote != nil {
+			return importEnvName(conflict.Remote)
+		}
+		return path
+	}
+	name := fmt.Sprintf("%s - %s", fallback(c.Apps[i].Type), fallback(c.Apps[i].Name))
+	if rest := strings.TrimPrefix(path[end+1:], "."); rest != "" {
+		return name + " — " + rest
+	}
+	return name
+}
+
+// importEnvName returns "TYPE - Name" of a JSON encoded environment
+func importEnvName(raw *string) string {
+	var app AppInfo
+	if raw == nil || json.Unmarshal([]byte(*raw), &app) != nil {
+		return "—"
+	}
+	return fmt.Sprintf("%s - %s", fallback(app.Type), fallback(app.Name))
+}
+
+// isJSONList reports whether raw is a JSON encoded list
+func isJSONList(raw *string) bool {
+	return raw != nil && strings.HasPrefix(strings.TrimSpace(*raw), "[")
+}
+
+// importEditText is the starting text of the edit field: listelerde iki tarafın
+// satırları birleştirilir, diğer alanlarda yerel değer
+func importEditText(c *merge.Conflict, list bool) string {
+	if list {
+		var lines []string
+		seen := map[string]bool{}
+		for _, raw := range []*string{c.Local, c.Remote} {
+			var values []string
+			if raw != nil {
+				json.Unmarshal([]byte(*raw), &values)
+			}
+			for _, v := range values {
+				if !seen[v] {
+					seen[v] = true
+					lines = append(lines, v)
+				}
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	var text string
+	if c.Local != nil {
+		json.Unmarshal([]byte(*c.Local), &text)
+	}
+	return text
+}
+
+// encodeImportEdit turns the edited text back into a JSON value; listelerde her satır bir eleman
+func encodeImportEdit(text string, list bool) string {
+	var raw []byte
+	if list {
+		values := []string{}
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				values = append(values, line)
+			}
+		}
+		raw, _ = json.Marshal(values)
+	} else {
+		raw, _ = json.Marshal(text)
+	}
+	return string(raw)
+}
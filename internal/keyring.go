@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"clientinfo/internal/keystore"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+)
+
+// keyringWrapSecret returns this installation's random secret that wraps remembered keys.
+// Vault'tan ayrı, uygulama ayarlarında tutulur; ilk kullanımda oluşturulur.
+func (s *AppState) keyringWrapSecret() ([]byte, error) {
+	prefs := s.myApp.Preferences()
+	if secret, err := base64.StdEncoding.DecodeString(prefs.String(PrefKeyringSecret)); err == nil && len(secret) == keystore.SecretLength {
+		return secret, nil
+	}
+	secret, err := keystore.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	prefs.SetString(PrefKeyringSecret, base64.StdEncoding.EncodeToString(secret))
+	return secret, nil
+}
+
+// rememberVaultKey stores the wrapped key of the open vault in the keyring
+func (s *AppState) rememberVaultKey() error {
+	if s.keys == nil || s.vaultKey == nil {
+		return errors.New("keyring is not available")
+	}
+	secret, err := s.keyringWrapSecret()
+	if err != nil {
+		return err
+	}
+	return keystore.Remember(s.keys, keystore.Account(s.currentFile), s.vaultKey, s.vaultKDF, secret)
+}
+
+// forgetVaultKey removes the stored key for path from the keyring
+func (s *AppState) forgetVaultKey(path string) error {
+	if s.keys == nil {
+		return nil
+	}
+	return keystore.Forget(s.keys, keystore.Account(path))
+}
+
+// hasRememberedKey reports whether a key for path is stored in the keyring
+func (s *AppState) hasRememberedKey(path string) bool {
+	return s.keys != nil && keystore.Has(s.keys, keystore.Account(path))
+}
+
+// unlockFromKeyring tries to open the vault at path with a remembered key.
+// Keyring yoksa, kayıt bulunamazsa veya anahtar artık geçerli değilse false döner
+// ve şifre ekranına düşülür.
+func (s *AppState) unlockFromKeyring(path string) bool {
+	if !s.hasRememberedKey(path) {
+		return false
+	}
+	params, err := store.ReadKDF(path)
+	if err != nil {
+		return false
+	}
+	secret, err := s.keyringWrapSecret()
+	if err != nil {
+		return false
+	}
+	key, err := keystore.Recall(s.keys, keystore.Account(path), params, secret)
+	if err != nil {
+		return false
+	}
+
+	if err := s.loadClientsWithKey(path, key); err != nil {
+		if errors.Is(err, vault.ErrWrongPassword) {
+			keystore.Forget(s.keys, keystore.Account(path))
+		}
+		return false
+	}
+	return true
+}
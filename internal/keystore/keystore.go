@@ -0,0 +1,150 @@
+// Package keystore remembers vault keys in the OS keyring (Secret Service,
+// Credential Manager, Keychain) for "remember on this machine".
+//
+// Anahtar keyring'e düz yazılmaz; bu kuruluma özel rastgele bir sır ve vault'un
+// KDF salt'ıyla türetilen anahtarla şifrelenir. Sır vault'ta değil uygulama
+// ayarlarında durur, yani keyring kaydı ve vault dosyası tek başına anahtarı
+// açmaya yetmez. Salt değişirse (anahtar rotasyonu) kayıt kendiliğinden geçersiz olur.
+package keystore
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"clientinfo/internal/vault"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// Service is the keyring service name under which vault keys are stored
+	Service = "client-man-vault"
+	// SecretLength is the size of the per-install wrap secret in bytes
+	SecretLength = 32
+	// wrapContext separates the wrap key from any other use of the secret and salt
+	wrapContext = "client-man-keyring-wrap-v2"
+)
+
+// ErrNotFound is returned when no usable key is remembered for a vault
+var ErrNotFound = errors.New("no key remembered for this vault")
+
+// Store is a keyring; testlerde bellek içi bir sahtesiyle değiştirilebilir
+type Store interface {
+	Get(account string) (string, error)
+	Set(account, secret string) error
+	Delete(account string) error
+}
+
+// OS stores secrets in the operating system keyring via go-keyring
+type OS struct {
+	Service string
+}
+
+// NewOS returns the OS keyring under Service
+func NewOS() *OS {
+	return &OS{Service: Service}
+}
+
+func (k *OS) Get(account string) (string, error) {
+	v, err := keyring.Get(k.Service, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	return v, err
+}
+
+func (k *OS) Set(account, secret string) error {
+	return keyring.Set(k.Service, account, secret)
+}
+
+func (k *OS) Delete(account string) error {
+	err := keyring.Delete(k.Service, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Account returns the keyring account name of a vault file (mutlak yol)
+func Account(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// NewSecret returns a new random per-install wrap secret
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, SecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Remember stores key of the vault with params under account, secret ile sarılmış olarak
+func Remember(st Store, account string, key []byte, params vault.KDFParams, secret []byte) error {
+	wrap, err := wrapKey(params, secret)
+	if err != nil {
+		return err
+	}
+	wrapped, err := vault.EncryptString(base64.StdEncoding.EncodeToString(key), wrap)
+	if err != nil {
+		return err
+	}
+	return st.Set(account, wrapped)
+}
+
+// Recall returns the key remembered under account.
+// Kayıt bu sırla veya bu salt'la açılamıyorsa (başka kurulum, anahtar rotasyonu)
+// silinir ve ErrNotFound döner.
+func Recall(st Store, account string, params vault.KDFParams, secret []byte) ([]byte, error) {
+	wrapped, err := st.Get(account)
+	if err != nil {
+		return nil, err
+	}
+	if wrapped == "" {
+		return nil, ErrNotFound
+	}
+	wrap, err := wrapKey(params, secret)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := vault.DecryptString(wrapped, wrap)
+	if err != nil {
+		st.Delete(account)
+		return nil, ErrNotFound
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != vault.KeyLength {
+		st.Delete(account)
+		return nil, ErrNotFound
+	}
+	return key, nil
+}
+
+// Has reports whether a key is stored under account
+func Has(st Store, account string) bool {
+	v, err := st.Get(account)
+	return err == nil && v != ""
+}
+
+// Forget removes the key stored under account; kayıt yoksa hata değildir
+func Forget(st Store, account string) error {
+	return st.Delete(account)
+}
+
+// wrapKey derives the key that wraps a vault key from the install secret and the vault's KDF salt
+func wrapKey(params vault.KDFParams, secret []byte) ([]byte, error) {
+	if len(secret) < SecretLength {
+		return nil, fmt.Errorf("keyring wrap secret must be %d bytes", SecretLength)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(wrapContext))
+	mac.Write([]byte(params.Salt))
+	return mac.Sum(nil), nil
+}
@@ -0,0 +1,120 @@
+package keystore
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"clientinfo/internal/vault"
+)
+
+// memStore is an in-memory Store
+type memStore map[string]string
+
+func (m memStore) Get(account string) (string, error) {
+	v, ok := m[account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (m memStore) Set(account, secret string) error {
+	m[account] = secret
+	return nil
+}
+
+func (m memStore) Delete(account string) error {
+	delete(m, account)
+	return nil
+}
+
+func testKey() []byte {
+	return bytes.Repeat([]byte{7}, vault.KeyLength)
+}
+
+func mustSecret(t *testing.T) []byte {
+	t.Helper()
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+func TestRememberRecall(t *testing.T) {
+	st := memStore{}
+	params := vault.KDFParams{Salt: "c2FsdA"}
+	secret := mustSecret(t)
+
+	if err := Remember(st, "vault.json", testKey(), params, secret); err != nil {
+		t.Fatal(err)
+	}
+	if !Has(st, "vault.json") {
+		t.Fatal("key not stored")
+	}
+	if strings.Contains(st["vault.json"], "BwcHBwcH") {
+		t.Fatal("key stored in clear")
+	}
+	key, err := Recall(st, "vault.json", params, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key, testKey()) {
+		t.Fatalf("recalled %x", key)
+	}
+}
+
+func TestRecallNeedsInstallSecret(t *testing.T) {
+	st := memStore{}
+	params := vault.KDFParams{Salt: "c2FsdA"}
+	if err := Remember(st, "vault.json", testKey(), params, mustSecret(t)); err != nil {
+		t.Fatal(err)
+	}
+	// Keyring kaydı ve vault'taki salt başka bir kurulumun sırrıyla açılamaz
+	if _, err := Recall(st, "vault.json", params, mustSecret(t)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if Has(st, "vault.json") {
+		t.Fatal("unusable entry not deleted")
+	}
+}
+
+func TestRecallAfterSaltChange(t *testing.T) {
+	st := memStore{}
+	secret := mustSecret(t)
+	if err := Remember(st, "vault.json", testKey(), vault.KDFParams{Salt: "b2xk"}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Recall(st, "vault.json", vault.KDFParams{Salt: "bmV3"}, secret); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if Has(st, "vault.json") {
+		t.Fatal("stale entry not deleted")
+	}
+}
+
+func TestForget(t *testing.T) {
+	st := memStore{}
+	params := vault.KDFParams{Salt: "c2FsdA"}
+	secret := mustSecret(t)
+	if err := Remember(st, "vault.json", testKey(), params, secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := Forget(st, "vault.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Forget(st, "vault.json"); err != nil {
+		t.Fatalf("forgetting twice: %v", err)
+	}
+	if _, err := Recall(st, "vault.json", params, secret); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestShortSecretRejected(t *testing.T) {
+	if err := Remember(memStore{}, "vault.json", testKey(), vault.KDFParams{Salt: "c2FsdA"}, []byte("short")); err == nil {
+		t.Fatal("short secret accepted")
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package launch
+
+import "os/exec"
+
+// newConsole is only needed on Windows; diğer sistemlerde terminal ayrı bir programdır
+func newConsole(*exec.Cmd) {}
@@ -0,0 +1,14 @@
+package launch
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// createNewConsole is CREATE_NEW_CONSOLE of CreateProcess
+const createNewConsole = 0x00000010
+
+// newConsole makes cmd open its own console window
+func newConsole(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: createNewConsole}
+}
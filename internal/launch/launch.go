@@ -0,0 +1,74 @@
+// Package launch starts commands in a new terminal window of the platform.
+//
+// Komut hiçbir zaman cmd /c veya bash -c'ye metin olarak verilmez; yalnızca macOS'ta
+// Terminal.app bir kabuk satırı beklediği için her argüman ayrı ayrı tırnaklanır.
+// Ek ortam değişkenleri terminal sürecinin ortamına konur; komut satırına yalnızca
+// Terminal.app'e mecburen yazılır, bu yüzden içlerinde sır bulunmamalıdır.
+package launch
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// errNoCommand is returned for an empty argv
+var errNoCommand = errors.New("no command to run")
+
+// InTerminal runs argv with the extra "KEY=value" env in a new terminal window.
+// Birden çok terminal denenir; ilk başlayan kullanılır.
+func InTerminal(argv, env []string) error {
+	if len(argv) == 0 {
+		return errNoCommand
+	}
+	var err error
+	for _, cmd := range commands(runtime.GOOS, argv, env) {
+		if err = cmd.Start(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// commands returns the terminal commands that run argv on goos, tercih sırasıyla
+func commands(goos string, argv, env []string) []*exec.Cmd {
+	var cmds []*exec.Cmd
+	switch goos {
+	case "windows":
+		// Yeni konsolda doğrudan ssh; oturum bitince pencere kapanır
+		cmd := exec.Command(argv[0], argv[1:]...)
+		newConsole(cmd)
+		cmds = append(cmds, cmd)
+	case "darwin":
+		// Terminal.app komutu kendi ortamında çalıştırır; değişkenler env ile verilir.
+		// Komut satırı AppleScript'e metin olarak değil argüman olarak verilir.
+		if len(env) > 0 {
+			argv = append(append([]string{"env"}, env...), argv...)
+		}
+		cmds = append(cmds, exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", `tell application "Terminal" to do script ((item 1 of argv) & "; exit")`,
+			"-e", "end run",
+			shellJoin(argv)))
+	default:
+		// Linux: xterm, yoksa gnome-terminal; ikisi de komutu kendi ortamıyla çalıştırır
+		cmds = append(cmds,
+			exec.Command("xterm", append([]string{"-hold", "-e"}, argv...)...),
+			exec.Command("gnome-terminal", append([]string{"--"}, argv...)...))
+	}
+	for _, cmd := range cmds {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmds
+}
+
+// shellJoin quotes each argument for a POSIX shell and joins them
+func shellJoin(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,64 @@
+package launch
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"clientinfo/internal/askpass"
+)
+
+// TestTokenNotInArgv checks that no terminal command line carries the askpass token; ps ve
+// /proc/*/cmdline herkese açıktır.
+func TestTokenNotInArgv(t *testing.T) {
+	s, err := askpass.Start("/usr/bin/client-man", nil, func(string) (string, bool) { return "", false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var spec string
+	for _, kv := range s.Env {
+		if path, ok := strings.CutPrefix(kv, askpass.EnvVar+"="); ok {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			spec = string(data)
+		}
+	}
+	addr, token, ok := strings.Cut(spec, " ")
+	if !ok || token == "" {
+		t.Fatalf("unexpected askpass spec %q", spec)
+	}
+
+	argv := []string{"ssh", "-o", "ServerAliveInterval=30", "ops@10.0.0.1"}
+	for _, goos := range []string{"windows", "darwin", "linux"} {
+		cmds := commands(goos, argv, s.Env)
+		if len(cmds) == 0 {
+			t.Fatalf("%s: no terminal command", goos)
+		}
+		for _, cmd := range cmds {
+			line := strings.Join(cmd.Args, " ")
+			if strings.Contains(line, token) || strings.Contains(line, addr) {
+				t.Errorf("%s: %s exposes the askpass listener on its command line: %q", goos, cmd.Path, line)
+			}
+			if !containsAll(cmd.Env, s.Env) {
+				t.Errorf("%s: %s does not get the askpass env", goos, cmd.Path)
+			}
+		}
+	}
+}
+
+func containsAll(env, want []string) bool {
+	have := map[string]bool{}
+	for _, kv := range env {
+		have[kv] = true
+	}
+	for _, kv := range want {
+		if !have[kv] {
+			return false
+		}
+	}
+	return true
+}
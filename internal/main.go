@@ -8,20 +8,31 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"clientinfo/internal/askpass"
+	"clientinfo/internal/keystore"
+	"clientinfo/internal/store"
+	_ "clientinfo/internal/store/sqlite" // .db/.sqlite vault'ları için backend
+	"clientinfo/internal/vault"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
-	fynetooltip "github.com/dweymouth/fyne-tooltip"
 )
 
 func main() {
+	// ssh bu exe'yi SSH_ASKPASS olarak çalıştırdıysa pencere açmadan cevap verilir
+	if spec := os.Getenv(askpass.EnvVar); spec != "" {
+		os.Exit(askpass.Run(spec, strings.Join(os.Args[1:], " "), os.Stdout))
+	}
+
 	state := &AppState{
-		expandedCompanies: make(map[string]bool),
-		expandedApps:      make(map[string]map[int]bool),
-		activeTabIndex:    make(map[string]int),
+		expandedClients: make(map[string]bool),
+		expandedApps:    make(map[string]bool),
+		activeTabIndex:  make(map[string]int),
 	}
+	state.keys = keystore.NewOS()
 	state.myApp = app.NewWithID(AppID)
 	state.myApp.Settings().SetTheme(&blueTheme{Theme: theme.DefaultTheme()})
 
@@ -32,26 +43,32 @@ func main() {
 	// Icon ayarla - bundled resource kullan
 	state.window.SetIcon(resourceAppiconPng)
 
+	// Son açılan vault hâlâ duruyorsa onunla başla
 	state.currentFile = DefaultJSONFile
+	if last := state.myApp.Preferences().String(PrefLastVaultFile); last != "" {
+		if _, err := os.Stat(last); err == nil {
+			state.currentFile = last
+		}
+	}
 
-	if err := state.loadClients(state.currentFile); err != nil {
-		// Dosya yüklenemezse sadece uyarı göster, dosyayı bozma
-		if os.IsNotExist(err) {
-			dialog.ShowInformation("Bilgi", DefaultJSONFile+" dosyası bulunamadı. Yeni dosya oluşturulacak.", state.window)
+	status, err := store.DetectStatus(state.currentFile)
+	if err != nil {
+		// Dosya okunamazsa uyarı göster, bozuk dosyanın kopyasını yedekle ve boş başlat.
+		// Orijinal dosya ancak yeni vault atomik olarak yazıldığında değişir.
+		if _, backupErr := state.backupManager().Snapshot(); backupErr != nil {
+			dialog.ShowError(fmt.Errorf("JSON dosyası okunamadı: %w\n%v", err, backupErr), state.window)
 		} else {
 			dialog.ShowError(fmt.Errorf("JSON dosyası okunamadı: %w Dosya yedeklendi ve boş başlatıldı", err), state.window)
-			// Bozuk dosyayı yedekle
-			backupFile := state.currentFile + ".backup"
-			os.Rename(state.currentFile, backupFile)
 		}
-		state.clients = []Client{}
-		state.filteredClients = []Client{}
+		status = vault.StatusMissing
 	}
 
-	content := state.buildUI()
-	// Tooltip layer'ını ekle
-	contentWithTooltips := fynetooltip.AddWindowToolTipLayer(content, state.window.Canvas())
-	state.window.SetContent(contentWithTooltips)
+	// Keyring'de hatırlanan anahtar varsa doğrudan aç, yoksa master password ekranı
+	if status == vault.StatusProtected && state.unlockFromKeyring(state.currentFile) {
+		state.showMainUI()
+	} else {
+		state.showUnlockScreen(state.currentFile, status, nil)
+	}
 
 	state.window.ShowAndRun()
 }
@@ -0,0 +1,84 @@
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"clientinfo/internal/model"
+)
+
+// Import merges an incoming copy of a client (örn. müşteriden dönen export) into
+// the local one, field by field.
+//
+// Ortak taban olarak boş bir client kullanılır: sadece bir tarafta dolu olan alan
+// o taraftan alınır, iki tarafta farklı dolu alanlar Conflict olur (varsayılan
+// Local). Ortamlar önce ID, sonra tip ve adla eşleştirilir; sadece yerelde olan
+// ortamlar korunur, sadece gelende olanlar eklenmek üzere birer Conflict olarak
+// döner (varsayılan Remote, Local seçilirse eklenmez).
+func Import(local, incoming model.Client) *Result {
+	in := model.CloneClient(incoming)
+	theirs := model.CloneClient(in)
+	theirs.ID = local.ID
+	base := model.Client{ID: local.ID}
+
+	matched := make([]bool, len(in.Apps))
+	theirs.Apps = make([]model.AppInfo, len(local.Apps))
+	for i, app := range local.Apps {
+		base.Apps = append(base.Apps, model.AppInfo{ID: app.ID})
+		j := matchApp(app, in.Apps, matched)
+		if j < 0 {
+			// Gelen tarafta yok; değişmemiş sayılır
+			theirs.Apps[i] = app
+			continue
+		}
+		matched[j] = true
+		theirs.Apps[i] = in.Apps[j]
+		theirs.Apps[i].ID = app.ID
+		if envKey(in.Apps[j]) == envKey(app) {
+			// Sadece yazımı farklı tip / ad çakışma sayılmaz
+			theirs.Apps[i].Type, theirs.Apps[i].Name = app.Type, app.Name
+		}
+	}
+
+	r := &Result{}
+	r.mergeFields(local.Company, base, local, theirs)
+	it := &r.items[len(r.items)-1]
+	if it.merged == nil {
+		return r
+	}
+	for j, app := range in.Apps {
+		if matched[j] {
+			continue
+		}
+		path := fmt.Sprintf("apps[%d]", len(it.merged.Apps))
+		it.merged.Apps = append(it.merged.Apps, app)
+		raw, err := json.Marshal(app)
+		if err != nil {
+			continue
+		}
+		v := string(raw)
+		it.conflicts = append(it.conflicts, r.conflict(Conflict{Company: local.Company, Path: path, Remote: &v, Choice: Remote}))
+	}
+	return r
+}
+
+// matchApp returns the index of the unmatched incoming app that corresponds to app, -1 if none
+func matchApp(app model.AppInfo, incoming []model.AppInfo, matched []bool) int {
+	for j := range incoming {
+		if !matched[j] && app.ID != "" && incoming[j].ID == app.ID {
+			return j
+		}
+	}
+	for j := range incoming {
+		if !matched[j] && envKey(incoming[j]) == envKey(app) {
+			return j
+		}
+	}
+	return -1
+}
+
+// envKey identifies an environment by type and name; büyük/küçük harf ve boşluklar yok sayılır
+func envKey(app model.AppInfo) string {
+	return strings.ToUpper(strings.TrimSpace(app.Type)) + "\x00" + strings.ToLower(strings.TrimSpace(app.Name))
+}
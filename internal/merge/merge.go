@@ -0,0 +1,358 @@
+// Package merge combines two edited copies of a client list with their common
+// base (three-way merge).
+//
+// Client'lar ID ile, ID'si olmayanlar (eski sürümün yazdığı dosya) firma
+// adıyla eşleştirilir. Aynı client'ta iki tarafın farklı
+// alanlara yaptığı değişiklikler birleştirilir; aynı alanı farklı değerlere
+// getiren değişiklikler Conflict olarak döner. Liste elemanı eklenen veya
+// silinen bir listede (örn. "apps") diğer taraf da değişiklik yaptıysa liste
+// bütün olarak tek bir çakışmadır, çünkü indeksler artık eşleşmez.
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"clientinfo/internal/history"
+	"clientinfo/internal/model"
+)
+
+// Side selects which version wins a conflict
+type Side int
+
+const (
+	Local  Side = iota // Bu oturumdaki değişiklik
+	Remote             // Diskteki (başkasının) değişiklik
+	Edited             // Kullanıcının elle girdiği değer (Conflict.Edit)
+)
+
+// Conflict is a field, list or whole client changed differently on both sides.
+// Path boşsa client bir tarafta silinmiş, diğerinde değiştirilmiştir; değerler
+// yoksa nil'dir.
+type Conflict struct {
+	Company string
+	Path    string
+	Base    *string
+	Local   *string
+	Remote  *string
+	Choice  Side   // Varsayılan Local
+	Edit    string // Choice Edited ise JSON değer; client düzeyinde kullanılmaz
+}
+
+// Value returns the JSON value the conflict resolves to, nil if the field is absent on the chosen side
+func (c Conflict) Value() *string {
+	switch c.Choice {
+	case Remote:
+		return c.Remote
+	case Edited:
+		return &c.Edit
+	}
+	return c.Local
+}
+
+// Secret reports whether the conflict touches a `secret` tagged field
+func (c Conflict) Secret() bool {
+	return c.Path == "" || model.IsSecretPath(c.Path)
+}
+
+// Result is a merge whose conflicts can be resolved before Clients is called
+type Result struct {
+	Conflicts []Conflict
+	items     []item
+}
+
+// item is one client of the merged list
+type item struct {
+	merged    *model.Client // Çakışmasız değişiklikler uygulanmış hali; nil ise silinmiş
+	local     *model.Client // Client düzeyindeki çakışmada tarafların sürümleri
+	remote    *model.Client
+	conflicts []int // Result.Conflicts indeksleri
+}
+
+// Clients merges the changes made in local and remote since base.
+// Sıra remote'tan alınır; sadece local'de eklenen client'lar sona eklenir.
+func Clients(base, local, remote []model.Client) *Result {
+	r := &Result{}
+	b, l := newIndex(base), newIndex(local)
+	paired := map[*model.Client]bool{}
+
+	for i := range remote {
+		rc := &remote[i]
+		if rc.ID == "" {
+			// ID'yi bilmeyen bir sürüm yazmış; ID ortak tabandan (firma yerelde
+			// yeniden adlandırılmış olabilir) veya yerel kayıttan alınır
+			match := b.find(rc)
+			if match == nil {
+				match = l.find(rc)
+			}
+			if match != nil {
+				rc = clone(rc)
+				rc.ID = match.ID
+			}
+		}
+		lc := l.find(rc)
+		if lc != nil && paired[lc] {
+			// Aynı client'a eşleşen ikinci kayıt olduğu gibi kalır
+			c := *rc
+			r.items = append(r.items, item{merged: &c})
+			continue
+		}
+		if lc != nil {
+			paired[lc] = true
+		}
+		r.add(rc.Company, b.find(rc), lc, rc)
+	}
+	for i := range local {
+		if paired[&local[i]] {
+			continue
+		}
+		r.add(local[i].Company, b.find(&local[i]), &local[i], nil)
+	}
+	return r
+}
+
+// Clients returns the merged list with every conflict resolved by its Choice.
+// Seçilen tarafta olmayan liste elemanları (örn. eklenmemesi seçilen bir ortam)
+// birleştirilmiş client'tan çıkarılır.
+func (r *Result) Clients() ([]model.Client, error) {
+	out := []model.Client{}
+	for _, it := range r.items {
+		if it.merged == nil {
+			// Client düzeyinde çakışma: seçilen taraf bütün olarak alınır
+			chosen := it.local
+			if r.Conflicts[it.conflicts[0]].Choice == Remote {
+				chosen = it.remote
+			}
+			if chosen != nil {
+				out = append(out, model.CloneClient(*chosen))
+			}
+			continue
+		}
+
+		c := model.CloneClient(*it.merged)
+		var removed []string
+		for _, ci := range it.conflicts {
+			conflict := r.Conflicts[ci]
+			value := conflict.Value()
+			if value == nil {
+				if strings.HasSuffix(conflict.Path, "]") {
+					removed = append(removed, conflict.Path)
+				}
+				continue
+			}
+			if err := model.SetPath(&c, conflict.Path, *value); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", conflict.Company, conflict.Path, err)
+			}
+		}
+		// Çakışmalar artan sırayla eklendiğinden tersten silinir; indeksler kaymaz
+		for i := len(removed) - 1; i >= 0; i-- {
+			if _, ok := model.GetPath(c, removed[i]); !ok {
+				continue
+			}
+			if err := model.DeletePath(&c, removed[i]); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", c.Company, removed[i], err)
+			}
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// add merges one client; nil means the client does not exist on that side
+func (r *Result) add(company string, base, local, remote *model.Client) {
+	switch {
+	case local == nil && remote == nil:
+		return
+	case local == nil || remote == nil:
+		present := local
+		if present == nil {
+			present = remote
+		}
+		// Eklenen client alınır; silinen client diğer tarafta değişmediyse silinir
+		if base == nil {
+			r.items = append(r.items, item{merged: clone(present)})
+			return
+		}
+		if len(history.Compute(*base, *present)) == 0 {
+			return
+		}
+		r.items = append(r.items, item{
+			local:     clone(local),
+			remote:    clone(remote),
+			conflicts: []int{r.conflict(Conflict{Company: company, Base: encode(base), Local: encode(local), Remote: encode(remote)})},
+		})
+		return
+	}
+
+	// İki tarafta da var; aynı adla iki kez eklendiyse boş client ortak taban sayılır
+	from := model.Client{ID: local.ID, Company: company}
+	if base != nil {
+		from = *base
+	}
+	r.mergeFields(company, from, *local, *remote)
+}
+
+// mergeFields applies the non-conflicting local changes on top of remote and records the rest
+func (r *Result) mergeFields(company string, base, local, remote model.Client) {
+	localChanges := history.Compute(base, local)
+	remoteChanges := history.Compute(base, remote)
+
+	var areas []string
+	for _, lc := range localChanges {
+		for _, rc := range remoteChanges {
+			la, ra := area(lc), area(rc)
+			if overlaps(la, ra) {
+				areas = addArea(areas, shorter(la, ra))
+			}
+		}
+	}
+
+	it := item{}
+	var apply []history.Change
+	for _, lc := range localChanges {
+		if !insideAny(area(lc), areas) {
+			apply = append(apply, lc)
+		}
+	}
+	merged := model.CloneClient(remote)
+	if err := history.Apply(&merged, apply, false); err != nil {
+		// Uygulanamadıysa client bütün olarak çakışma sayılır
+		r.items = append(r.items, item{
+			local:     clone(&local),
+			remote:    clone(&remote),
+			conflicts: []int{r.conflict(Conflict{Company: company, Base: encode(&base), Local: encode(&local), Remote: encode(&remote)})},
+		})
+		return
+	}
+	it.merged = &merged
+
+	for _, path := range areas {
+		lv, lok := model.GetPath(local, path)
+		rv, rok := model.GetPath(remote, path)
+		if lok == rok && lv == rv {
+			// İki taraf aynı değişikliği yapmış
+			continue
+		}
+		c := Conflict{Company: company, Path: path}
+		if v, ok := model.GetPath(base, path); ok {
+			c.Base = &v
+		}
+		if lok {
+			c.Local = &lv
+		}
+		if rok {
+			c.Remote = &rv
+		}
+		it.conflicts = append(it.conflicts, r.conflict(c))
+	}
+	r.items = append(r.items, it)
+}
+
+func (r *Result) conflict(c Conflict) int {
+	r.Conflicts = append(r.Conflicts, c)
+	return len(r.Conflicts) - 1
+}
+
+// area is the part of the client a change touches: liste elemanı ekleyen veya
+// silen değişikliklerde listenin tamamı
+func area(ch history.Change) string {
+	if ch.Old != nil && ch.New != nil {
+		return ch.Path
+	}
+	if i := strings.LastIndexByte(ch.Path, '['); i > 0 && strings.HasSuffix(ch.Path, "]") {
+		return ch.Path[:i]
+	}
+	return ch.Path
+}
+
+// overlaps reports whether one path equals or contains the other
+func overlaps(a, b string) bool {
+	return contains(a, b) || contains(b, a)
+}
+
+// contains reports whether path lies inside outer
+func contains(outer, path string) bool {
+	return path == outer || strings.HasPrefix(path, outer+".") || strings.HasPrefix(path, outer+"[")
+}
+
+func shorter(a, b string) string {
+	if len(a) <= len(b) {
+		return a
+	}
+	return b
+}
+
+// addArea adds path unless an existing area contains it, dropping areas path contains
+func addArea(areas []string, path string) []string {
+	for _, a := range areas {
+		if contains(a, path) {
+			return areas
+		}
+	}
+	var out []string
+	for _, a := range areas {
+		if !contains(path, a) {
+			out = append(out, a)
+		}
+	}
+	return append(out, path)
+}
+
+func insideAny(path string, areas []string) bool {
+	for _, a := range areas {
+		if overlaps(a, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// index finds the client of one side that corresponds to a client of another
+type index struct {
+	byID      map[string]*model.Client
+	byCompany map[string]*model.Client
+}
+
+func newIndex(clients []model.Client) index {
+	x := index{byID: map[string]*model.Client{}, byCompany: map[string]*model.Client{}}
+	for i := range clients {
+		c := &clients[i]
+		if _, ok := x.byID[c.ID]; c.ID != "" && !ok {
+			x.byID[c.ID] = c
+		}
+		if _, ok := x.byCompany[c.Company]; !ok {
+			x.byCompany[c.Company] = c
+		}
+	}
+	return x
+}
+
+// find matches c by ID; ID'si olmayan client firma adıyla eşleşir
+func (x index) find(c *model.Client) *model.Client {
+	if c.ID != "" {
+		return x.byID[c.ID]
+	}
+	return x.byCompany[c.Company]
+}
+
+func clone(c *model.Client) *model.Client {
+	if c == nil {
+		return nil
+	}
+	out := model.CloneClient(*c)
+	return &out
+}
+
+// encode returns c as a JSON value, nil if c is nil
+func encode(c *model.Client) *string {
+	if c == nil {
+		return nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil
+	}
+	v := string(b)
+	return &v
+}
@@ -0,0 +1,44 @@
+package model
+
+import "fmt"
+
+// BastionChain returns the bastions to pass through to reach a host behind id, ilk atlanan başta.
+// Via bağlantıları izlenir; eksik bastion ve döngü hatadır. id boşsa zincir boştur.
+func (c *Client) BastionChain(id string) ([]Bastion, error) {
+	var chain []Bastion
+	seen := map[string]bool{}
+	for id != "" {
+		if seen[id] {
+			return nil, fmt.Errorf("bastion chain of %s loops", c.Company)
+		}
+		seen[id] = true
+		b := c.BastionByID(id)
+		if b == nil {
+			return nil, fmt.Errorf("bastion %s of %s does not exist", id, c.Company)
+		}
+		chain = append([]Bastion{*b}, chain...)
+		id = b.Via
+	}
+	return chain, nil
+}
+
+// RemoveBastion deletes a bastion and every reference to it.
+// Onu kullanan ortamlar ve bastion'lar silinenin Via'sına bağlanır; zincirin geri kalanı korunur.
+func (c *Client) RemoveBastion(id string) {
+	i := c.BastionIndex(id)
+	if i < 0 {
+		return
+	}
+	via := c.Bastions[i].Via
+	c.Bastions = append(c.Bastions[:i], c.Bastions[i+1:]...)
+	for j := range c.Apps {
+		if c.Apps[j].Bastion == id {
+			c.Apps[j].Bastion = via
+		}
+	}
+	for j := range c.Bastions {
+		if c.Bastions[j].Via == id {
+			c.Bastions[j].Via = via
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// Client ve AppInfo kayıtları liste sırası veya firma adı yerine kalıcı bir
+// UUID ile tanınır; yeniden adlandırma ve silme işlemleri arayüz durumunu,
+// geçmişi ve import eşleşmesini bozmaz.
+
+// NewID returns a random version 4 UUID
+func NewID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// EnsureIDs gives every client, app, tunnel and bastion without an ID, or with one already used
+// in clients, a new ID. Bir şey değiştiyse true döner (kaydedilmesi gerekir).
+func EnsureIDs(clients []Client) bool {
+	seen := map[string]bool{}
+	changed := false
+	assign := func(id *string) {
+		if *id == "" || seen[*id] {
+			*id = NewID()
+			changed = true
+		}
+		seen[*id] = true
+	}
+	for i := range clients {
+		assign(&clients[i].ID)
+		for j := range clients[i].Apps {
+			assign(&clients[i].Apps[j].ID)
+			for k := range clients[i].Apps[j].Tunnels {
+				assign(&clients[i].Apps[j].Tunnels[k].ID)
+			}
+		}
+		for j := range clients[i].Bastions {
+			assign(&clients[i].Bastions[j].ID)
+		}
+	}
+	return changed
+}
+
+// IndexByID returns the position of the client with id, -1 if missing
+func IndexByID(clients []Client, id string) int {
+	if id == "" {
+		return -1
+	}
+	for i := range clients {
+		if clients[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// AppIndex returns the position of the app with id, -1 if missing
+func (c *Client) AppIndex(id string) int {
+	if id == "" {
+		return -1
+	}
+	for i := range c.Apps {
+		if c.Apps[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// AppByID returns the app with id, nil if missing
+func (c *Client) AppByID(id string) *AppInfo {
+	if i := c.AppIndex(id); i >= 0 {
+		return &c.Apps[i]
+	}
+	return nil
+}
+
+// BastionIndex returns the position of the bastion with id, -1 if missing
+func (c *Client) BastionIndex(id string) int {
+	if id == "" {
+		return -1
+	}
+	for i := range c.Bastions {
+		if c.Bastions[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// BastionByID returns the bastion with id, nil if missing
+func (c *Client) BastionByID(id string) *Bastion {
+	if i := c.BastionIndex(id); i >= 0 {
+		return &c.Bastions[i]
+	}
+	return nil
+}
+
+// TunnelIndex returns the position of the tunnel with id, -1 if missing
+func (a *AppInfo) TunnelIndex(id string) int {
+	if id == "" {
+		return -1
+	}
+	for i := range a.Tunnels {
+		if a.Tunnels[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// TunnelByID returns the tunnel with id, nil if missing
+func (a *AppInfo) TunnelByID(id string) *Tunnel {
+	if i := a.TunnelIndex(id); i >= 0 {
+		return &a.Tunnels[i]
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package model defines the client data structures shared by the app and its tools.
+//
+// Gizli alanlar `secret` struct tag'i ile işaretlenir:
+//
+//	secret:"true"     string alanın tamamı şifrelenir
+//	secret:"userpass" []string içindeki "kullanıcı/şifre" satırlarının şifre kısmı şifrelenir
+package model
+
+// VPNInfo holds VPN connection details
+type VPNInfo struct {
+	App           string `json:"app"`
+	Host          string `json:"host"`
+	User          string `json:"user"`
+	Password      string `json:"password" secret:"true"`
+	TwoFATokenApp string `json:"two_fa_token_app"`
+	Notes         string `json:"not"`
+}
+
+// ClientData holds system-specific information
+type ClientData struct {
+	JiraURI       string   `json:"jira_uri"`
+	JiraUser      string   `json:"jira_user"`
+	JiraPassword  string   `json:"jira_password" secret:"true"`
+	User          string   `json:"user"`
+	PasswordReset string   `json:"pass_reset"`
+	RDC           []string `json:"rdc"`
+	Hosts         []string `json:"hosts"`
+	Notes         string   `json:"not"`
+}
+
+// AppInfo holds application environment details
+type AppInfo struct {
+	ID            string   `json:"id,omitempty"` // Kalıcı UUID, bkz. EnsureIDs
+	Type          string   `json:"type"`
+	Name          string   `json:"name"`
+	User          string   `json:"user"`
+	Password      string   `json:"pass" secret:"true"`
+	DBServerIP    string   `json:"db_server_ip"`
+	TNS           string   `json:"tns"`
+	AppServerIP   string   `json:"app_server_ip"`
+	AppServerURI  string   `json:"app_server_uri"`
+	AppServerUser string   `json:"app_server_user"`
+	AppServerPass string   `json:"app_server_pass" secret:"true"`
+	WeblogicPass  string   `json:"weblogic_pass" secret:"true"`
+	AppURI        string   `json:"app_uri"`
+	AppUsers      []string `json:"app_users" secret:"userpass"`
+	SSHParams     string   `json:"ssh_params"`
+	Bastion       string   `json:"bastion"`   // Üzerinden bağlanılan bastion'ın ID'si, boşsa doğrudan
+	HostKeys      []string `json:"host_keys"` // Güvenilen SSH host anahtarları, known_hosts satırı olarak
+	Tunnels       []Tunnel `json:"tunnels"`
+	Notes         string   `json:"not"`
+}
+
+// Tunnel is a local port forward of an environment through its app server (ssh -L)
+type Tunnel struct {
+	ID            string `json:"id,omitempty"` // Kalıcı UUID, bkz. EnsureIDs
+	Name          string `json:"name"`
+	LocalPort     int    `json:"local_port"`  // 127.0.0.1 üzerinde dinlenir
+	RemoteHost    string `json:"remote_host"` // App server'dan görüldüğü haliyle, örn. DB server IP
+	RemotePort    int    `json:"remote_port"`
+	AutoReconnect bool   `json:"auto_reconnect"`
+}
+
+// Bastion is a jump host of a client that environments connect through.
+// Bastion'lar Via ile birbirine zincirlenebilir; her biri kendi kimlik bilgisiyle girilir.
+type Bastion struct {
+	ID       string   `json:"id,omitempty"` // Kalıcı UUID, bkz. EnsureIDs
+	Name     string   `json:"name"`
+	Host     string   `json:"host"` // IP veya ad; "host:port" de olabilir
+	User     string   `json:"user"`
+	Password string   `json:"password" secret:"true"`
+	KeyFile  string   `json:"key_file"`
+	Via      string   `json:"via"`       // Önceki bastion'ın ID'si, boşsa doğrudan
+	HostKeys []string `json:"host_keys"` // Güvenilen SSH host anahtarları, known_hosts satırı olarak
+	Notes    string   `json:"not"`
+}
+
+// Client represents a single client with all their information
+type Client struct {
+	ID         string     `json:"id,omitempty"` // Kalıcı UUID, bkz. EnsureIDs
+	Company    string     `json:"company"`
+	EBSVersion string     `json:"ebs_version"`
+	VPN        VPNInfo    `json:"vpn"`
+	Data       ClientData `json:"data"`
+	Apps       []AppInfo  `json:"apps"`
+	Bastions   []Bastion  `json:"bastions"`
+	Notes      string     `json:"not"`
+}
@@ -0,0 +1,432 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field paths use the JSON names of a client, örn. "vpn.password" veya
+// "apps[1].app_users[0]". Değerler JSON olarak kodlanır; bir liste elemanının
+// tamamı da tek bir yol olabilir ("apps[2]").
+
+// ErrNoSuchPath is returned when a path does not exist in a client
+var ErrNoSuchPath = errors.New("field no longer exists")
+
+// FieldValues flattens c into path → JSON value pairs. nil listeler boş liste gibi yok sayılır.
+func FieldValues(c Client) map[string]string {
+	out := map[string]string{}
+	flattenValue("", toGeneric(c), out)
+	return out
+}
+
+// DiffPaths returns the sorted paths whose values differ between a and b.
+// Sadece bir tarafta olan liste elemanları (örn. eklenmiş bir app) tek bir yol olarak döner.
+func DiffPaths(a, b Client) []string {
+	fa, fb := FieldValues(a), FieldValues(b)
+	seen := map[string]bool{}
+	var paths []string
+	add := func(path string) {
+		if elem := missingElement(path, fa, fb); elem != "" {
+			path = elem
+		}
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	for path, v := range fa {
+		if w, ok := fb[path]; !ok || w != v {
+			add(path)
+		}
+	}
+	for path := range fb {
+		if _, ok := fa[path]; !ok {
+			add(path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// GetPath returns the JSON value at path, false if it does not exist
+func GetPath(c Client, path string) (string, bool) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return "", false
+	}
+	node := toGeneric(c)
+	for _, seg := range segs {
+		next, ok := seg.get(node)
+		if !ok {
+			return "", false
+		}
+		node = next
+	}
+	b, err := json.Marshal(node)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// SetPath replaces the value at an existing path, or appends when path is the next list index
+func SetPath(c *Client, path string, value string) error {
+	return editPath(c, path, func(parent interface{}, last pathSegment) (interface{}, error) {
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, err
+		}
+		return last.set(parent, v, false)
+	})
+}
+
+// InsertPath inserts a list element at path, shifting later elements
+func InsertPath(c *Client, path string, value string) error {
+	return editPath(c, path, func(parent interface{}, last pathSegment) (interface{}, error) {
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, err
+		}
+		return last.set(parent, v, true)
+	})
+}
+
+// DeletePath removes the list element at path
+func DeletePath(c *Client, path string) error {
+	return editPath(c, path, func(parent interface{}, last pathSegment) (interface{}, error) {
+		return last.remove(parent)
+	})
+}
+
+// IsSecretPath reports whether path holds, or contains, a `secret` tagged value
+func IsSecretPath(path string) bool {
+	segs, err := parsePath(path)
+	if err != nil {
+		return false
+	}
+	t := reflect.TypeOf(Client{})
+	for _, seg := range segs {
+		if seg.key == "" {
+			// Liste elemanı
+			if t.Kind() != reflect.Slice {
+				return false
+			}
+			t = t.Elem()
+			continue
+		}
+		if t.Kind() != reflect.Struct {
+			return false
+		}
+		sf, ok := fieldByJSONName(t, seg.key)
+		if !ok {
+			return false
+		}
+		if sf.Tag.Get(secretTag) != "" {
+			return true
+		}
+		t = sf.Type
+	}
+	return containsSecret(t)
+}
+
+// SecretKind returns the `secret` tag of the field at path (SecretWhole veya
+// SecretUserPass), "" if the field is not secret. Liste elemanları listenin
+// etiketini taşır; içinde gizli alan olan yapılar için "" döner.
+func SecretKind(path string) string {
+	segs, err := parsePath(path)
+	if err != nil {
+		return ""
+	}
+	t := reflect.TypeOf(Client{})
+	tag := ""
+	for _, seg := range segs {
+		if seg.key == "" {
+			if t.Kind() != reflect.Slice {
+				return ""
+			}
+			t = t.Elem()
+			continue
+		}
+		if t.Kind() != reflect.Struct {
+			return ""
+		}
+		sf, ok := fieldByJSONName(t, seg.key)
+		if !ok {
+			return ""
+		}
+		tag = sf.Tag.Get(secretTag)
+		t = sf.Type
+	}
+	if t.Kind() != reflect.String {
+		return ""
+	}
+	return tag
+}
+
+// SecretLineMask hides the password half of a "user/password" line
+func SecretLineMask(line string) string {
+	user, _, ok := SplitUserPass(line)
+	if !ok {
+		return "••••"
+	}
+	return user + "/••••"
+}
+
+func containsSecret(t reflect.Type) bool {
+	for t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Tag.Get(secretTag) != "" || containsSecret(sf.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if strings.Split(sf.Tag.Get("json"), ",")[0] == name {
+			return sf, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// editPath decodes c, lets fn change the parent of the last segment and encodes the result back into c
+func editPath(c *Client, path string, fn func(parent interface{}, last pathSegment) (interface{}, error)) error {
+	segs, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	root := toGeneric(*c)
+	updated, err := editNode(root, segs, fn)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+	var out Client
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return err
+	}
+	*c = out
+	return nil
+}
+
+// editNode walks down to the parent of the last segment; listeler yeniden
+// oluşabildiği için her seviye güncellenmiş değeri üst seviyeye geri yazar.
+func editNode(node interface{}, segs []pathSegment, fn func(parent interface{}, last pathSegment) (interface{}, error)) (interface{}, error) {
+	if len(segs) == 1 {
+		return fn(node, segs[0])
+	}
+	child, ok := segs[0].get(node)
+	if !ok {
+		return nil, ErrNoSuchPath
+	}
+	updated, err := editNode(child, segs[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+	return segs[0].set(node, updated, false)
+}
+
+// pathSegment is either an object key or a list index (key == "")
+type pathSegment struct {
+	key   string
+	index int
+}
+
+func (s pathSegment) get(node interface{}) (interface{}, bool) {
+	if s.key != "" {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		v, ok := m[s.key]
+		return v, ok
+	}
+	list, _ := node.([]interface{})
+	if s.index < 0 || s.index >= len(list) {
+		return nil, false
+	}
+	return list[s.index], true
+}
+
+// set stores v and returns the (possibly new) container. insert true ise liste elemanı araya eklenir.
+func (s pathSegment) set(node interface{}, v interface{}, insert bool) (interface{}, error) {
+	if s.key != "" {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, ErrNoSuchPath
+		}
+		if _, exists := m[s.key]; !exists {
+			return nil, ErrNoSuchPath
+		}
+		m[s.key] = v
+		return m, nil
+	}
+
+	// nil liste boş liste gibi davranır
+	list, ok := node.([]interface{})
+	if !ok && node != nil {
+		return nil, ErrNoSuchPath
+	}
+	switch {
+	case insert && s.index >= 0 && s.index <= len(list):
+		list = append(list, nil)
+		copy(list[s.index+1:], list[s.index:])
+		list[s.index] = v
+	case s.index == len(list):
+		list = append(list, v)
+	case s.index >= 0 && s.index < len(list):
+		list[s.index] = v
+	default:
+		return nil, ErrNoSuchPath
+	}
+	return list, nil
+}
+
+func (s pathSegment) remove(node interface{}) (interface{}, error) {
+	list, ok := node.([]interface{})
+	if s.key != "" || !ok || s.index < 0 || s.index >= len(list) {
+		return nil, ErrNoSuchPath
+	}
+	return append(list[:s.index], list[s.index+1:]...), nil
+}
+
+// parsePath splits "apps[1].app_users[0]" into segments
+func parsePath(path string) ([]pathSegment, error) {
+	var segs []pathSegment
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			key = part[:i]
+			part = part[i:]
+		} else {
+			part = ""
+		}
+		if key == "" {
+			return nil, fmt.Errorf("invalid field path %q", path)
+		}
+		segs = append(segs, pathSegment{key: key})
+		for part != "" {
+			end := strings.IndexByte(part, ']')
+			if part[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid field path %q", path)
+			}
+			n, err := strconv.Atoi(part[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid field path %q", path)
+			}
+			segs = append(segs, pathSegment{index: n})
+			part = part[end+1:]
+		}
+	}
+	return segs, nil
+}
+
+func toGeneric(c Client) interface{} {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func flattenValue(path string, v interface{}, out map[string]string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, child := range t {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			flattenValue(p, child, out)
+		}
+	case []interface{}:
+		for i, child := range t {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), child, out)
+		}
+	case nil:
+		// nil ve boş liste aynı kabul edilir
+	default:
+		b, _ := json.Marshal(t)
+		out[path] = string(b)
+	}
+}
+
+// missingElement returns the outermost list element prefix of path that exists on only one side
+func missingElement(path string, fa, fb map[string]string) string {
+	for i := 0; i < len(path); i++ {
+		if path[i] != ']' {
+			continue
+		}
+		elem := path[:i+1]
+		if hasPathPrefix(fa, elem) != hasPathPrefix(fb, elem) {
+			return elem
+		}
+	}
+	return ""
+}
+
+func hasPathPrefix(m map[string]string, elem string) bool {
+	for path := range m {
+		if path == elem || strings.HasPrefix(path, elem+".") || strings.HasPrefix(path, elem+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// LessPath orders paths with list indexes compared numerically ("apps[9]" < "apps[10]")
+func LessPath(a, b string) bool {
+	for a != "" && b != "" {
+		na, ra := leadingNumber(a)
+		nb, rb := leadingNumber(b)
+		if na >= 0 && nb >= 0 {
+			if na != nb {
+				return na < nb
+			}
+			a, b = ra, rb
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func leadingNumber(s string) (int, string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return -1, s
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return -1, s
+	}
+	return n, s[i:]
+}
@@ -0,0 +1,231 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	secretTag = "secret"
+
+	// SecretWhole marks a string field whose whole value is a secret
+	SecretWhole = "true"
+	// SecretUserPass marks a []string of "user/password" lines; only the password half is secret
+	SecretUserPass = "userpass"
+)
+
+// SecretFunc is called for every non-empty secret value. field is the path inside the
+// client (örn. "Apps[0].AppServerPass"); value may be replaced in place.
+type SecretFunc func(c *Client, field string, value *string) error
+
+// VisitSecrets walks every field tagged with `secret` in clients and calls fn for each
+// non-empty value. Şifreleme, çözme ve plaintext kontrolü bu tek tanımdan beslenir.
+func VisitSecrets(clients []Client, fn SecretFunc) error {
+	for i := range clients {
+		c := &clients[i]
+		if err := visitStruct(reflect.ValueOf(c).Elem(), "", c, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TransformSecrets replaces every secret value with the result of fn
+func TransformSecrets(clients []Client, fn func(string) (string, error)) error {
+	return VisitSecrets(clients, func(_ *Client, _ string, value *string) error {
+		out, err := fn(*value)
+		if err != nil {
+			return err
+		}
+		*value = out
+		return nil
+	})
+}
+
+// SplitUserPass splits an AppUsers line into user and password at the first "/"
+func SplitUserPass(line string) (user, password string, ok bool) {
+	parts := strings.SplitN(line, "/", 2)
+	if len(parts) < 2 {
+		return parts[0], "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func visitStruct(v reflect.Value, prefix string, c *Client, fn SecretFunc) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fv := v.Field(i)
+		path := prefix + sf.Name
+
+		switch sf.Tag.Get(secretTag) {
+		case SecretWhole:
+			if fv.Kind() != reflect.String {
+				return fmt.Errorf("secret field %s must be a string", path)
+			}
+			if err := visitString(fv, path, c, fn); err != nil {
+				return err
+			}
+			continue
+		case SecretUserPass:
+			if fv.Kind() != reflect.Slice || fv.Type().Elem().Kind() != reflect.String {
+				return fmt.Errorf("userpass field %s must be a []string", path)
+			}
+			if err := visitUserPass(fv, path, c, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch fv.Kind() {
+		case reflect.Struct:
+			if err := visitStruct(fv, path+".", c, fn); err != nil {
+				return err
+			}
+		case reflect.Slice:
+			if fv.Type().Elem().Kind() != reflect.Struct {
+				continue
+			}
+			for j := 0; j < fv.Len(); j++ {
+				if err := visitStruct(fv.Index(j), fmt.Sprintf("%s[%d].", path, j), c, fn); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func visitString(fv reflect.Value, path string, c *Client, fn SecretFunc) error {
+	value := fv.String()
+	if value == "" {
+		return nil
+	}
+	if err := fn(c, path, &value); err != nil {
+		return err
+	}
+	fv.SetString(value)
+	return nil
+}
+
+func visitUserPass(fv reflect.Value, path string, c *Client, fn SecretFunc) error {
+	for j := 0; j < fv.Len(); j++ {
+		line := fv.Index(j)
+		user, password, ok := SplitUserPass(line.String())
+		if !ok || password == "" {
+			continue
+		}
+		if err := fn(c, fmt.Sprintf("%s[%d]", path, j), &password); err != nil {
+			return err
+		}
+		line.SetString(user + "/" + password)
+	}
+	return nil
+}
+
+// SplitSecrets returns a copy of c with every `secret` value removed, and a sparse
+// client of the same shape holding only those values. userpass satırlarında
+// kullanıcı adı "user/" olarak açık tarafta kalır, şifre gizli tarafa taşınır.
+func SplitSecrets(c Client) (public, secrets Client) {
+	public = CloneClient(c)
+	splitStruct(reflect.ValueOf(&public).Elem(), reflect.ValueOf(&secrets).Elem())
+	return public, secrets
+}
+
+// MergeSecrets puts the values split off by SplitSecrets back into public
+func MergeSecrets(public *Client, secrets Client) {
+	mergeStruct(reflect.ValueOf(public).Elem(), reflect.ValueOf(secrets))
+}
+
+// CloneClient returns a deep copy of c (nil ve boş listeler korunur)
+func CloneClient(c Client) Client {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return c
+	}
+	var out Client
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return c
+	}
+	return out
+}
+
+func splitStruct(pub, sec reflect.Value) {
+	t := pub.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		pv, sv := pub.Field(i), sec.Field(i)
+
+		switch sf.Tag.Get(secretTag) {
+		case SecretWhole:
+			sv.SetString(pv.String())
+			pv.SetString("")
+			continue
+		case SecretUserPass:
+			passwords := make([]string, pv.Len())
+			found := false
+			for j := 0; j < pv.Len(); j++ {
+				user, password, ok := SplitUserPass(pv.Index(j).String())
+				if ok && password != "" {
+					passwords[j] = password
+					pv.Index(j).SetString(user + "/")
+					found = true
+				}
+			}
+			if found {
+				sv.Set(reflect.ValueOf(passwords))
+			}
+			continue
+		}
+
+		switch pv.Kind() {
+		case reflect.Struct:
+			splitStruct(pv, sv)
+		case reflect.Slice:
+			if pv.Type().Elem().Kind() != reflect.Struct {
+				continue
+			}
+			sv.Set(reflect.MakeSlice(pv.Type(), pv.Len(), pv.Len()))
+			for j := 0; j < pv.Len(); j++ {
+				splitStruct(pv.Index(j), sv.Index(j))
+			}
+		}
+	}
+}
+
+func mergeStruct(pub, sec reflect.Value) {
+	t := pub.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		pv, sv := pub.Field(i), sec.Field(i)
+
+		switch sf.Tag.Get(secretTag) {
+		case SecretWhole:
+			if sv.String() != "" {
+				pv.SetString(sv.String())
+			}
+			continue
+		case SecretUserPass:
+			for j := 0; j < pv.Len() && j < sv.Len(); j++ {
+				if password := sv.Index(j).String(); password != "" {
+					pv.Index(j).SetString(pv.Index(j).String() + password)
+				}
+			}
+			continue
+		}
+
+		switch pv.Kind() {
+		case reflect.Struct:
+			mergeStruct(pv, sv)
+		case reflect.Slice:
+			if pv.Type().Elem().Kind() != reflect.Struct {
+				continue
+			}
+			for j := 0; j < pv.Len() && j < sv.Len(); j++ {
+				mergeStruct(pv.Index(j), sv.Index(j))
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package passman
+
+import (
+	"encoding/json"
+	"errors"
+
+	"clientinfo/internal/model"
+)
+
+// ExtBitwarden is the file extension of Bitwarden exports
+const ExtBitwarden = ".json"
+
+// unfiledFolder collects Bitwarden items that are in no folder
+const unfiledFolder = "Bitwarden"
+
+// Bitwarden item and field types
+const (
+	bitwardenTypeLogin   = 1
+	bitwardenTextField   = 0
+	bitwardenHiddenField = 1
+)
+
+// bitwardenExport is the unencrypted JSON export of Bitwarden (Tools > Export vault > .json)
+type bitwardenExport struct {
+	Encrypted bool              `json:"encrypted"`
+	Folders   []bitwardenFolder `json:"folders"`
+	Items     []bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenItem struct {
+	ID       string           `json:"id"`
+	FolderID *string          `json:"folderId"`
+	Type     int              `json:"type"`
+	Name     string           `json:"name"`
+	Notes    *string          `json:"notes"`
+	Favorite bool             `json:"favorite"`
+	Fields   []bitwardenField `json:"fields,omitempty"`
+	Login    *bitwardenLogin  `json:"login,omitempty"`
+}
+
+type bitwardenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
+type bitwardenLogin struct {
+	URIs     []bitwardenURI `json:"uris,omitempty"`
+	Username *string        `json:"username"`
+	Password *string        `json:"password"`
+	TOTP     *string        `json:"totp"`
+}
+
+type bitwardenURI struct {
+	Match *int   `json:"match"`
+	URI   string `json:"uri"`
+}
+
+// EncodeBitwarden writes clients as a Bitwarden unencrypted JSON export
+func EncodeBitwarden(clients []model.Client) ([]byte, error) {
+	export := bitwardenExport{Folders: []bitwardenFolder{}, Items: []bitwardenItem{}}
+	for _, folder := range ToFolders(clients) {
+		folderID := model.NewID()
+		export.Folders = append(export.Folders, bitwardenFolder{ID: folderID, Name: folder.Name})
+		for _, it := range folder.Items {
+			export.Items = append(export.Items, bitwardenItemOf(it, folderID))
+		}
+	}
+	return json.MarshalIndent(export, "", "  ")
+}
+
+func bitwardenItemOf(it Item, folderID string) bitwardenItem {
+	item := bitwardenItem{
+		ID:       model.NewID(),
+		FolderID: &folderID,
+		Type:     bitwardenTypeLogin,
+		Name:     it.Title,
+		Login:    &bitwardenLogin{},
+	}
+	for _, f := range it.Fields {
+		value := f.Value
+		switch f.Name {
+		case FieldUserName:
+			item.Login.Username = &value
+		case FieldPassword:
+			item.Login.Password = &value
+		case FieldURL:
+			item.Login.URIs = []bitwardenURI{{URI: value}}
+		case FieldNotes:
+			item.Notes = &value
+		default:
+			fieldType := bitwardenTextField
+			if f.Hidden {
+				fieldType = bitwardenHiddenField
+			}
+			item.Fields = append(item.Fields, bitwardenField{Name: f.Name, Value: value, Type: fieldType})
+		}
+	}
+	return item
+}
+
+// DecodeBitwarden reads the clients of a Bitwarden unencrypted JSON export.
+// Her klasör bir client olur; klasörsüz kayıtlar "Bitwarden" adlı bir client'ta toplanır.
+func DecodeBitwarden(data []byte) ([]model.Client, error) {
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	if export.Encrypted {
+		return nil, errors.New("encrypted Bitwarden exports cannot be read; export the vault as unencrypted .json")
+	}
+
+	var folders []Folder
+	index := map[string]int{}
+	folderFor := func(id, name string) *Folder {
+		if i, ok := index[id]; ok {
+			return &folders[i]
+		}
+		index[id] = len(folders)
+		folders = append(folders, Folder{Name: name})
+		return &folders[len(folders)-1]
+	}
+	for _, f := range export.Folders {
+		folderFor(f.ID, f.Name)
+	}
+
+	for _, item := range export.Items {
+		folderID := ""
+		if item.FolderID != nil {
+			folderID = *item.FolderID
+		}
+		folder := folderFor(folderID, unfiledFolder)
+		folder.Items = append(folder.Items, bitwardenItemFields(item))
+	}
+
+	// Boş klasörler client olmaz
+	var used []Folder
+	for _, f := range folders {
+		if len(f.Items) > 0 {
+			used = append(used, f)
+		}
+	}
+	return FromFolders(used), nil
+}
+
+// bitwardenItemFields turns a Bitwarden item back into an Item; sıra kayıt sırasını izler
+func bitwardenItemFields(item bitwardenItem) Item {
+	it := Item{Title: item.Name}
+	if item.Login != nil {
+		if len(item.Login.URIs) > 0 {
+			it.Fields = append(it.Fields, Field{Name: FieldURL, Value: item.Login.URIs[0].URI})
+		}
+		if item.Login.Username != nil {
+			it.Fields = append(it.Fields, Field{Name: FieldUserName, Value: *item.Login.Username})
+		}
+		if item.Login.Password != nil {
+			it.Fields = append(it.Fields, Field{Name: FieldPassword, Value: *item.Login.Password, Hidden: true})
+		}
+	}
+	for _, f := range item.Fields {
+		it.Fields = append(it.Fields, Field{Name: f.Name, Value: f.Value, Hidden: f.Type == bitwardenHiddenField})
+	}
+	if item.Notes != nil {
+		it.Fields = append(it.Fields, Field{Name: FieldNotes, Value: *item.Notes})
+	}
+	return it
+}
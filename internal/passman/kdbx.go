@@ -0,0 +1,112 @@
+package passman
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"clientinfo/internal/model"
+	"github.com/tobischo/gokeepasslib/v3"
+	w "github.com/tobischo/gokeepasslib/v3/wrappers"
+)
+
+// ExtKDBX is the file extension of KeePass databases
+const ExtKDBX = ".kdbx"
+
+// kdbxRootName is the root group of exported databases
+const kdbxRootName = "client-man"
+
+// ErrWrongKDBXPassword is returned when a KeePass database cannot be opened with the given password
+var ErrWrongKDBXPassword = errors.New("the KeePass database cannot be opened with this password")
+
+// EncodeKDBX writes clients as a KDBX 4 database protected by password
+func EncodeKDBX(clients []model.Client, password string) ([]byte, error) {
+	if password == "" {
+		return nil, errors.New("password is empty")
+	}
+
+	root := gokeepasslib.NewGroup()
+	root.Name = kdbxRootName
+	for _, folder := range ToFolders(clients) {
+		group := gokeepasslib.NewGroup()
+		group.Name = folder.Name
+		for _, it := range folder.Items {
+			group.Entries = append(group.Entries, kdbxEntry(it))
+		}
+		root.Groups = append(root.Groups, group)
+	}
+
+	db := gokeepasslib.NewDatabase(gokeepasslib.WithDatabaseKDBXVersion4())
+	db.Credentials = gokeepasslib.NewPasswordCredentials(password)
+	db.Content.Meta.DatabaseName = kdbxRootName
+	db.Content.Root = &gokeepasslib.RootData{Groups: []gokeepasslib.Group{root}}
+	if err := db.LockProtectedEntries(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := gokeepasslib.NewEncoder(&buf).Encode(db); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func kdbxEntry(it Item) gokeepasslib.Entry {
+	entry := gokeepasslib.NewEntry()
+	entry.Values = append(entry.Values, gokeepasslib.ValueData{Key: FieldTitle, Value: gokeepasslib.V{Content: it.Title}})
+	for _, f := range it.Fields {
+		v := gokeepasslib.V{Content: f.Value}
+		if f.Hidden {
+			v.Protected = w.NewBoolWrapper(true)
+		}
+		entry.Values = append(entry.Values, gokeepasslib.ValueData{Key: f.Name, Value: v})
+	}
+	return entry
+}
+
+// DecodeKDBX reads the clients of a KeePass database (KDBX 3.1 veya 4).
+// Kaydı olan her grup bir client olur; alt gruplar da dolaşılır.
+func DecodeKDBX(data []byte, password string) ([]model.Client, error) {
+	db := gokeepasslib.NewDatabase()
+	db.Credentials = gokeepasslib.NewPasswordCredentials(password)
+	if err := gokeepasslib.NewDecoder(bytes.NewReader(data)).Decode(db); err != nil {
+		// KDBX 4'te yanlış şifre başlık HMAC hatası olarak döner
+		if errors.Is(err, gokeepasslib.ErrInvalidDatabaseOrCredentials) || strings.HasPrefix(err.Error(), "Wrong password?") {
+			return nil, ErrWrongKDBXPassword
+		}
+		return nil, err
+	}
+	if err := db.UnlockProtectedEntries(); err != nil {
+		return nil, err
+	}
+
+	var folders []Folder
+	var walk func(groups []gokeepasslib.Group)
+	walk = func(groups []gokeepasslib.Group) {
+		for _, g := range groups {
+			if len(g.Entries) > 0 {
+				folder := Folder{Name: g.Name}
+				for _, entry := range g.Entries {
+					folder.Items = append(folder.Items, kdbxItem(entry))
+				}
+				folders = append(folders, folder)
+			}
+			walk(g.Groups)
+		}
+	}
+	if db.Content != nil && db.Content.Root != nil {
+		walk(db.Content.Root.Groups)
+	}
+	return FromFolders(folders), nil
+}
+
+func kdbxItem(entry gokeepasslib.Entry) Item {
+	it := Item{Title: entry.GetTitle()}
+	for _, v := range entry.Values {
+		if v.Key == FieldTitle {
+			continue
+		}
+		it.Fields = append(it.Fields, Field{Name: v.Key, Value: v.Value.Content, Hidden: v.Value.Protected.Bool})
+	}
+	return it
+}
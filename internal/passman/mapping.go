@@ -0,0 +1,325 @@
+// Package passman converts clients to and from the formats of general password
+// managers: KeePass KDBX 4 and Bitwarden unencrypted JSON.
+//
+// Her client bir grup/klasördür (adı firma adı). İçinde bir "Customer" kaydı
+// client alanlarını, VPN ve Jira kayıtları kendi kimlik bilgilerini taşır; her
+// ortam için bir ortam kaydı ile DB, App Server ve Weblogic kayıtları yazılır.
+// IP/URI/TNS gibi alanlar özel alan (custom field) olur. Kayıt türü ve ID'ler
+// "client-man.*" alanlarında saklanır; böylece dışa verilen dosya hiçbir alan
+// kaybetmeden geri okunur. Bu alanları taşımayan (başka bir kaynaktan gelen)
+// kayıtlar ortam olarak içeri alınır.
+package passman
+
+import (
+	"fmt"
+	"strings"
+
+	"clientinfo/internal/model"
+)
+
+// Standard field names shared by KeePass and the login part of Bitwarden items
+const (
+	FieldTitle    = "Title"
+	FieldUserName = "UserName"
+	FieldPassword = "Password"
+	FieldURL      = "URL"
+	FieldNotes    = "Notes"
+)
+
+// Fields that let an export be read back without loss
+const (
+	fieldKind     = "client-man.kind"
+	fieldClientID = "client-man.client_id"
+	fieldAppID    = "client-man.app_id"
+)
+
+// Item kinds
+const (
+	kindCustomer  = "customer"
+	kindVPN       = "vpn"
+	kindJira      = "jira"
+	kindApp       = "environment"
+	kindDB        = "db"
+	kindAppServer = "app_server"
+	kindWeblogic  = "weblogic"
+)
+
+// Folder is one client: a KeePass group or a Bitwarden folder
+type Folder struct {
+	Name  string
+	Items []Item
+}
+
+// Item is one password manager entry; alanlar yazıldıkları sırayla tutulur
+type Item struct {
+	Title  string
+	Fields []Field
+}
+
+// Field is a standard (FieldUserName, ...) or custom entry field
+type Field struct {
+	Name   string
+	Value  string
+	Hidden bool // Şifre gibi gizlenecek alan
+}
+
+// Get returns the value of the field called name, "" if missing
+func (it Item) Get(name string) string {
+	for _, f := range it.Fields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// binding ties a field of an item kind to a client or environment field
+type binding struct {
+	name   string
+	hidden bool
+	// client veya app alanın adresini döner: *string ya da satır satır yazılan *[]string
+	client func(c *model.Client) interface{}
+	app    func(a *model.AppInfo) interface{}
+}
+
+func clientStr(name string, hidden bool, f func(c *model.Client) *string) binding {
+	return binding{name: name, hidden: hidden, client: func(c *model.Client) interface{} { return f(c) }}
+}
+
+func clientList(name string, f func(c *model.Client) *[]string) binding {
+	return binding{name: name, client: func(c *model.Client) interface{} { return f(c) }}
+}
+
+func appStr(name string, hidden bool, f func(a *model.AppInfo) *string) binding {
+	return binding{name: name, hidden: hidden, app: func(a *model.AppInfo) interface{} { return f(a) }}
+}
+
+func appList(name string, hidden bool, f func(a *model.AppInfo) *[]string) binding {
+	return binding{name: name, hidden: hidden, app: func(a *model.AppInfo) interface{} { return f(a) }}
+}
+
+// itemKind describes one kind of item
+type itemKind struct {
+	name     string
+	title    string // Ortam kayıtlarında ortam adının arkasına eklenir
+	always   bool   // Boş olsa da yazılır
+	bindings []binding
+}
+
+var clientKinds = []itemKind{
+	{name: kindCustomer, title: "Customer", always: true, bindings: []binding{
+		clientStr("EBS Version", false, func(c *model.Client) *string { return &c.EBSVersion }),
+		clientStr("Client User", false, func(c *model.Client) *string { return &c.Data.User }),
+		clientStr("Password Reset", false, func(c *model.Client) *string { return &c.Data.PasswordReset }),
+		clientList("RDC", func(c *model.Client) *[]string { return &c.Data.RDC }),
+		clientList("Hosts", func(c *model.Client) *[]string { return &c.Data.Hosts }),
+		clientStr("Data Notes", false, func(c *model.Client) *string { return &c.Data.Notes }),
+		clientStr(FieldNotes, false, func(c *model.Client) *string { return &c.Notes }),
+	}},
+	{name: kindVPN, title: "VPN", bindings: []binding{
+		clientStr(FieldURL, false, func(c *model.Client) *string { return &c.VPN.Host }),
+		clientStr(FieldUserName, false, func(c *model.Client) *string { return &c.VPN.User }),
+		clientStr(FieldPassword, true, func(c *model.Client) *string { return &c.VPN.Password }),
+		clientStr("VPN App", false, func(c *model.Client) *string { return &c.VPN.App }),
+		clientStr("2FA App", false, func(c *model.Client) *string { return &c.VPN.TwoFATokenApp }),
+		clientStr(FieldNotes, false, func(c *model.Client) *string { return &c.VPN.Notes }),
+	}},
+	{name: kindJira, title: "Jira", bindings: []binding{
+		clientStr(FieldURL, false, func(c *model.Client) *string { return &c.Data.JiraURI }),
+		clientStr(FieldUserName, false, func(c *model.Client) *string { return &c.Data.JiraUser }),
+		clientStr(FieldPassword, true, func(c *model.Client) *string { return &c.Data.JiraPassword }),
+	}},
+}
+
+var appKinds = []itemKind{
+	{name: kindApp, always: true, bindings: []binding{
+		appStr("Type", false, func(a *model.AppInfo) *string { return &a.Type }),
+		appStr("Name", false, func(a *model.AppInfo) *string { return &a.Name }),
+		appStr(FieldURL, false, func(a *model.AppInfo) *string { return &a.AppURI }),
+		appList("App Users", true, func(a *model.AppInfo) *[]string { return &a.AppUsers }),
+		appStr(FieldNotes, false, func(a *model.AppInfo) *string { return &a.Notes }),
+	}},
+	{name: kindDB, title: "DB", bindings: []binding{
+		appStr(FieldUserName, false, func(a *model.AppInfo) *string { return &a.User }),
+		appStr(FieldPassword, true, func(a *model.AppInfo) *string { return &a.Password }),
+		appStr("DB Server IP", false, func(a *model.AppInfo) *string { return &a.DBServerIP }),
+		appStr("TNS", false, func(a *model.AppInfo) *string { return &a.TNS }),
+	}},
+	{name: kindAppServer, title: "App Server", bindings: []binding{
+		appStr(FieldURL, false, func(a *model.AppInfo) *string { return &a.AppServerURI }),
+		appStr(FieldUserName, false, func(a *model.AppInfo) *string { return &a.AppServerUser }),
+		appStr(FieldPassword, true, func(a *model.AppInfo) *string { return &a.AppServerPass }),
+		appStr("App Server IP", false, func(a *model.AppInfo) *string { return &a.AppServerIP }),
+		appStr("SSH Params", false, func(a *model.AppInfo) *string { return &a.SSHParams }),
+	}},
+	{name: kindWeblogic, title: "Weblogic", bindings: []binding{
+		appStr(FieldPassword, true, func(a *model.AppInfo) *string { return &a.WeblogicPass }),
+	}},
+}
+
+func (b binding) target(c *model.Client, a *model.AppInfo) interface{} {
+	if b.client != nil {
+		return b.client(c)
+	}
+	return b.app(a)
+}
+
+func (b binding) get(c *model.Client, a *model.AppInfo) string {
+	switch v := b.target(c, a).(type) {
+	case *string:
+		return *v
+	case *[]string:
+		return strings.Join(*v, "\n")
+	}
+	return ""
+}
+
+func (b binding) set(c *model.Client, a *model.AppInfo, value string) {
+	switch v := b.target(c, a).(type) {
+	case *string:
+		*v = value
+	case *[]string:
+		*v = splitLines(value)
+	}
+}
+
+// ToFolders maps clients to password manager folders
+func ToFolders(clients []model.Client) []Folder {
+	folders := make([]Folder, 0, len(clients))
+	for ci := range clients {
+		c := &clients[ci]
+		folder := Folder{Name: c.Company}
+		for _, kind := range clientKinds {
+			if it, ok := kind.item(c, nil, kind.title); ok {
+				it.Fields = append(it.Fields, Field{Name: fieldClientID, Value: c.ID})
+				folder.Items = append(folder.Items, it)
+			}
+		}
+		for ai := range c.Apps {
+			a := &c.Apps[ai]
+			env := strings.TrimSpace(a.Type + " " + a.Name)
+			for _, kind := range appKinds {
+				title := strings.TrimSpace(env + " " + kind.title)
+				if it, ok := kind.item(c, a, title); ok {
+					it.Fields = append(it.Fields, Field{Name: fieldAppID, Value: a.ID})
+					folder.Items = append(folder.Items, it)
+				}
+			}
+		}
+		folders = append(folders, folder)
+	}
+	return folders
+}
+
+// item builds the item of kind, false if it would be empty and is not always written
+func (kind itemKind) item(c *model.Client, a *model.AppInfo, title string) (Item, bool) {
+	it := Item{Title: title, Fields: []Field{{Name: fieldKind, Value: kind.name}}}
+	empty := true
+	for _, b := range kind.bindings {
+		v := b.get(c, a)
+		if v == "" {
+			continue
+		}
+		empty = false
+		it.Fields = append(it.Fields, Field{Name: b.name, Value: v, Hidden: b.hidden})
+	}
+	return it, kind.always || !empty
+}
+
+// FromFolders maps password manager folders back to clients, her klasör bir client
+func FromFolders(folders []Folder) []model.Client {
+	var clients []model.Client
+	for _, folder := range folders {
+		c := model.Client{
+			Company: folder.Name,
+			Data:    model.ClientData{RDC: []string{}, Hosts: []string{}},
+			Apps:    []model.AppInfo{},
+		}
+		for _, it := range folder.Items {
+			readItem(&c, it)
+		}
+		clients = append(clients, c)
+	}
+	return clients
+}
+
+func readItem(c *model.Client, it Item) {
+	kindName := it.Get(fieldKind)
+	for _, kind := range clientKinds {
+		if kind.name != kindName {
+			continue
+		}
+		if id := it.Get(fieldClientID); id != "" {
+			c.ID = id
+		}
+		kind.read(c, nil, it)
+		return
+	}
+	for _, kind := range appKinds {
+		if kind.name != kindName {
+			continue
+		}
+		kind.read(c, appFor(c, it.Get(fieldAppID)), it)
+		return
+	}
+	readForeignItem(c, it)
+}
+
+func (kind itemKind) read(c *model.Client, a *model.AppInfo, it Item) {
+	for _, b := range kind.bindings {
+		if v := it.Get(b.name); v != "" {
+			b.set(c, a, v)
+		}
+	}
+}
+
+// appFor returns the environment with id, creating it when missing.
+// ID'siz ortam kayıtları son ortama aittir.
+func appFor(c *model.Client, id string) *model.AppInfo {
+	if id != "" {
+		if a := c.AppByID(id); a != nil {
+			return a
+		}
+	} else if len(c.Apps) > 0 {
+		return &c.Apps[len(c.Apps)-1]
+	}
+	c.Apps = append(c.Apps, model.AppInfo{ID: id, AppUsers: []string{}})
+	return &c.Apps[len(c.Apps)-1]
+}
+
+// readForeignItem imports an entry that was not written by client-man as an environment:
+// kullanıcı/şifre App Server bilgisi, URL App URI olur; diğer özel alanlar nota eklenir.
+func readForeignItem(c *model.Client, it Item) {
+	a := model.AppInfo{
+		Name:          it.Title,
+		AppServerUser: it.Get(FieldUserName),
+		AppServerPass: it.Get(FieldPassword),
+		AppURI:        it.Get(FieldURL),
+		Notes:         it.Get(FieldNotes),
+		AppUsers:      []string{},
+	}
+	for _, f := range it.Fields {
+		switch f.Name {
+		case FieldTitle, FieldUserName, FieldPassword, FieldURL, FieldNotes:
+			continue
+		}
+		if f.Value == "" {
+			continue
+		}
+		if a.Notes != "" {
+			a.Notes += "\n"
+		}
+		a.Notes += fmt.Sprintf("%s: %s", f.Name, f.Value)
+	}
+	c.Apps = append(c.Apps, a)
+}
+
+func splitLines(v string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(v, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
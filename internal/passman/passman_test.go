@@ -0,0 +1,190 @@
+package passman
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"clientinfo/internal/model"
+	"github.com/tobischo/gokeepasslib/v3"
+)
+
+// fullClient fills every field that is written to a password manager, her alan farklı bir değerle
+func fullClient() model.Client {
+	return model.Client{
+		ID:         "client-1",
+		Company:    "ACME",
+		EBSVersion: "12.2.10",
+		Notes:      "customer notes",
+		VPN: model.VPNInfo{
+			App:           "FortiClient",
+			Host:          "vpn.acme.example",
+			User:          "vpnuser",
+			Password:      "vpn-secret",
+			TwoFATokenApp: "Authenticator",
+			Notes:         "vpn notes",
+		},
+		Data: model.ClientData{
+			JiraURI:       "https://jira.acme.example",
+			JiraUser:      "jirauser",
+			JiraPassword:  "jira-secret",
+			User:          "clientuser",
+			PasswordReset: "call helpdesk",
+			RDC:           []string{"rdc1.acme.example", "rdc2.acme.example"},
+			Hosts:         []string{"10.0.0.1 db01", "10.0.0.2 app01"},
+			Notes:         "data notes",
+		},
+		Apps: []model.AppInfo{
+			{
+				ID:            "app-1",
+				Type:          "PROD",
+				Name:          "EBS",
+				User:          "apps",
+				Password:      "db-secret",
+				DBServerIP:    "10.0.0.1",
+				TNS:           "PROD=(DESCRIPTION=(ADDRESS=(HOST=10.0.0.1)(PORT=1521)))",
+				AppServerIP:   "10.0.0.2",
+				AppServerURI:  "ssh://app01.acme.example",
+				AppServerUser: "applmgr",
+				AppServerPass: "app-secret",
+				WeblogicPass:  "wls-secret",
+				AppURI:        "https://ebs.acme.example/OA_HTML",
+				AppUsers:      []string{"sysadmin/welcome1", "operations/welcome2"},
+				SSHParams:     "-p 2222",
+				Notes:         "prod notes",
+			},
+			{
+				ID:            "app-2",
+				Type:          "TEST",
+				Name:          "EBS",
+				User:          "apps",
+				Password:      "test-db-secret",
+				DBServerIP:    "10.0.1.1",
+				TNS:           "TEST",
+				AppServerIP:   "10.0.1.2",
+				AppServerURI:  "ssh://test01.acme.example",
+				AppServerUser: "applmgr",
+				AppServerPass: "test-app-secret",
+				WeblogicPass:  "test-wls-secret",
+				AppURI:        "https://test.acme.example/OA_HTML",
+				AppUsers:      []string{"sysadmin/welcome3"},
+				SSHParams:     "-o ServerAliveInterval=30",
+				Notes:         "test notes",
+			},
+		},
+	}
+}
+
+// customFields are the fields every environment must carry as custom fields
+var customFields = []string{"DB Server IP", "TNS", "App Server IP"}
+
+func checkRoundTrip(t *testing.T, got []model.Client) {
+	t.Helper()
+	want := fullClient()
+	if len(got) != 1 {
+		t.Fatalf("got %d clients, want 1", len(got))
+	}
+	if !reflect.DeepEqual(got[0].VPN, want.VPN) {
+		t.Errorf("VPN = %+v, want %+v", got[0].VPN, want.VPN)
+	}
+	if !reflect.DeepEqual(got[0].Data, want.Data) {
+		t.Errorf("Data = %+v, want %+v", got[0].Data, want.Data)
+	}
+	if len(got[0].Apps) != len(want.Apps) {
+		t.Fatalf("got %d environments, want %d", len(got[0].Apps), len(want.Apps))
+	}
+	for i := range want.Apps {
+		if !reflect.DeepEqual(got[0].Apps[i], want.Apps[i]) {
+			t.Errorf("environment %d = %+v, want %+v", i, got[0].Apps[i], want.Apps[i])
+		}
+	}
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("client = %+v, want %+v", got[0], want)
+	}
+}
+
+// checkCustomFields fails unless each environment's IP/TNS fields are custom fields of its items
+func checkCustomFields(t *testing.T, custom map[string]int) {
+	t.Helper()
+	for _, name := range customFields {
+		if custom[name] != len(fullClient().Apps) {
+			t.Errorf("custom field %q written %d times, want %d", name, custom[name], len(fullClient().Apps))
+		}
+	}
+}
+
+func TestKDBXRoundTrip(t *testing.T) {
+	data, err := EncodeKDBX([]model.Client{fullClient()}, "export-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeKDBX(data, "export-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, got)
+
+	db := gokeepasslib.NewDatabase()
+	db.Credentials = gokeepasslib.NewPasswordCredentials("export-password")
+	if err := gokeepasslib.NewDecoder(bytes.NewReader(data)).Decode(db); err != nil {
+		t.Fatal(err)
+	}
+	custom := map[string]int{}
+	for _, g := range db.Content.Root.Groups[0].Groups {
+		for _, entry := range g.Entries {
+			for _, v := range entry.Values {
+				custom[v.Key]++
+			}
+		}
+	}
+	checkCustomFields(t, custom)
+}
+
+func TestKDBXWrongPassword(t *testing.T) {
+	data, err := EncodeKDBX([]model.Client{fullClient()}, "export-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecodeKDBX(data, "wrong"); err != ErrWrongKDBXPassword {
+		t.Fatalf("err = %v, want ErrWrongKDBXPassword", err)
+	}
+}
+
+func TestBitwardenRoundTrip(t *testing.T) {
+	data, err := EncodeBitwarden([]model.Client{fullClient()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeBitwarden(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, got)
+
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatal(err)
+	}
+	custom := map[string]int{}
+	uris := map[string]bool{}
+	for _, item := range export.Items {
+		for _, f := range item.Fields {
+			custom[f.Name]++
+		}
+		if item.Login != nil {
+			for _, u := range item.Login.URIs {
+				uris[u.URI] = true
+			}
+		}
+	}
+	checkCustomFields(t, custom)
+	// URI alanları Bitwarden'da login URI'si olur
+	for _, a := range fullClient().Apps {
+		for _, uri := range []string{a.AppURI, a.AppServerURI} {
+			if !uris[uri] {
+				t.Errorf("URI %q not written as a login URI", uri)
+			}
+		}
+	}
+}
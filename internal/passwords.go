@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"clientinfo/internal/passman"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	nativeDialog "github.com/sqweek/dialog"
+)
+
+// exportPasswordManager writes all clients as a KeePass database or a Bitwarden JSON export.
+// KDBX dosyası verilen şifreyle korunur; Bitwarden JSON'u şifresizdir.
+func (s *AppState) exportPasswordManager() {
+	if len(s.clients) == 0 {
+		dialog.ShowInformation(DialogTitleInfo, DialogMsgNoClientsToExport, s.window)
+		return
+	}
+
+	passEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+	passForm := widget.NewForm(
+		widget.NewFormItem("Password:", passEntry),
+		widget.NewFormItem("Confirm:", confirmEntry),
+	)
+	warning := widget.NewLabel(PasswordExportBitwardenWarning)
+	warning.Wrapping = fyne.TextWrapWord
+	warning.Hide()
+
+	format := widget.NewRadioGroup([]string{PasswordFormatKeePass, PasswordFormatBitwarden}, func(selected string) {
+		if selected == PasswordFormatBitwarden {
+			passForm.Hide()
+			warning.Show()
+		} else {
+			warning.Hide()
+			passForm.Show()
+		}
+	})
+	format.Required = true
+	format.SetSelected(PasswordFormatKeePass)
+
+	content := container.NewVBox(widget.NewLabel(PasswordExportInfo), format, passForm, warning)
+	d := dialog.NewCustomConfirm(PasswordExportTitle, ExportSealButton, "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+
+		clients := vault.CloneClients(s.clients)
+		var data []byte
+		var err error
+		ext := passman.ExtKDBX
+		if format.Selected == PasswordFormatBitwarden {
+			ext = passman.ExtBitwarden
+			data, err = passman.EncodeBitwarden(clients)
+		} else {
+			switch {
+			case len([]rune(passEntry.Text)) < ExportPassphraseMinLength:
+				err = fmt.Errorf(PasswordExportTooShort, ExportPassphraseMinLength)
+			case passEntry.Text != confirmEntry.Text:
+				err = errors.New(UnlockMsgMismatch)
+			default:
+				data, err = passman.EncodeKDBX(clients, passEntry.Text)
+			}
+		}
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+
+		filename, err := nativeDialog.File().
+			Title(PasswordExportTitle).
+			Filter(format.Selected, strings.TrimPrefix(ext, ".")).
+			SetStartFile(PasswordExportDefaultName + ext).
+			Save()
+		if err != nil {
+			// Kullanıcı iptal etti
+			return
+		}
+		if filepath.Ext(filename) == "" {
+			filename += ext
+		}
+		if err := store.WriteFile(filename, data, store.VaultPerm); err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		dialog.ShowInformation(DialogTitleSuccess, fmt.Sprintf(DialogMsgPasswordExported, len(clients), filepath.Base(filename)), s.window)
+	}, s.window)
+	d.Resize(fyne.NewSize(480, 0))
+	d.Show()
+}
+
+// importPasswordManager reads a KeePass database or a Bitwarden JSON export.
+// Her grup/klasör bir firma olur; var olan firmalar birleştirme sihirbazında ele alınır.
+func (s *AppState) importPasswordManager() {
+	filename, err := nativeDialog.File().
+		Title(PasswordImportTitle).
+		Filter(PasswordFileFilter, strings.TrimPrefix(passman.ExtKDBX, "."), strings.TrimPrefix(passman.ExtBitwarden, ".")).
+		Load()
+	if err != nil {
+		// Kullanıcı iptal etti
+		return
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf(DialogMsgFileReadError+": %v", err), s.window)
+		return
+	}
+
+	if !strings.EqualFold(filepath.Ext(filename), passman.ExtKDBX) {
+		clients, err := passman.DecodeBitwarden(data)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf(DialogMsgJSONReadError+": %v", err), s.window)
+			return
+		}
+		s.importNext(clients)
+		return
+	}
+
+	passEntry := widget.NewPasswordEntry()
+	items := []*widget.FormItem{
+		{Text: "", Widget: widget.NewLabel(fmt.Sprintf(PasswordImportPrompt, filepath.Base(filename)))},
+		{Text: "Password:", Widget: passEntry},
+	}
+	d := dialog.NewForm(PasswordImportTitle, "Open", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		progress := dialog.NewCustomWithoutButtons(PasswordImportTitle, widget.NewProgressBarInfinite(), s.window)
+		progress.Show()
+
+		// KDBX 4 anahtar türetmesi (Argon2) bilerek yavaştır; UI donmasın diye arka planda
+		go func() {
+			clients, err := passman.DecodeKDBX(data, passEntry.Text)
+			fyne.Do(func() {
+				progress.Hide()
+				if err != nil {
+					dialog.ShowError(err, s.window)
+					return
+				}
+				s.importNext(clients)
+			})
+		}()
+	}, s.window)
+	d.Resize(fyne.NewSize(UnlockFormWidth, 0))
+	d.Show()
+	s.window.Canvas().Focus(passEntry)
+}
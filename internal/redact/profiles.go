@@ -0,0 +1,158 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"clientinfo/internal/model"
+)
+
+const (
+	// ProfileCustomerHandover is the default profile of customer exports
+	ProfileCustomerHandover = "Customer handover"
+	// ProfileInternalAudit leaves every secret out
+	ProfileInternalAudit = "Internal audit"
+	// ProfileFullBackup exports everything
+	ProfileFullBackup = "Full backup"
+
+	suffix = ".profiles.json"
+)
+
+// Builtin returns the profiles every installation has
+func Builtin() []Profile {
+	return []Profile{
+		{
+			Name:        ProfileCustomerHandover,
+			Description: "Everything except the VPN section and the Jira credentials",
+			Rules: []Rule{
+				{Path: "vpn", Action: Drop},
+				{Path: "data.jira_user", Action: Drop},
+				{Path: "data.jira_password", Action: Drop},
+			},
+		},
+		{
+			Name:        ProfileInternalAudit,
+			Description: "Everything except secrets; user/password lines keep only the user",
+			Secrets:     Drop,
+		},
+		{
+			Name:        ProfileFullBackup,
+			Description: "Everything, secrets included",
+		},
+	}
+}
+
+// PathFor returns the custom profile file that belongs to the vault at vaultPath
+func PathFor(vaultPath string) string {
+	return vaultPath + suffix
+}
+
+// Load returns the built-in profiles followed by the custom ones in the profile file of the vault.
+// Dosya yoksa sadece yerleşik profiller döner; aynı isimli özel profil yerleşik olanın yerine geçer.
+//
+// Dosya biçimi:
+//
+//	[{"name": "Vendor", "secrets": "mask", "rules": [{"path": "vpn", "action": "drop"}]}]
+func Load(vaultPath string) ([]Profile, error) {
+	profiles := Builtin()
+	if vaultPath == "" {
+		return profiles, nil
+	}
+	data, err := os.ReadFile(PathFor(vaultPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return profiles, err
+	}
+
+	var custom []Profile
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return profiles, fmt.Errorf("profile file is corrupt: %w", err)
+	}
+	for _, p := range custom {
+		if err := p.Validate(); err != nil {
+			return profiles, err
+		}
+		if i := index(profiles, p.Name); i >= 0 {
+			profiles[i] = p
+			continue
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+// Find returns the profile called name, büyük/küçük harf farkı gözetmeden
+func Find(profiles []Profile, name string) (Profile, bool) {
+	if i := index(profiles, name); i >= 0 {
+		return profiles[i], true
+	}
+	return Profile{}, false
+}
+
+// Names returns the profile names in their order
+func Names(profiles []Profile) []string {
+	names := make([]string, len(profiles))
+	for i, p := range profiles {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Preview describes what an export made with decisions contains, grouped by client.
+// Dahil edilen alanlar tek tek listelenmez; sadece sayıları yazılır.
+func Preview(decisions []Decision) string {
+	type summary struct {
+		included int
+		masked   []string
+		dropped  []string
+	}
+	var order []string
+	byCompany := map[string]*summary{}
+	for _, d := range decisions {
+		s, ok := byCompany[d.Company]
+		if !ok {
+			s = &summary{}
+			byCompany[d.Company] = s
+			order = append(order, d.Company)
+		}
+		switch d.Action {
+		case Mask:
+			s.masked = append(s.masked, d.Path)
+		case Drop:
+			s.dropped = append(s.dropped, d.Path)
+		default:
+			s.included++
+		}
+	}
+
+	var b strings.Builder
+	for _, company := range order {
+		s := byCompany[company]
+		fmt.Fprintf(&b, "%s: %d field(s) included as is\n", company, s.included)
+		writeList(&b, "masked", s.masked)
+		writeList(&b, "left out", s.dropped)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeList(b *strings.Builder, label string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	sort.Slice(paths, func(i, j int) bool { return model.LessPath(paths[i], paths[j]) })
+	fmt.Fprintf(b, "  %s: %s\n", label, strings.Join(paths, ", "))
+}
+
+func index(profiles []Profile, name string) int {
+	for i, p := range profiles {
+		if strings.EqualFold(p.Name, strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,274 @@
+// Package redact applies export profiles: named rules that decide, field by
+// field, what of a client leaves the machine in an export.
+//
+// Kurallar model paketindeki alan yollarıyla yazılır; liste indeksleri yerine
+// "[]" kullanılır ("apps[].pass"). Bir kural yolun kendisine veya altındaki her
+// şeye uygulanır ("vpn" tüm VPN bölümünü kapsar); birden fazla kural uyarsa en
+// uzun (en özel) olan geçerlidir. Kural yoksa gizli alanlara Secrets, diğerlerine
+// Default uygulanır. Bir listeye (örn. "data.hosts") verilen drop kuralı
+// elemanları tamamen çıkarır. "id" ve "company" her zaman dahildir; import
+// eşleşmesi bunlara dayanır.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"clientinfo/internal/model"
+)
+
+// Action decides what happens to a field in an export
+type Action string
+
+const (
+	Include Action = "include" // Değer olduğu gibi
+	Mask    Action = "mask"    // Değer yerine "••••"; userpass satırlarında sadece şifre
+	Drop    Action = "drop"    // Alan boşaltılır, liste elemanı çıkarılır; userpass satırlarında sadece şifre
+)
+
+// MaskValue replaces masked values
+const MaskValue = "••••"
+
+// Rule applies an action to a field path and everything below it
+type Rule struct {
+	Path   string `json:"path"`
+	Action Action `json:"action"`
+}
+
+// Profile is a named set of rules
+type Profile struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     Action `json:"default,omitempty"` // Boşsa Include
+	Secrets     Action `json:"secrets,omitempty"` // Kuralı olmayan gizli alanlar; boşsa Default
+	Rules       []Rule `json:"rules,omitempty"`
+}
+
+// Decision is what a profile did to one non-empty field of one client
+type Decision struct {
+	Company string
+	Path    string
+	Action  Action
+}
+
+// alwaysIncluded are kept whatever the profile says; import eşleşmesi bunlara dayanır
+var alwaysIncluded = map[string]bool{"id": true, "company": true, "apps[].id": true, "apps[].tunnels[].id": true, "bastions[].id": true}
+
+// indexPattern matches list indexes in a field path
+var indexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// Validate reports rules with an unknown action or a path that does not exist in a client
+func (p Profile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("profile without a name")
+	}
+	for _, a := range []Action{p.Default, p.Secrets} {
+		if !validAction(a, true) {
+			return fmt.Errorf("profile %q: unknown action %q", p.Name, a)
+		}
+	}
+	for _, r := range p.Rules {
+		if !validAction(r.Action, false) {
+			return fmt.Errorf("profile %q: unknown action %q for %s", p.Name, r.Action, r.Path)
+		}
+		if !knownPath(r.Path) {
+			return fmt.Errorf("profile %q: unknown field %q", p.Name, r.Path)
+		}
+	}
+	return nil
+}
+
+// Apply returns redacted copies of clients and the decision made for every non-empty field
+func (p Profile) Apply(clients []model.Client) ([]model.Client, []Decision, error) {
+	out := make([]model.Client, 0, len(clients))
+	var decisions []Decision
+	for _, c := range clients {
+		redacted, d, err := p.apply(c)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", c.Company, err)
+		}
+		out = append(out, redacted)
+		decisions = append(decisions, d...)
+	}
+	return out, decisions, nil
+}
+
+func (p Profile) apply(c model.Client) (model.Client, []Decision, error) {
+	out := model.CloneClient(c)
+	values := model.FieldValues(c)
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return model.LessPath(paths[i], paths[j]) })
+
+	var decisions []Decision
+	var removed []string
+	dropped := map[string]bool{}
+	for _, path := range paths {
+		if elem := p.droppedElement(path); elem != "" {
+			// Liste elemanının tamamı çıkarılır; altındaki alanlar tek tek raporlanmaz
+			if !dropped[elem] {
+				dropped[elem] = true
+				removed = append(removed, elem)
+				decisions = append(decisions, Decision{Company: c.Company, Path: elem, Action: Drop})
+			}
+			continue
+		}
+		if values[path] == `""` {
+			continue
+		}
+
+		action := p.actionFor(path)
+		value := values[path]
+		if action != Include {
+			// Şifresiz bir userpass satırı değişmez; dahil sayılır
+			if value = redactValue(path, value, action); value == values[path] {
+				action = Include
+			}
+		}
+		decisions = append(decisions, Decision{Company: c.Company, Path: path, Action: action})
+		if action == Include {
+			continue
+		}
+		if err := model.SetPath(&out, path, value); err != nil {
+			return model.Client{}, nil, err
+		}
+	}
+
+	// Büyük indeksten küçüğe silinir; indeksler kaymaz
+	sort.Slice(removed, func(i, j int) bool { return model.LessPath(removed[j], removed[i]) })
+	for _, elem := range removed {
+		if err := model.DeletePath(&out, elem); err != nil {
+			return model.Client{}, nil, err
+		}
+	}
+	return out, decisions, nil
+}
+
+// actionFor returns the action for a concrete field path
+func (p Profile) actionFor(path string) Action {
+	if alwaysIncluded[pattern(path)] {
+		return Include
+	}
+	if r, ok := p.rule(pattern(path)); ok {
+		return r.Action
+	}
+	if model.SecretKind(path) != "" && p.Secrets != "" {
+		return p.Secrets
+	}
+	if p.Default != "" {
+		return p.Default
+	}
+	return Include
+}
+
+// droppedElement returns the outermost list element containing path that falls under a
+// Drop rule ("apps" veya "data.hosts"), "" if none. userpass satırları çıkarılmaz,
+// sadece şifreleri silinir.
+func (p Profile) droppedElement(path string) string {
+	for i := 0; i < len(path); i++ {
+		if path[i] != ']' {
+			continue
+		}
+		elem := path[:i+1]
+		r, ok := p.rule(pattern(elem))
+		if ok && r.Action == Drop && model.SecretKind(elem) != model.SecretUserPass {
+			return elem
+		}
+	}
+	return ""
+}
+
+// rule returns the most specific rule covering a field pattern
+func (p Profile) rule(pat string) (Rule, bool) {
+	var best Rule
+	found := false
+	for _, r := range p.Rules {
+		if covers(normalize(r.Path), pat) && (!found || len(normalize(r.Path)) > len(normalize(best.Path))) {
+			best = r
+			best.Path = normalize(r.Path)
+			found = true
+		}
+	}
+	return best, found
+}
+
+// covers reports whether rule path applies to pat: aynı yol veya altındaki bir yol
+func covers(rule, pat string) bool {
+	return pat == rule || strings.HasPrefix(pat, rule+".") || strings.HasPrefix(pat, rule+"[")
+}
+
+// redactValue returns the JSON value a masked or dropped string field gets
+func redactValue(path, raw string, action Action) string {
+	if model.SecretKind(path) == model.SecretUserPass {
+		var line string
+		if err := json.Unmarshal([]byte(raw), &line); err == nil {
+			user, _, ok := model.SplitUserPass(line)
+			if !ok {
+				user = line
+			}
+			if action == Mask && ok {
+				return quote(user + "/" + MaskValue)
+			}
+			if action == Mask {
+				return quote(MaskValue)
+			}
+			return quote(user)
+		}
+	}
+	if action == Mask {
+		return quote(MaskValue)
+	}
+	return `""`
+}
+
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// pattern replaces list indexes with "[]"
+func pattern(path string) string {
+	return indexPattern.ReplaceAllString(path, "[]")
+}
+
+// normalize lets rules be written with or without the "[]" of a list ("apps" = "apps[]")
+func normalize(path string) string {
+	return strings.TrimSuffix(pattern(strings.TrimSpace(path)), "[]")
+}
+
+func validAction(a Action, allowEmpty bool) bool {
+	switch a {
+	case Include, Mask, Drop:
+		return true
+	case "":
+		return allowEmpty
+	}
+	return false
+}
+
+// sample has one element in every list so that all field patterns appear in FieldValues
+var sample = model.Client{
+	ID:       "-",
+	Data:     model.ClientData{RDC: []string{""}, Hosts: []string{""}},
+	Apps:     []model.AppInfo{{ID: "-", AppUsers: []string{""}, HostKeys: []string{""}, Tunnels: []model.Tunnel{{ID: "-"}}}},
+	Bastions: []model.Bastion{{ID: "-", HostKeys: []string{""}}},
+}
+
+// knownPath reports whether a rule path names a field of a client
+func knownPath(path string) bool {
+	rule := normalize(path)
+	if rule == "" {
+		return false
+	}
+	for field := range model.FieldValues(sample) {
+		if covers(rule, pattern(field)) {
+			return true
+		}
+	}
+	return false
+}
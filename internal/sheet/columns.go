@@ -0,0 +1,166 @@
+// Package sheet flattens clients into spreadsheet rows and reads them back.
+//
+// Her ortam (AppInfo) bir satırdır ve üst client'ın sütunlarını taşır; ortamı
+// olmayan bir client tek satırla yazılır. Liste alanları (RDC, hosts, app users)
+// hücre içinde satır satır yazılır. Gizli sütunlar sadece istenirse yazılır.
+package sheet
+
+import (
+	"strings"
+
+	"clientinfo/internal/model"
+)
+
+// Column is one spreadsheet column bound to a client or environment field
+type Column struct {
+	Key    string // Alan yolu, redact kurallarıyla aynı yazım: "vpn.host", "apps[].pass"
+	Header string
+	Secret bool
+	App    bool // Ortam sütunu; false ise client sütunu
+
+	get func(c *model.Client, a *model.AppInfo) string
+	set func(c *model.Client, a *model.AppInfo, v string)
+}
+
+func clientField(key, header string, secret bool, field func(c *model.Client) *string) Column {
+	return Column{
+		Key: key, Header: header, Secret: secret,
+		get: func(c *model.Client, _ *model.AppInfo) string { return *field(c) },
+		set: func(c *model.Client, _ *model.AppInfo, v string) { *field(c) = v },
+	}
+}
+
+func clientList(key, header string, field func(c *model.Client) *[]string) Column {
+	return Column{
+		Key: key, Header: header,
+		get: func(c *model.Client, _ *model.AppInfo) string { return joinLines(*field(c)) },
+		set: func(c *model.Client, _ *model.AppInfo, v string) { *field(c) = splitLines(v) },
+	}
+}
+
+func appField(key, header string, secret bool, field func(a *model.AppInfo) *string) Column {
+	return Column{
+		Key: key, Header: header, Secret: secret, App: true,
+		get: func(_ *model.Client, a *model.AppInfo) string { return *field(a) },
+		set: func(_ *model.Client, a *model.AppInfo, v string) { *field(a) = v },
+	}
+}
+
+// Columns lists every column in export order
+var Columns = []Column{
+	clientField("id", "Client ID", false, func(c *model.Client) *string { return &c.ID }),
+	clientField("company", "Company", false, func(c *model.Client) *string { return &c.Company }),
+	clientField("ebs_version", "EBS Version", false, func(c *model.Client) *string { return &c.EBSVersion }),
+	clientField("vpn.app", "VPN App", false, func(c *model.Client) *string { return &c.VPN.App }),
+	clientField("vpn.host", "VPN Host", false, func(c *model.Client) *string { return &c.VPN.Host }),
+	clientField("vpn.user", "VPN User", false, func(c *model.Client) *string { return &c.VPN.User }),
+	clientField("vpn.password", "VPN Password", true, func(c *model.Client) *string { return &c.VPN.Password }),
+	clientField("vpn.two_fa_token_app", "VPN 2FA App", false, func(c *model.Client) *string { return &c.VPN.TwoFATokenApp }),
+	clientField("vpn.not", "VPN Notes", false, func(c *model.Client) *string { return &c.VPN.Notes }),
+	clientField("data.jira_uri", "Jira URI", false, func(c *model.Client) *string { return &c.Data.JiraURI }),
+	clientField("data.jira_user", "Jira User", false, func(c *model.Client) *string { return &c.Data.JiraUser }),
+	clientField("data.jira_password", "Jira Password", true, func(c *model.Client) *string { return &c.Data.JiraPassword }),
+	clientField("data.user", "Client User", false, func(c *model.Client) *string { return &c.Data.User }),
+	clientField("data.pass_reset", "Password Reset", false, func(c *model.Client) *string { return &c.Data.PasswordReset }),
+	clientList("data.rdc", "RDC", func(c *model.Client) *[]string { return &c.Data.RDC }),
+	clientList("data.hosts", "Hosts", func(c *model.Client) *[]string { return &c.Data.Hosts }),
+	clientField("data.not", "Client Data Notes", false, func(c *model.Client) *string { return &c.Data.Notes }),
+	clientField("not", "Client Notes", false, func(c *model.Client) *string { return &c.Notes }),
+
+	appField("apps[].id", "Environment ID", false, func(a *model.AppInfo) *string { return &a.ID }),
+	appField("apps[].type", "Environment Type", false, func(a *model.AppInfo) *string { return &a.Type }),
+	appField("apps[].name", "Environment", false, func(a *model.AppInfo) *string { return &a.Name }),
+	appField("apps[].user", "DB User", false, func(a *model.AppInfo) *string { return &a.User }),
+	appField("apps[].pass", "DB Password", true, func(a *model.AppInfo) *string { return &a.Password }),
+	appField("apps[].db_server_ip", "DB Server IP", false, func(a *model.AppInfo) *string { return &a.DBServerIP }),
+	appField("apps[].tns", "TNS", false, func(a *model.AppInfo) *string { return &a.TNS }),
+	appField("apps[].app_server_ip", "App Server IP", false, func(a *model.AppInfo) *string { return &a.AppServerIP }),
+	appField("apps[].app_server_uri", "App Server URI", false, func(a *model.AppInfo) *string { return &a.AppServerURI }),
+	appField("apps[].app_server_user", "App Server User", false, func(a *model.AppInfo) *string { return &a.AppServerUser }),
+	appField("apps[].app_server_pass", "App Server Password", true, func(a *model.AppInfo) *string { return &a.AppServerPass }),
+	appField("apps[].weblogic_pass", "Weblogic Password", true, func(a *model.AppInfo) *string { return &a.WeblogicPass }),
+	appField("apps[].app_uri", "App URI", false, func(a *model.AppInfo) *string { return &a.AppURI }),
+	appUsersColumn,
+	appField("apps[].ssh_params", "SSH Params", false, func(a *model.AppInfo) *string { return &a.SSHParams }),
+	appField("apps[].not", "Environment Notes", false, func(a *model.AppInfo) *string { return &a.Notes }),
+}
+
+// appUsersColumn holds "user/password" lines; gizli sütunlar yazılmazsa sadece kullanıcılar kalır
+var appUsersColumn = Column{
+	Key: "apps[].app_users", Header: "App Users", App: true,
+	get: func(_ *model.Client, a *model.AppInfo) string { return joinLines(a.AppUsers) },
+	set: func(_ *model.Client, a *model.AppInfo, v string) { a.AppUsers = splitLines(v) },
+}
+
+// ColumnByKey returns the column with key, false if there is none
+func ColumnByKey(key string) (Column, bool) {
+	for _, col := range Columns {
+		if col.Key == key {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// Rows flattens clients into a header row followed by one row per environment
+func Rows(clients []model.Client, withSecrets bool) [][]string {
+	var cols []Column
+	for _, col := range Columns {
+		if !col.Secret || withSecrets {
+			cols = append(cols, col)
+		}
+	}
+
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = col.Header
+	}
+	rows := [][]string{header}
+
+	for ci := range clients {
+		c := &clients[ci]
+		apps := c.Apps
+		if len(apps) == 0 {
+			apps = []model.AppInfo{{}}
+		}
+		for ai := range apps {
+			a := &apps[ai]
+			row := make([]string, len(cols))
+			for i, col := range cols {
+				row[i] = col.get(c, a)
+				if col.Key == appUsersColumn.Key && !withSecrets {
+					row[i] = joinLines(usersOnly(a.AppUsers))
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// usersOnly drops the password half of "user/password" lines
+func usersOnly(lines []string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if user, _, ok := model.SplitUserPass(line); ok {
+			line = user
+		}
+		out[i] = line
+	}
+	return out
+}
+
+func joinLines(lines []string) string {
+	return strings.Join(lines, "\n")
+}
+
+// splitLines splits a cell into lines, boş satırlar atılır
+func splitLines(v string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(v, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
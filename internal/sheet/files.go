@@ -0,0 +1,79 @@
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"clientinfo/internal/store"
+)
+
+// Supported file extensions
+const (
+	ExtCSV  = ".csv"
+	ExtXLSX = ".xlsx"
+)
+
+// utf8BOM lets Excel open UTF-8 CSV files with Turkish characters intact
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// WriteFile writes rows as CSV or XLSX, chosen by the extension of filename
+func WriteFile(filename string, rows [][]string) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ExtCSV:
+		data, err = encodeCSV(rows)
+	case ExtXLSX:
+		data, err = encodeXLSX(rows)
+	default:
+		return fmt.Errorf("unsupported spreadsheet type %q (use %s or %s)", filepath.Ext(filename), ExtCSV, ExtXLSX)
+	}
+	if err != nil {
+		return err
+	}
+	return store.WriteFile(filename, data, store.VaultPerm)
+}
+
+// ReadFile reads the rows of a CSV file or of the first sheet of an XLSX file
+func ReadFile(filename string) ([][]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ExtCSV:
+		return decodeCSV(data)
+	case ExtXLSX:
+		return decodeXLSX(data)
+	}
+	return nil, fmt.Errorf("unsupported spreadsheet type %q (use %s or %s)", filepath.Ext(filename), ExtCSV, ExtXLSX)
+}
+
+func encodeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(utf8BOM)
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeCSV accepts comma or semicolon separated files (Excel bölgesel ayara göre ; kullanır)
+func decodeCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("the CSV file is not UTF-8 encoded")
+	}
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		r.Comma = ';'
+	}
+	return r.ReadAll()
+}
@@ -0,0 +1,197 @@
+package sheet
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"clientinfo/internal/model"
+)
+
+// ErrNoCompany is returned when no column is mapped to the company
+var ErrNoCompany = errors.New("a column must be mapped to Company")
+
+// RowError is a validation error of one spreadsheet row; satır reddedilir, diğerleri okunur
+type RowError struct {
+	Row int // 1'den başlayan satır numarası, başlık satırı 1'dir
+	Msg string
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Msg)
+}
+
+// hostPattern accepts host names with an optional port ("db01.acme.local:1521")
+var hostPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?(:\d{1,5})?$`)
+
+// hostColumns are validated as IP addresses or host names
+var hostColumns = map[string]bool{"apps[].db_server_ip": true, "apps[].app_server_ip": true}
+
+// GuessMapping maps each header cell to the column with the same header or key, "" if none.
+// Büyük/küçük harf ve baştaki/sondaki boşluklar önemsizdir; her sütun en fazla bir kez eşlenir.
+func GuessMapping(header []string) []string {
+	mapping := make([]string, len(header))
+	used := map[string]bool{}
+	for i, cell := range header {
+		cell = strings.TrimSpace(cell)
+		for _, col := range Columns {
+			if used[col.Key] {
+				continue
+			}
+			if strings.EqualFold(cell, col.Header) || strings.EqualFold(cell, col.Key) {
+				mapping[i] = col.Key
+				used[col.Key] = true
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+// Parse builds clients from the data rows (rows[1:]) using mapping, the column key of each
+// source column ("" = yok sayılır). Aynı Client ID'li veya aynı firma adlı satırlar tek bir
+// client'ın ortamlarıdır; client sütunları bu satırlar arasında çelişemez.
+func Parse(rows [][]string, mapping []string) ([]model.Client, []RowError, error) {
+	cols := make([]*Column, len(mapping))
+	hasCompany := false
+	for i, key := range mapping {
+		if key == "" {
+			continue
+		}
+		col, ok := ColumnByKey(key)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown column %q", key)
+		}
+		cols[i] = &col
+		hasCompany = hasCompany || key == "company"
+	}
+	if !hasCompany {
+		return nil, nil, ErrNoCompany
+	}
+
+	var clients []model.Client
+	var rowErrors []RowError
+	firstRow := map[int]int{} // client indeksi → ilk satırı
+	for r := 1; r < len(rows); r++ {
+		rowNum := r + 1
+		c, app, hasApp, err := parseRow(rows[r], cols)
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Row: rowNum, Msg: err.Error()})
+			continue
+		}
+		if c == nil {
+			continue // Boş satır
+		}
+
+		idx := findClient(clients, *c)
+		if idx < 0 {
+			c.Apps = []model.AppInfo{}
+			if c.Data.RDC == nil {
+				c.Data.RDC = []string{}
+			}
+			if c.Data.Hosts == nil {
+				c.Data.Hosts = []string{}
+			}
+			clients = append(clients, *c)
+			idx = len(clients) - 1
+			firstRow[idx] = rowNum
+		} else if err := mergeClientColumns(&clients[idx], *c, cols, firstRow[idx]); err != nil {
+			rowErrors = append(rowErrors, RowError{Row: rowNum, Msg: err.Error()})
+			continue
+		}
+
+		if !hasApp {
+			continue
+		}
+		if dup := findApp(clients[idx].Apps, app); dup >= 0 {
+			rowErrors = append(rowErrors, RowError{Row: rowNum, Msg: fmt.Sprintf("environment %s appears twice for %s", strings.TrimSpace(app.Type+" "+app.Name), c.Company)})
+			continue
+		}
+		clients[idx].Apps = append(clients[idx].Apps, app)
+	}
+	return clients, rowErrors, nil
+}
+
+// parseRow reads one row; c nil ise satır tamamen boştur
+func parseRow(row []string, cols []*Column) (c *model.Client, app model.AppInfo, hasApp bool, err error) {
+	var client model.Client
+	empty := true
+	for i, col := range cols {
+		if col == nil || i >= len(row) {
+			continue
+		}
+		v := strings.TrimSpace(row[i])
+		if v == "" {
+			continue
+		}
+		empty = false
+		if hostColumns[col.Key] && net.ParseIP(v) == nil && !hostPattern.MatchString(v) {
+			return nil, app, false, fmt.Errorf("%s %q is not an IP address or host name", col.Header, v)
+		}
+		col.set(&client, &app, v)
+		hasApp = hasApp || col.App
+	}
+	if empty {
+		return nil, app, false, nil
+	}
+	if strings.TrimSpace(client.Company) == "" {
+		return nil, app, false, errors.New("no Company given")
+	}
+	if hasApp && app.Name == "" {
+		return nil, app, false, errors.New("no Environment given")
+	}
+	if app.AppUsers == nil {
+		app.AppUsers = []string{}
+	}
+	return &client, app, hasApp, nil
+}
+
+// findClient returns the client c belongs to: aynı ID, yoksa aynı firma adı
+func findClient(clients []model.Client, c model.Client) int {
+	if c.ID != "" {
+		return model.IndexByID(clients, c.ID)
+	}
+	for i := range clients {
+		if strings.EqualFold(clients[i].Company, c.Company) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeClientColumns fills the empty client columns of dst from src and rejects differing values
+func mergeClientColumns(dst *model.Client, src model.Client, cols []*Column, firstRow int) error {
+	for _, col := range cols {
+		if col == nil || col.App {
+			continue
+		}
+		have, got := col.get(dst, nil), col.get(&src, nil)
+		if got == "" || got == have {
+			continue
+		}
+		if have != "" {
+			return fmt.Errorf("%s differs from row %d", col.Header, firstRow)
+		}
+	}
+	for _, col := range cols {
+		if col != nil && !col.App && col.get(dst, nil) == "" {
+			col.set(dst, nil, col.get(&src, nil))
+		}
+	}
+	return nil
+}
+
+// findApp returns the environment with the same ID, or the same type and name
+func findApp(apps []model.AppInfo, a model.AppInfo) int {
+	for i, existing := range apps {
+		if a.ID != "" && existing.ID == a.ID {
+			return i
+		}
+		if strings.EqualFold(existing.Type, a.Type) && strings.EqualFold(existing.Name, a.Name) {
+			return i
+		}
+	}
+	return -1
+}
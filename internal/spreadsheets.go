@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"clientinfo/internal/sheet"
+	"clientinfo/internal/vault"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	nativeDialog "github.com/sqweek/dialog"
+)
+
+// exportSpreadsheet writes all clients as a CSV or XLSX environment inventory.
+// Dosya şifrelenmez; gizli sütunlar sadece kullanıcı açıkça isterse yazılır.
+func (s *AppState) exportSpreadsheet() {
+	if len(s.clients) == 0 {
+		dialog.ShowInformation(DialogTitleInfo, DialogMsgNoClientsToExport, s.window)
+		return
+	}
+
+	info := widget.NewLabel(SpreadsheetExportInfo)
+	info.Wrapping = fyne.TextWrapWord
+	secrets := widget.NewCheck(SpreadsheetExportSecrets, nil)
+
+	d := dialog.NewCustomConfirm(SpreadsheetExportTitle, ExportSealButton, "Cancel", container.NewVBox(info, secrets), func(ok bool) {
+		if !ok {
+			return
+		}
+		filename, err := nativeDialog.File().
+			Title(SpreadsheetExportTitle).
+			Filter(SpreadsheetFileFilter, strings.TrimPrefix(sheet.ExtXLSX, "."), strings.TrimPrefix(sheet.ExtCSV, ".")).
+			SetStartFile(SpreadsheetDefaultName + sheet.ExtXLSX).
+			Save()
+		if err != nil {
+			// Kullanıcı iptal etti
+			return
+		}
+		if filepath.Ext(filename) == "" {
+			filename += sheet.ExtXLSX
+		}
+
+		rows := sheet.Rows(vault.CloneClients(s.clients), secrets.Checked)
+		if err := sheet.WriteFile(filename, rows); err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		dialog.ShowInformation(DialogTitleSuccess, fmt.Sprintf(DialogMsgSpreadsheetExported, len(rows)-1, filepath.Base(filename)), s.window)
+	}, s.window)
+	d.Resize(fyne.NewSize(480, 0))
+	d.Show()
+}
+
+// importSpreadsheet reads a CSV or XLSX inventory and asks how its columns map to client fields
+func (s *AppState) importSpreadsheet() {
+	filename, err := nativeDialog.File().
+		Title(SpreadsheetImportTitle).
+		Filter(SpreadsheetFileFilter, strings.TrimPrefix(sheet.ExtXLSX, "."), strings.TrimPrefix(sheet.ExtCSV, ".")).
+		Load()
+	if err != nil {
+		// Kullanıcı iptal etti
+		return
+	}
+
+	rows, err := sheet.ReadFile(filename)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf(DialogMsgFileReadError+": %v", err), s.window)
+		return
+	}
+	if len(rows) < 2 {
+		dialog.ShowInformation(DialogTitleInfo, DialogMsgSpreadsheetEmpty, s.window)
+		return
+	}
+	s.showColumnMapping(rows)
+}
+
+// showColumnMapping lets the user pick the client field of every spreadsheet column.
+// Başlığı bilinen sütunlar önceden eşlenir; "(ignore)" seçilen sütunlar okunmaz.
+func (s *AppState) showColumnMapping(rows [][]string) {
+	options := []string{SpreadsheetIgnoreColumn}
+	keyOf := map[string]string{SpreadsheetIgnoreColumn: ""}
+	headerOf := map[string]string{}
+	for _, col := range sheet.Columns {
+		options = append(options, col.Header)
+		keyOf[col.Header] = col.Key
+		headerOf[col.Key] = col.Header
+	}
+
+	header := rows[0]
+	guessed := sheet.GuessMapping(header)
+	selects := make([]*widget.Select, len(header))
+	form := widget.NewForm()
+	for i, cell := range header {
+		sel := widget.NewSelect(options, nil)
+		if guessed[i] != "" {
+			sel.SetSelected(headerOf[guessed[i]])
+		} else {
+			sel.SetSelected(SpreadsheetIgnoreColumn)
+		}
+		selects[i] = sel
+		title := strings.TrimSpace(cell)
+		if title == "" {
+			title = fmt.Sprintf(SpreadsheetUnnamedColumn, i+1)
+		}
+		form.Append(title, sel)
+	}
+
+	info := widget.NewLabel(fmt.Sprintf(SpreadsheetMappingInfo, len(rows)-1))
+	info.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(form)
+	scroll.SetMinSize(fyne.NewSize(520, 360))
+
+	d := dialog.NewCustomConfirm(SpreadsheetMappingTitle, SpreadsheetMappingNext, "Cancel", container.NewBorder(info, nil, nil, nil, scroll), func(ok bool) {
+		if !ok {
+			return
+		}
+		mapping := make([]string, len(selects))
+		for i, sel := range selects {
+			mapping[i] = keyOf[sel.Selected]
+		}
+		clients, rowErrors, err := sheet.Parse(rows, mapping)
+		if err != nil {
+			if errors.Is(err, sheet.ErrNoCompany) {
+				err = errors.New(DialogMsgSpreadsheetNoCompany)
+			}
+			dialog.ShowError(err, s.window)
+			return
+		}
+		s.showSpreadsheetImportReview(clients, rowErrors)
+	}, s.window)
+	d.Show()
+}
+
+// showSpreadsheetImportReview lists rejected rows and customers that already exist before importing.
+// Var olan firmalar birleştirme sihirbazında alan alan karşılaştırılır veya atlanır.
+func (s *AppState) showSpreadsheetImportReview(clients []Client, rowErrors []sheet.RowError) {
+	environments := 0
+	var existing []string
+	for _, c := range clients {
+		environments += len(c.Apps)
+		if i := s.importMatch(c); i >= 0 {
+			existing = append(existing, s.clients[i].Company)
+		}
+	}
+
+	content := container.NewVBox(widget.NewLabel(fmt.Sprintf(SpreadsheetReviewSummary, len(clients), environments)))
+	if len(rowErrors) > 0 {
+		lines := make([]string, len(rowErrors))
+		for i, e := range rowErrors {
+			lines[i] = e.Error()
+		}
+		errorsLabel := widget.NewLabel(strings.Join(lines, "\n"))
+		errorsLabel.Wrapping = fyne.TextWrapWord
+		errorsScroll := container.NewVScroll(errorsLabel)
+		errorsScroll.SetMinSize(fyne.NewSize(520, 160))
+		content.Add(widget.NewLabelWithStyle(fmt.Sprintf(SpreadsheetReviewRejected, len(rowErrors)), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		content.Add(errorsScroll)
+	}
+
+	skipExisting := widget.NewCheck(SpreadsheetReviewSkipExisting, nil)
+	if len(existing) > 0 {
+		dupLabel := widget.NewLabel(fmt.Sprintf(SpreadsheetReviewExisting, strings.Join(existing, ", ")))
+		dupLabel.Wrapping = fyne.TextWrapWord
+		content.Add(dupLabel)
+		content.Add(skipExisting)
+	}
+
+	if len(clients) == 0 {
+		dialog.ShowCustom(SpreadsheetImportTitle, "Close", content, s.window)
+		return
+	}
+	d := dialog.NewCustomConfirm(SpreadsheetImportTitle, SpreadsheetReviewImport, "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		queue := clients
+		if skipExisting.Checked {
+			queue = nil
+			for _, c := range clients {
+				if s.importMatch(c) < 0 {
+					queue = append(queue, c)
+				}
+			}
+		}
+		s.importNext(queue)
+	}, s.window)
+	d.Resize(fyne.NewSize(560, 0))
+	d.Show()
+}
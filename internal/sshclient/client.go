@@ -0,0 +1,311 @@
+// Package sshclient opens SSH connections and interactive shells with golang.org/x/crypto/ssh.
+// Harici ssh istemcisine ve panoya şifre kopyalamaya gerek kalmadan uygulama içinden bağlanır.
+package sshclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// DefaultPort is the SSH port used when the target names none
+const DefaultPort = 22
+
+// DialTimeout limits the TCP connect and the SSH handshake
+const DialTimeout = 15 * time.Second
+
+// KeepAliveInterval is how often idle connections are probed; VPN ve NAT bağlantıyı düşürmesin
+const KeepAliveInterval = 30 * time.Second
+
+// TermType is the terminal type announced to the server
+const TermType = "xterm-256color"
+
+// defaultKeyFiles are tried in ~/.ssh when no identity file is given (OpenSSH sırası)
+var defaultKeyFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+// Target describes how to reach and log in to a server
+type Target struct {
+	Host     string // IP veya ad; "host:port" de kabul edilir
+	Port     int    // 0 ise DefaultPort
+	User     string
+	Password string   // Parola ve keyboard-interactive girişte kullanılır
+	KeyFiles []string // Şifresiz özel anahtarlar; boşsa ~/.ssh altındaki varsayılanlar
+	Ciphers  []string // Boşsa x/crypto/ssh varsayılanları, bkz. Params.CipherList()
+
+	// HostKeyCallback verifies the server's host key; boş bırakılamaz
+	HostKeyCallback ssh.HostKeyCallback
+	// HostKeyAlgorithms restricts the offered host key types, bkz. HostKeyAlgorithms()
+	HostKeyAlgorithms []string
+
+	// Jumps are the bastions to connect through, ilk atlanan başta. Her biri kendi kimlik
+	// bilgisi ve host key kontrolüyle girilir; kendi Jumps alanları kullanılmaz.
+	Jumps []Target
+}
+
+// Addr returns host:port of the target
+func (t Target) Addr() string {
+	host := strings.TrimSpace(t.Host)
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		return net.JoinHostPort(h, p)
+	}
+	port := t.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port))
+}
+
+// String returns user@host:port for messages
+func (t Target) String() string {
+	return t.User + "@" + t.Addr()
+}
+
+// Dial connects to the target, through its jumps if any, and authenticates.
+// Sırasıyla parola, keyboard-interactive (parola ile) ve açık anahtarlar (ssh-agent, anahtar dosyaları) denenir.
+// Dönen client kapanınca ara bağlantılar da kapanır.
+func Dial(t Target) (*ssh.Client, error) {
+	var via *ssh.Client
+	for _, hop := range t.Jumps {
+		client, err := dialVia(via, hop)
+		if err != nil {
+			if via != nil {
+				via.Close()
+			}
+			return nil, err
+		}
+		via = client
+	}
+	client, err := dialVia(via, t)
+	if err != nil && via != nil {
+		via.Close()
+	}
+	return client, err
+}
+
+// dialVia connects to t directly, or through via when it is not nil
+func dialVia(via *ssh.Client, t Target) (*ssh.Client, error) {
+	if t.HostKeyCallback == nil {
+		return nil, errors.New("no host key callback given")
+	}
+	if strings.TrimSpace(t.Host) == "" || strings.TrimSpace(t.User) == "" {
+		return nil, errors.New("server and user are required")
+	}
+
+	auth, closeAgent := authMethods(t)
+	defer closeAgent()
+
+	config := &ssh.ClientConfig{
+		User:              t.User,
+		Auth:              auth,
+		HostKeyCallback:   t.HostKeyCallback,
+		HostKeyAlgorithms: t.HostKeyAlgorithms,
+		Timeout:           DialTimeout,
+	}
+	config.Ciphers = t.Ciphers
+
+	if via == nil {
+		client, err := ssh.Dial("tcp", t.Addr(), config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t, err)
+		}
+		go keepAlive(client)
+		return client, nil
+	}
+
+	conn, err := via.Dial("tcp", t.Addr())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t, err)
+	}
+	// Tünel içindeki bağlantının zaman aşımı yok; el sıkışma uzarsa bağlantı kapatılır
+	timer := time.AfterFunc(DialTimeout, func() { conn.Close() })
+	c, chans, reqs, err := ssh.NewClientConn(conn, t.Addr(), config)
+	timer.Stop()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%s: %w", t, err)
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	go func() {
+		client.Wait()
+		via.Close()
+	}()
+	go keepAlive(client)
+	return client, nil
+}
+
+// authMethods builds the auth methods of t; dönen fonksiyon ssh-agent bağlantısını kapatır
+func authMethods(t Target) ([]ssh.AuthMethod, func()) {
+	var methods []ssh.AuthMethod
+	closeAgent := func() {}
+
+	if t.Password != "" {
+		password := t.Password
+		methods = append(methods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+				// Tek soru parola sorusudur; başka soru (OTP vb.) cevaplanamaz
+				answers := make([]string, len(questions))
+				for i := range questions {
+					if !echos[i] {
+						answers[i] = password
+					}
+				}
+				return answers, nil
+			}),
+		)
+	}
+
+	var signers []ssh.Signer
+	signers = append(signers, keyFileSigners(t.KeyFiles)...)
+	var agentClient agent.ExtendedAgent
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			agentClient = agent.NewClient(conn)
+			closeAgent = func() { conn.Close() }
+		}
+	}
+	if len(signers) > 0 || agentClient != nil {
+		methods = append(methods, ssh.PublicKeysCallback(func() ([]ssh.Signer, error) {
+			if agentClient == nil {
+				return signers, nil
+			}
+			fromAgent, err := agentClient.Signers()
+			if err != nil {
+				return signers, nil
+			}
+			return append(signers, fromAgent...), nil
+		}))
+	}
+	return methods, closeAgent
+}
+
+// keyFileSigners loads the unencrypted private keys among files (boşsa varsayılan dosyalar).
+// Okunamayan veya parola korumalı anahtarlar atlanır; onlar için ssh-agent kullanılmalı.
+func keyFileSigners(files []string) []ssh.Signer {
+	if len(files) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		for _, name := range defaultKeyFiles {
+			files = append(files, filepath.Join(home, ".ssh", name))
+		}
+	}
+
+	var signers []ssh.Signer
+	for _, file := range files {
+		data, err := os.ReadFile(expandHome(file))
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	return signers
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// keepAlive probes the server until the connection is closed
+func keepAlive(client *ssh.Client) {
+	ticker := time.NewTicker(KeepAliveInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+			return
+		}
+	}
+}
+
+// Shell is an interactive login shell on a PTY.
+// Read uzak tarafın çıktısını (stdout ve stderr birlikte) verir; Write klavye girdisini gönderir.
+type Shell struct {
+	client  *ssh.Client
+	session *ssh.Session
+	stdin   io.WriteCloser
+	output  *io.PipeReader
+}
+
+// OpenShell requests a PTY of rows x cols and starts the login shell.
+// Shell kapatıldığında client da kapatılır.
+func OpenShell(client *ssh.Client, rows, cols int) (*Shell, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+	modes := ssh.TerminalModes{
+		ssh.ECHO:          1,
+		ssh.TTY_OP_ISPEED: 38400,
+		ssh.TTY_OP_OSPEED: 38400,
+	}
+	if err := session.RequestPty(TermType, rows, cols, modes); err != nil {
+		session.Close()
+		return nil, fmt.Errorf("PTY: %w", err)
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		session.Close()
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	session.Stdout = pw
+	session.Stderr = pw
+	if err := session.Shell(); err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	go func() {
+		// Oturum bitince okuyucu EOF (veya çıkış hatası) alır
+		err := session.Wait()
+		var exitErr *ssh.ExitError
+		if err == nil || errors.As(err, &exitErr) {
+			err = io.EOF
+		}
+		pw.CloseWithError(err)
+	}()
+	return &Shell{client: client, session: session, stdin: stdin, output: pr}, nil
+}
+
+// Read reads output of the shell; oturum bitince io.EOF döner
+func (sh *Shell) Read(p []byte) (int, error) {
+	return sh.output.Read(p)
+}
+
+// Write sends input to the shell
+func (sh *Shell) Write(p []byte) (int, error) {
+	return sh.stdin.Write(p)
+}
+
+// Resize tells the server the new size of the terminal
+func (sh *Shell) Resize(rows, cols int) error {
+	return sh.session.WindowChange(rows, cols)
+}
+
+// Close ends the session and the connection
+func (sh *Shell) Close() error {
+	sh.session.Close()
+	return sh.client.Close()
+}
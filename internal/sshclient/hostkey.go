@@ -0,0 +1,249 @@
+package sshclient
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// ErrHostKeyChanged is returned when a server offers another key than the trusted one
+var ErrHostKeyChanged = errors.New("the server's host key has CHANGED; the connection was refused")
+
+// HostKeyStatus is the result of checking an offered host key
+type HostKeyStatus int
+
+const (
+	HostKeyTrusted HostKeyStatus = iota // Güvenilen anahtarlardan biri
+	HostKeyNew                          // Bu adres için güvenilen anahtar yok
+	HostKeyChanged                      // Adresin anahtarı var ama sunulan farklı
+)
+
+// KnownHost is one line of a known_hosts file
+type KnownHost struct {
+	Hosts []string // Desenler; "|1|" ile başlayanlar hash'lenmiştir
+	Key   ssh.PublicKey
+}
+
+// Fingerprint returns the OpenSSH style SHA256 fingerprint of a host key
+func Fingerprint(key ssh.PublicKey) string {
+	return ssh.FingerprintSHA256(key)
+}
+
+// UserKnownHostsFile returns the path of ~/.ssh/known_hosts
+func UserKnownHostsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "known_hosts")
+}
+
+// PinnedHostKeys is a temporary known_hosts file and the ssh config that makes ssh trust only its keys.
+// ssh, -J atlamaları için açtığı ssh'lere komut satırındaki -o seçeneklerini değil yalnızca -F dosyasını
+// geçirir; bastion'ların anahtarları da bu yüzden config ile sabitlenir.
+type PinnedHostKeys struct {
+	KnownHosts string
+	Config     string
+	dir        string
+}
+
+// TempPinnedHostKeys writes lines to a new known_hosts file only the user can read, with its config.
+// Config önce bu dosyayı (sistemin known_hosts'u yerine de) ve StrictHostKeyChecking yes'i verir, sonra kullanıcının ve sistemin config'ini
+// içerir; ssh'te ilk verilen değer geçerli olduğundan diğer ayarlar eskisi gibi kalır.
+func TempPinnedHostKeys(lines []string) (*PinnedHostKeys, error) {
+	dir, err := os.MkdirTemp("", "client-man-known-hosts-")
+	if err != nil {
+		return nil, err
+	}
+	h := &PinnedHostKeys{
+		KnownHosts: filepath.Join(dir, "known_hosts"),
+		Config:     filepath.Join(dir, "config"),
+		dir:        dir,
+	}
+	config := strings.Join([]string{
+		"UserKnownHostsFile " + configPath(h.KnownHosts),
+		"GlobalKnownHostsFile " + configPath(h.KnownHosts),
+		"StrictHostKeyChecking yes",
+		"Include ~/.ssh/config",
+		"Include /etc/ssh/ssh_config",
+	}, "\n") + "\n"
+	if err := os.WriteFile(h.KnownHosts, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		h.Remove()
+		return nil, err
+	}
+	if err := os.WriteFile(h.Config, []byte(config), 0o600); err != nil {
+		h.Remove()
+		return nil, err
+	}
+	return h, nil
+}
+
+// Remove deletes the files
+func (h *PinnedHostKeys) Remove() {
+	os.RemoveAll(h.dir)
+}
+
+// configPath quotes a file for an ssh option value. ssh değeri boşluklardan böler ve % dizilerini
+// açar; "C:\Users\Ad Soyad\..." tek dosya olarak kalmalı.
+func configPath(file string) string {
+	file = strings.ReplaceAll(file, "%", "%%")
+	if strings.ContainsFunc(file, unicode.IsSpace) {
+		file = `"` + file + `"`
+	}
+	return file
+}
+
+// ReadKnownHosts reads a known_hosts file
+func ReadKnownHosts(file string) ([]KnownHost, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKnownHosts(data), nil
+}
+
+// ParseKnownHosts reads known_hosts lines. @cert-authority ve @revoked satırları ile
+// okunamayan satırlar atlanır; OpenSSH de bozuk satırı yok sayar.
+func ParseKnownHosts(data []byte) []KnownHost {
+	var entries []KnownHost
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		marker, hosts, key, _, _, err := ssh.ParseKnownHosts(line)
+		if err != nil || marker != "" {
+			continue
+		}
+		entries = append(entries, KnownHost{Hosts: hosts, Key: key})
+	}
+	return entries
+}
+
+// ParseHostKeyLines reads the trusted host keys stored with an environment
+func ParseHostKeyLines(lines []string) []KnownHost {
+	return ParseKnownHosts([]byte(strings.Join(lines, "\n")))
+}
+
+// HostKeyLine returns the known_hosts line that trusts key for addr (host:port)
+func HostKeyLine(addr string, key ssh.PublicKey) string {
+	return knownhosts.Line([]string{knownhosts.Normalize(addr)}, key)
+}
+
+// Matches reports whether the entry lists addr (host:port).
+// Hash'li adlar ve * / ? desenleri desteklenir; "!" ile başlayan desen eşleşirse satır geçersizdir.
+func (k KnownHost) Matches(addr string) bool {
+	host := strings.ToLower(knownhosts.Normalize(addr))
+	matched := false
+	for _, pattern := range k.Hosts {
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		var ok bool
+		if strings.HasPrefix(pattern, "|1|") {
+			ok = hashedHostMatches(pattern, host)
+		} else {
+			ok = wildcardMatch(strings.ToLower(pattern), host)
+		}
+		if ok && negated {
+			return false
+		}
+		matched = matched || ok
+	}
+	return matched
+}
+
+// wildcardMatch matches s against a known_hosts pattern where * is any run and ? any one character
+func wildcardMatch(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if wildcardMatch(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+		default:
+			if s == "" || s[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return s == ""
+}
+
+// hashedHostMatches checks a "|1|salt|hash" name (HashKnownHosts yes)
+func hashedHostMatches(pattern, host string) bool {
+	parts := strings.Split(pattern, "|")
+	if len(parts) != 4 {
+		return false
+	}
+	salt, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, salt)
+	mac.Write([]byte(host))
+	return hmac.Equal(mac.Sum(nil), want)
+}
+
+// KeysFor returns the keys listed for addr
+func KeysFor(entries []KnownHost, addr string) []ssh.PublicKey {
+	var keys []ssh.PublicKey
+	for _, e := range entries {
+		if e.Matches(addr) {
+			keys = append(keys, e.Key)
+		}
+	}
+	return keys
+}
+
+// CheckHostKey compares the offered key with the trusted keys of an address
+func CheckHostKey(trusted []ssh.PublicKey, key ssh.PublicKey) HostKeyStatus {
+	if len(trusted) == 0 {
+		return HostKeyNew
+	}
+	for _, t := range trusted {
+		if bytes.Equal(t.Marshal(), key.Marshal()) {
+			return HostKeyTrusted
+		}
+	}
+	return HostKeyChanged
+}
+
+// HostKeyAlgorithms returns the algorithms to negotiate so that the server offers one of the
+// trusted keys. Aksi halde sunucu başka türde bir anahtar sunar ve boşuna "değişti" uyarısı çıkar.
+func HostKeyAlgorithms(trusted []ssh.PublicKey) []string {
+	var algos []string
+	seen := map[string]bool{}
+	add := func(names ...string) {
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				algos = append(algos, name)
+			}
+		}
+	}
+	for _, key := range trusted {
+		if key.Type() == ssh.KeyAlgoRSA {
+			add(ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, ssh.KeyAlgoRSA)
+		} else {
+			add(key.Type())
+		}
+	}
+	return algos
+}
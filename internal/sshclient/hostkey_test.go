@@ -0,0 +1,66 @@
+package sshclient
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestTempPinnedHostKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := TempPinnedHostKeys([]string{HostKeyLine("10.0.0.1:22", key), HostKeyLine("jump.example:2222", key)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{h.KnownHosts, h.Config} {
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0o077 != 0 {
+			t.Fatalf("%s mode %o, want no access for group and others", filepath.Base(file), perm)
+		}
+	}
+	config, err := os.ReadFile(h.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sabitleme, içerilen kullanıcı config'inden önce gelmeli; ssh ilk değeri kullanır
+	pinned := configPath(h.KnownHosts)
+	want := "UserKnownHostsFile " + pinned + "\nGlobalKnownHostsFile " + pinned + "\nStrictHostKeyChecking yes\n"
+	if !strings.HasPrefix(string(config), want) {
+		t.Fatalf("config starts with %q, want %q", config, want)
+	}
+
+	entries, err := ReadKnownHosts(h.KnownHosts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []string{"10.0.0.1:22", "jump.example:2222"} {
+		if CheckHostKey(KeysFor(entries, addr), key) != HostKeyTrusted {
+			t.Errorf("%s not trusted by the written file", addr)
+		}
+	}
+	if len(KeysFor(entries, "jump.example:22")) != 0 {
+		t.Error("key trusted on a port it was not stored for")
+	}
+
+	h.Remove()
+	if _, err := os.Stat(filepath.Dir(h.KnownHosts)); !os.IsNotExist(err) {
+		t.Fatalf("temporary directory left behind: %v", err)
+	}
+}
@@ -0,0 +1,312 @@
+package sshclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Option is one "-o Key=Value" option of the ssh command
+type Option struct {
+	Key   string
+	Value string
+}
+
+// Params are the typed extra arguments of an environment's ssh command.
+// Kayıtta metin olarak durur (AppInfo.SSHParams); ParseParams ile okunur, Args ile argv'ye çevrilir.
+type Params struct {
+	ConfigFile    string   // -F; yalnızca uygulama verir, SSH params'ta kabul edilmez
+	Port          int      // -p; 0 ise belirtilmemiş
+	IdentityFiles []string // -i
+	JumpHosts     []string // -J, virgülle ayrılmış sıra; "[user@]host[:port]"
+	Ciphers       string   // -c; OpenSSH gibi başında + - ^ olabilir
+	Options       []Option // Diğer -o seçenekleri
+	Flags         []string // Değersiz bayraklar, örn. "-A", "-v"
+}
+
+// valueFlags take an argument; noValueFlags are the switches allowed without one.
+// Yerel komut çalıştırabilen veya bağlantının kendisini değiştiren bayraklar (-F, -W, -f, -N ...) kabul edilmez.
+const (
+	valueFlags   = "pioJc"
+	noValueFlags = "46AaCKkqTtvXxY"
+)
+
+// allowedOptions are the -o options passed on to ssh besides Port, IdentityFile, ProxyJump and Ciphers.
+// Listede olmayan her seçenek reddedilir; yerel komut çalıştıran veya kütüphane yükleyen seçenekler
+// (ProxyCommand, LocalCommand, PKCS11Provider, SecurityKeyProvider ...) bu yüzden hiç geçemez.
+var allowedOptions = []string{
+	"AddressFamily", "CheckHostIP", "Compression", "ConnectionAttempts", "ConnectTimeout",
+	"ForwardAgent", "HostKeyAlgorithms", "HostKeyAlias", "IdentitiesOnly", "KbdInteractiveAuthentication",
+	"KexAlgorithms", "LogLevel", "MACs", "PasswordAuthentication", "PreferredAuthentications",
+	"PubkeyAcceptedAlgorithms", "PubkeyAcceptedKeyTypes", "PubkeyAuthentication",
+	"ServerAliveCountMax", "ServerAliveInterval", "StrictHostKeyChecking", "TCPKeepAlive", "UserKnownHostsFile",
+}
+
+// knownCiphers are the cipher names OpenSSH accepts in -c
+var knownCiphers = []string{
+	"3des-cbc", "aes128-cbc", "aes192-cbc", "aes256-cbc",
+	"aes128-ctr", "aes192-ctr", "aes256-ctr",
+	"aes128-gcm@openssh.com", "aes256-gcm@openssh.com", "chacha20-poly1305@openssh.com",
+}
+
+// ParseParams parses the free-form SSH params of an environment, örn. "-p 2222 -i ~/.ssh/prod -o ServerAliveInterval=30".
+// Tırnaklar (' ve ") boşluk içeren değerleri gruplar. Sunucu ve kullanıcı ayrı alanlardan geldiği için
+// seçenek olmayan argümanlar hata sayılır.
+func ParseParams(s string) (Params, error) {
+	var p Params
+	args, err := splitArgs(s)
+	if err != nil {
+		return p, err
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' || arg == "--" {
+			return p, fmt.Errorf("unexpected argument %q; server and user come from their own fields", arg)
+		}
+		flag := arg[1]
+		if strings.IndexByte(valueFlags, flag) < 0 {
+			// -vvv gibi birleşik bayraklar
+			for _, c := range arg[1:] {
+				if c > unicode.MaxASCII || strings.IndexByte(noValueFlags, byte(c)) < 0 {
+					return p, fmt.Errorf("option -%c is not supported", c)
+				}
+				p.Flags = append(p.Flags, "-"+string(c))
+			}
+			continue
+		}
+
+		value := arg[2:]
+		if value == "" {
+			i++
+			if i == len(args) {
+				return p, fmt.Errorf("option -%c needs a value", flag)
+			}
+			value = args[i]
+		}
+		switch flag {
+		case 'p':
+			err = p.setPort(value)
+		case 'i':
+			err = p.addIdentityFile(value)
+		case 'J':
+			err = p.setJumpHosts(value)
+		case 'c':
+			err = p.setCiphers(value)
+		case 'o':
+			err = p.addOption(value)
+		}
+		if err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// Args returns the params as ssh arguments, her biri ayrı argv elemanı
+func (p Params) Args() []string {
+	var args []string
+	if p.ConfigFile != "" {
+		args = append(args, "-F", p.ConfigFile)
+	}
+	if p.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(p.Port))
+	}
+	for _, file := range p.IdentityFiles {
+		args = append(args, "-i", file)
+	}
+	if len(p.JumpHosts) > 0 {
+		args = append(args, "-J", strings.Join(p.JumpHosts, ","))
+	}
+	if p.Ciphers != "" {
+		args = append(args, "-c", p.Ciphers)
+	}
+	for _, o := range p.Options {
+		args = append(args, "-o", o.Key+"="+o.Value)
+	}
+	return append(args, p.Flags...)
+}
+
+// PinHostKeys makes ssh and its jump hosts trust only the keys in h. SSH params'taki aynı seçenekler
+// çıkarılır; böylece "StrictHostKeyChecking no" gibi bir değer kontrolü kapatamaz.
+func (p *Params) PinHostKeys(h *PinnedHostKeys) {
+	p.ConfigFile = h.Config
+	options := []Option{{Key: "UserKnownHostsFile", Value: configPath(h.KnownHosts)}, {Key: "StrictHostKeyChecking", Value: "yes"}}
+	for _, o := range p.Options {
+		if !strings.EqualFold(o.Key, "UserKnownHostsFile") && !strings.EqualFold(o.Key, "StrictHostKeyChecking") {
+			options = append(options, o)
+		}
+	}
+	p.Options = options
+}
+
+// CipherList returns the ciphers to use instead of the defaults.
+// + - ^ ile varsayılan listeyi değiştiren değerlerde nil döner; varsayılanlar kullanılır.
+func (p Params) CipherList() []string {
+	if p.Ciphers == "" || strings.ContainsAny(p.Ciphers[:1], "+-^") {
+		return nil
+	}
+	return strings.Split(p.Ciphers, ",")
+}
+
+// Command returns the argv of ssh connecting to user@host with p, "ssh" dahil.
+// Kullanıcı ve sunucu "-" ile başlayamaz; aksi halde ssh onları seçenek olarak okurdu.
+func Command(p Params, user, host string) ([]string, error) {
+	user, host = strings.TrimSpace(user), strings.TrimSpace(host)
+	for _, v := range []string{user, host} {
+		if v == "" || strings.HasPrefix(v, "-") || strings.ContainsFunc(v, unicode.IsSpace) || strings.ContainsFunc(v, unicode.IsControl) {
+			return nil, fmt.Errorf("invalid server or user %q", v)
+		}
+	}
+	return append(append([]string{"ssh"}, p.Args()...), user+"@"+host), nil
+}
+
+func (p *Params) setPort(value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %q", value)
+	}
+	p.Port = port
+	return nil
+}
+
+func (p *Params) addIdentityFile(value string) error {
+	if strings.TrimSpace(value) == "" || strings.ContainsFunc(value, unicode.IsControl) {
+		return errors.New("identity file is empty")
+	}
+	p.IdentityFiles = append(p.IdentityFiles, value)
+	return nil
+}
+
+func (p *Params) setJumpHosts(value string) error {
+	var hosts []string
+	for _, hop := range strings.Split(value, ",") {
+		if _, _, _, err := SplitJumpHost(hop); err != nil {
+			return err
+		}
+		hosts = append(hosts, hop)
+	}
+	p.JumpHosts = hosts
+	return nil
+}
+
+func (p *Params) setCiphers(value string) error {
+	for i, name := range strings.Split(value, ",") {
+		if i == 0 {
+			name = strings.TrimLeft(name, "+-^")
+		}
+		if !containsFold(knownCiphers, name) {
+			return fmt.Errorf("unknown cipher %q", name)
+		}
+	}
+	p.Ciphers = value
+	return nil
+}
+
+// addOption reads "Key=Value" or "Key Value"; port, kimlik, jump ve cipher seçenekleri tipli alanlara yazılır
+func (p *Params) addOption(value string) error {
+	key, val, ok := strings.Cut(strings.TrimSpace(value), "=")
+	if !ok {
+		key, val, ok = strings.Cut(strings.TrimSpace(value), " ")
+	}
+	key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+	if !ok || key == "" || val == "" {
+		return fmt.Errorf("option %q must be Key=Value", value)
+	}
+	for _, c := range key {
+		if c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c)) {
+			return fmt.Errorf("invalid option name %q", key)
+		}
+	}
+	if strings.ContainsFunc(val, unicode.IsControl) {
+		return fmt.Errorf("invalid value of option %s", key)
+	}
+
+	switch strings.ToLower(key) {
+	case "port":
+		return p.setPort(val)
+	case "identityfile":
+		return p.addIdentityFile(val)
+	case "proxyjump":
+		return p.setJumpHosts(val)
+	case "ciphers":
+		return p.setCiphers(val)
+	}
+	if !containsFold(allowedOptions, key) {
+		return fmt.Errorf("option %s is not supported", key)
+	}
+	p.Options = append(p.Options, Option{Key: key, Value: val})
+	return nil
+}
+
+// SplitJumpHost splits a "[user@]host[:port]" jump host; port 0 ise belirtilmemiş
+func SplitJumpHost(hop string) (user, host string, port int, err error) {
+	addr := strings.TrimSpace(hop)
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		user, addr = addr[:i], addr[i+1:]
+		if user == "" {
+			return "", "", 0, fmt.Errorf("invalid jump host %q", hop)
+		}
+	}
+	host = addr
+	if h, portStr, splitErr := net.SplitHostPort(addr); splitErr == nil {
+		host = h
+		if port, err = strconv.Atoi(portStr); err != nil || port < 1 || port > 65535 {
+			return "", "", 0, fmt.Errorf("invalid port of jump host %q", hop)
+		}
+	}
+	host = strings.Trim(host, "[]")
+	if host == "" || strings.HasPrefix(host, "-") || strings.HasPrefix(user, "-") || strings.ContainsFunc(user+host, unicode.IsSpace) || strings.ContainsAny(host, "/,") {
+		return "", "", 0, fmt.Errorf("invalid jump host %q", hop)
+	}
+	return user, host, port, nil
+}
+
+// splitArgs splits s into arguments like a shell would, without any expansion.
+// ' ve " içindeki boşluklar argümanı bölmez; kapanmamış tırnak hatadır.
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	for _, c := range s {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case unicode.IsSpace(c):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("missing closing quote %c", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
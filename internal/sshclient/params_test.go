@@ -0,0 +1,69 @@
+package sshclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseParams(t *testing.T) {
+	p, err := ParseParams(`-p 2222 -i "~/.ssh/prod key" -o ServerAliveInterval=30 -o "StrictHostKeyChecking no" -o Port=2200 -A -v`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"-p", "2200", "-i", "~/.ssh/prod key",
+		"-o", "ServerAliveInterval=30", "-o", "StrictHostKeyChecking=no",
+		"-A", "-v",
+	}
+	if got := p.Args(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Args() = %q, want %q", got, want)
+	}
+}
+
+func TestParseParamsRejectsUnlistedOptions(t *testing.T) {
+	for _, params := range []string{
+		"-o ProxyCommand=nc %h %p",
+		"-o LocalCommand=id",
+		"-o PermitLocalCommand=yes",
+		"-o KnownHostsCommand=/bin/true",
+		"-o PKCS11Provider=/tmp/evil.so",
+		"-o SecurityKeyProvider=/tmp/evil.so",
+		"-o pkcs11provider=/tmp/evil.so",
+		"-o Include=/etc/ssh/other",
+		"-o Match=exec true",
+		"-o NoSuchOption=1",
+		"-F /tmp/config",
+	} {
+		if _, err := ParseParams(params); err == nil {
+			t.Errorf("ParseParams(%q) accepted", params)
+		}
+	}
+}
+
+func TestPinHostKeys(t *testing.T) {
+	p, err := ParseParams(`-o "StrictHostKeyChecking no" -o UserKnownHostsFile=/dev/null -o ServerAliveInterval=30`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.PinHostKeys(&PinnedHostKeys{KnownHosts: "/tmp/pin/known_hosts", Config: "/tmp/pin/config"})
+	want := []string{
+		"-F", "/tmp/pin/config",
+		"-o", "UserKnownHostsFile=/tmp/pin/known_hosts", "-o", "StrictHostKeyChecking=yes",
+		"-o", "ServerAliveInterval=30",
+	}
+	if got := p.Args(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Args() = %q, want %q", got, want)
+	}
+}
+
+func TestParseParamsRejectsConfigFile(t *testing.T) {
+	if _, err := ParseParams("-F /tmp/evil_config"); err == nil {
+		t.Fatal("ParseParams accepted -F")
+	}
+}
+
+func TestConfigPath(t *testing.T) {
+	if got, want := configPath(`C:\Users\Ada Lovelace\AppData\Local\Temp\100%\known_hosts`), `"C:\Users\Ada Lovelace\AppData\Local\Temp\100%%\known_hosts"`; got != want {
+		t.Fatalf("configPath = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,298 @@
+package sshclient
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ReconnectDelay is the first wait before an automatic reconnect; her başarısız denemede ikiye katlanır
+const ReconnectDelay = 2 * time.Second
+
+// MaxReconnectDelay caps the wait between reconnect attempts
+const MaxReconnectDelay = time.Minute
+
+// ErrConnectionLost is the error of a tunnel whose SSH connection dropped
+var ErrConnectionLost = errors.New("SSH connection lost")
+
+// TunnelState is the lifecycle state of a tunnel
+type TunnelState int
+
+const (
+	TunnelStopped      TunnelState = iota
+	TunnelConnecting               // İlk bağlantı kuruluyor
+	TunnelRunning                  // Yerel port dinleniyor, SSH bağlantısı açık
+	TunnelReconnecting             // Bağlantı koptu veya kurulamadı, tekrar denenecek
+	TunnelFailed                   // Bağlantı yok ve yeniden denenmeyecek; Err nedenidir
+)
+
+func (s TunnelState) String() string {
+	switch s {
+	case TunnelConnecting:
+		return "connecting"
+	case TunnelRunning:
+		return "running"
+	case TunnelReconnecting:
+		return "reconnecting"
+	case TunnelFailed:
+		return "failed"
+	}
+	return "stopped"
+}
+
+// TunnelStats is a snapshot of a tunnel's state and traffic
+type TunnelStats struct {
+	State    TunnelState
+	Err      error // Son bağlantı hatası
+	Active   int   // Açık yönlendirilmiş bağlantılar
+	Total    int64 // Başlatıldığından beri kabul edilen bağlantılar
+	BytesIn  int64 // Uzak taraftan yerele
+	BytesOut int64 // Yerelden uzak tarafa
+}
+
+// Tunnel forwards a local TCP port to a host:port seen from an SSH server (ssh -L).
+// Yerel port Start ile hemen açılır; SSH bağlantısı arka planda kurulur ve AutoReconnect
+// açıksa koptuğunda artan aralıklarla yeniden kurulur.
+type Tunnel struct {
+	Local         string                      // Dinlenecek adres, örn. "127.0.0.1:15210"
+	Remote        string                      // Sunucu tarafında bağlanılacak host:port
+	Dial          func() (*ssh.Client, error) // Her (yeniden) bağlantıda çağrılır
+	AutoReconnect bool                        // Çalışırken SetAutoReconnect ile değiştirilir
+	OnChange      func()                      // Durum değişince çağrılır; herhangi bir goroutine'den gelebilir
+
+	mu       sync.Mutex
+	state    TunnelState
+	err      error
+	listener net.Listener
+	client   *ssh.Client
+	conns    map[net.Conn]struct{}
+	stop     chan struct{}
+
+	total    atomic.Int64
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+}
+
+// Start listens on the local port and connects in the background.
+// Port açılamazsa hata hemen döner; çalışan bir tünelde bir şey yapmaz.
+func (t *Tunnel) Start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stop != nil {
+		return nil
+	}
+	l, err := net.Listen("tcp", t.Local)
+	if err != nil {
+		return err
+	}
+	t.listener = l
+	t.conns = map[net.Conn]struct{}{}
+	t.stop = make(chan struct{})
+	t.state, t.err = TunnelConnecting, nil
+	t.total.Store(0)
+	t.bytesIn.Store(0)
+	t.bytesOut.Store(0)
+
+	go t.acceptLoop(l, t.stop)
+	go t.connectLoop(t.stop)
+	go t.changed()
+	return nil
+}
+
+// Stop closes the local port, the forwarded connections and the SSH connection
+func (t *Tunnel) Stop() {
+	t.mu.Lock()
+	stop := t.stop
+	t.mu.Unlock()
+	t.shutdown(stop, TunnelStopped, nil)
+}
+
+// shutdown closes everything of the run started with stop and sets the final state.
+// Tünel bu arada durdurulmuş veya yeniden başlatılmışsa bir şey yapmaz.
+func (t *Tunnel) shutdown(stop chan struct{}, state TunnelState, err error) {
+	t.mu.Lock()
+	if stop == nil || t.stop != stop {
+		t.mu.Unlock()
+		return
+	}
+	close(stop)
+	t.stop = nil
+	t.listener.Close()
+	if t.client != nil {
+		t.client.Close()
+		t.client = nil
+	}
+	for c := range t.conns {
+		c.Close()
+	}
+	t.conns = nil
+	t.state, t.err = state, err
+	t.mu.Unlock()
+	t.changed()
+}
+
+// SetAutoReconnect turns automatic reconnecting on or off, tünel çalışırken de
+func (t *Tunnel) SetAutoReconnect(on bool) {
+	t.mu.Lock()
+	t.AutoReconnect = on
+	t.mu.Unlock()
+}
+
+// Stats returns the current state and counters
+func (t *Tunnel) Stats() TunnelStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return TunnelStats{
+		State:    t.state,
+		Err:      t.err,
+		Active:   len(t.conns),
+		Total:    t.total.Load(),
+		BytesIn:  t.bytesIn.Load(),
+		BytesOut: t.bytesOut.Load(),
+	}
+}
+
+func (t *Tunnel) changed() {
+	if t.OnChange != nil {
+		t.OnChange()
+	}
+}
+
+// setState records a state change unless the tunnel was stopped meanwhile
+func (t *Tunnel) setState(stop chan struct{}, state TunnelState, err error) bool {
+	t.mu.Lock()
+	if t.stop != stop {
+		t.mu.Unlock()
+		return false
+	}
+	t.state, t.err = state, err
+	t.mu.Unlock()
+	t.changed()
+	return true
+}
+
+// connectLoop keeps the SSH connection up until the tunnel stops
+func (t *Tunnel) connectLoop(stop chan struct{}) {
+	delay := ReconnectDelay
+	for {
+		client, err := t.Dial()
+		if err == nil {
+			t.mu.Lock()
+			if t.stop != stop {
+				t.mu.Unlock()
+				client.Close()
+				return
+			}
+			t.client = client
+			t.mu.Unlock()
+			t.setState(stop, TunnelRunning, nil)
+			delay = ReconnectDelay
+
+			client.Wait()
+			t.mu.Lock()
+			if t.client == client {
+				t.client = nil
+			}
+			t.mu.Unlock()
+			err = ErrConnectionLost
+		}
+
+		t.mu.Lock()
+		reconnect := t.AutoReconnect
+		t.mu.Unlock()
+		if !reconnect {
+			// Port kapatılır; kullanıcı tekrar başlatana kadar kimse bekletilmez
+			t.shutdown(stop, TunnelFailed, err)
+			return
+		}
+		if !t.setState(stop, TunnelReconnecting, err) {
+			return
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, MaxReconnectDelay)
+	}
+}
+
+// acceptLoop forwards every local connection while an SSH connection is up
+func (t *Tunnel) acceptLoop(l net.Listener, stop chan struct{}) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		t.mu.Lock()
+		client := t.client
+		if t.stop != stop || client == nil {
+			// Bağlantı yokken gelen istemci beklemek yerine hemen kapanır
+			t.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		t.conns[conn] = struct{}{}
+		t.mu.Unlock()
+		t.total.Add(1)
+		go t.forward(conn, client)
+	}
+}
+
+// forward copies one local connection to the remote address and back
+func (t *Tunnel) forward(local net.Conn, client *ssh.Client) {
+	defer func() {
+		local.Close()
+		t.mu.Lock()
+		delete(t.conns, local)
+		t.mu.Unlock()
+		t.changed()
+	}()
+	t.changed()
+
+	remote, err := client.Dial("tcp", t.Remote)
+	if err != nil {
+		return
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(countingWriter{remote, &t.bytesOut}, local)
+		closeWrite(remote)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(countingWriter{local, &t.bytesIn}, remote)
+		closeWrite(local)
+		done <- struct{}{}
+	}()
+	<-done
+	<-done
+}
+
+// closeWrite half-closes c when it supports it, aksi halde tamamen kapatır
+func closeWrite(c net.Conn) {
+	if cw, ok := c.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+		return
+	}
+	c.Close()
+}
+
+// countingWriter adds the bytes written to n
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Int64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
@@ -1,140 +1,286 @@
 package main
 
 import (
-	"encoding/json"
+	"crypto/subtle"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 
+	"clientinfo/internal/history"
+	"clientinfo/internal/keystore"
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/widget"
 )
 
 // AppState holds the application state
 type AppState struct {
-	myApp             fyne.App
-	window            fyne.Window
-	clients           []Client
-	filteredClients   []Client
-	listContainer     *fyne.Container
-	searchEntry       *widget.Entry
-	currentFile       string
-	expandedCompanies map[string]bool         // Firma adı -> açık/kapalı durumu
-	expandedApps      map[string]map[int]bool // Firma adı -> (App index -> açık/kapalı)
-	activeTabIndex    map[string]int          // Firma adı -> aktif tab index
+	myApp                fyne.App
+	window               fyne.Window
+	clients              []Client
+	filteredClients      []Client
+	listContainer        *fyne.Container
+	searchEntry          *widget.Entry
+	currentFile          string
+	expandedClients      map[string]bool            // Client ID -> açık/kapalı durumu
+	expandedApps         map[string]bool            // App ID -> açık/kapalı durumu
+	activeTabIndex       map[string]int             // Client ID -> aktif tab index
+	vaultKDF             vault.KDFParams            // Açık vault'un KDF parametreleri (salt dahil)
+	vaultKey             []byte                     // Master password'den türetilen anahtar
+	vaultCipher          string                     // vault.CipherAESGCM (alan bazlı) veya vault.CipherXChaCha (tüm dosya)
+	keys                 keystore.Store             // "Bu bilgisayarda hatırla" için OS keyring
+	history              *history.Log               // Alan bazlı değişiklik geçmişi ve undo/redo yığınları
+	store                store.Store                // Açık vault'un depolaması (JSON dosya veya SQLite)
+	syncedFile           store.Fingerprint          // Vault dosyasının son okunan/yazılan hali
+	syncedClients        []Client                   // Dosyadaki client'lar (birleştirmede ortak taban)
+	dismissedFile        store.Fingerprint          // Kullanıcının yeniden yüklemeyi reddettiği dış değişiklik
+	conflictOpen         bool                       // Yeniden yükleme / birleştirme dialogu açık
+	terminals            map[string]*terminalWindow // App ID -> açık SSH terminal penceresi
+	tunnels              map[string]*tunnelRun      // Tunnel ID -> başlatılmış SSH tüneli
+	tunnelRows           map[string]*tunnelRow      // Tunnel ID -> ekrandaki satırı
+	autoLockStarted      bool
+	fileWatchStarted     bool
+	tunnelRefreshStarted bool
 }
 
-// FileManager handles file I/O operations
-type FileManager struct {
-	filePath string
+// LoadClients reads the vault at path, unlocks it with the master password and decrypts client data.
+func (s *AppState) loadClients(path string, password string) error {
+	return s.openVaultFile(path, &vault.MigrationContext{Password: password})
 }
 
-// NewFileManager creates a new FileManager instance
-func NewFileManager(filePath string) *FileManager {
-	return &FileManager{
-		filePath: filePath,
-	}
-}
-
-// SetFilePath updates the current file path
-func (fm *FileManager) SetFilePath(filePath string) {
-	fm.filePath = filePath
+// loadClientsWithKey opens the vault at path with an already derived key (örn. keyring'den)
+func (s *AppState) loadClientsWithKey(path string, key []byte) error {
+	return s.openVaultFile(path, &vault.MigrationContext{Key: key})
 }
 
-// GetFilePath returns the current file path
-func (fm *FileManager) GetFilePath() string {
-	return fm.filePath
-}
-
-// LoadClients reads and parses client data from a JSON file
-func (s *AppState) loadClients(path string) error {
-	data, err := os.ReadFile(path)
+// openVaultFile opens the store for path, then migrates and decrypts the vault.
+// Eski sürümdeki dosyalar kayıtlı migration adımlarıyla güncel formata taşınır.
+func (s *AppState) openVaultFile(path string, ctx *vault.MigrationContext) error {
+	st, err := store.Open(path)
 	if err != nil {
 		return err
 	}
 
-	if err := json.Unmarshal(data, &s.clients); err != nil {
+	unlocked, err := st.Unlock(ctx)
+	if err != nil {
+		st.Close()
 		return err
 	}
 
-	// Check if any password fields are unencrypted and encrypt them if needed
-	hasUnencrypted := false
-	for i := range s.clients {
-		if (s.clients[i].VPN.Password != "" && !isEncrypted(s.clients[i].VPN.Password)) ||
-			(s.clients[i].Data.JiraPassword != "" && !isEncrypted(s.clients[i].Data.JiraPassword)) {
-			hasUnencrypted = true
-			break
-		}
-		for j := range s.clients[i].Apps {
-			if (s.clients[i].Apps[j].Password != "" && !isEncrypted(s.clients[i].Apps[j].Password)) ||
-				(s.clients[i].Apps[j].AppServerPass != "" && !isEncrypted(s.clients[i].Apps[j].AppServerPass)) {
-				hasUnencrypted = true
-				break
-			}
-		}
-		if hasUnencrypted {
-			break
+	if unlocked.FromVersion < vault.FormatVersion {
+		// Yedek dosya oluştur - orijinal dosya henüz değişmedi
+		backupPath := fmt.Sprintf("%s.v%d.backup", path, unlocked.FromVersion)
+		if err := store.CopyFile(path, backupPath, store.VaultPerm); err != nil {
+			st.Close()
+			return fmt.Errorf("backup oluşturulamadı: %w", err)
 		}
 	}
 
-	// If unencrypted passwords found, encrypt them and save immediately
-	if hasUnencrypted {
-		// Yedek dosya oluştur - orijinal plaintext JSON'u koru
-		backupPath := path + ".backup"
-		if err := os.Rename(path, backupPath); err != nil {
-			return fmt.Errorf("backup oluşturulamadı: %w", err)
-		}
+	// ID'siz (eski) client ve ortamlara kalıcı ID verilir ve kaydedilir
+	needsSave := unlocked.NeedsSave
+	if model.EnsureIDs(unlocked.Clients) {
+		needsSave = true
+	}
 
-		clientsCopy := make([]Client, len(s.clients))
-		copy(clientsCopy, s.clients)
-		if err := encryptClientsInPlace(clientsCopy); err != nil {
-			// Hata durumunda yedegi geri yükle
-			os.Rename(backupPath, path)
-			return err
-		}
-		// Update s.clients with encrypted values
-		s.clients = clientsCopy
-
-		// Write encrypted data back to file
-		encData, err := json.MarshalIndent(s.clients, "", "  ")
-		if err != nil {
-			// Hata durumunda yedegi geri yükle
-			os.Rename(backupPath, path)
-			return err
-		}
-		if err := os.WriteFile(path, encData, 0644); err != nil {
-			// Hata durumunda yedegi geri yükle
-			os.Rename(backupPath, path)
-			return err
-		}
+	if err := s.setVault(st, unlocked.Clients, unlocked.KDF, unlocked.Key, unlocked.Cipher); err != nil {
+		return err
+	}
+	if needsSave {
+		return s.saveClients()
 	}
+	return nil
+}
 
-	// Decrypt any encrypted password fields after loading (or after migration)
-	if err := decryptClientsInPlace(s.clients); err != nil {
+// createVault starts an empty vault at path protected by the given master password
+func (s *AppState) createVault(path string, password string) error {
+	params, err := vault.NewKDFParams()
+	if err != nil {
+		return err
+	}
+	key, err := vault.DeriveKey(password, params)
+	if err != nil {
 		return err
 	}
+	st, err := store.Open(path)
+	if err != nil {
+		return err
+	}
+
+	if err := s.setVault(st, []Client{}, params, key, vault.CipherAESGCM); err != nil {
+		return err
+	}
+	return s.saveClients()
+}
+
+// setVault installs the store, unlocked client data and key material into the state.
+// Önceki store kapatılır.
+func (s *AppState) setVault(st store.Store, clients []Client, params vault.KDFParams, key []byte, cipherName string) error {
+	if err := st.SetKeys(store.Keys{KDF: params, Key: key, Cipher: cipherName}); err != nil {
+		st.Close()
+		return err
+	}
+	if s.store != nil && s.store != st {
+		s.store.Close()
+	}
+	s.store = st
 
+	if clients == nil {
+		clients = []Client{}
+	}
+	s.clients = clients
 	s.filteredClients = make([]Client, len(s.clients))
 	copy(s.filteredClients, s.clients)
-	s.currentFile = path
-	s.window.SetTitle(fmt.Sprintf("Client Info Manager — %s", filepath.Base(path)))
-
+	s.currentFile = st.Path()
+	s.vaultKDF = params
+	s.vaultKey = key
+	s.vaultCipher = cipherName
+	s.loadHistory(s.currentFile, key)
+	s.myApp.Preferences().SetString(PrefLastVaultFile, s.currentFile)
+	s.dismissedFile = store.Fingerprint{}
+	s.markSynced()
 	return nil
 }
 
-// SaveClients writes client data to JSON file
+// storeKeys returns the key material of the open vault
+func (s *AppState) storeKeys() store.Keys {
+	return store.Keys{KDF: s.vaultKDF, Key: s.vaultKey, Cipher: s.vaultCipher}
+}
+
+// SaveClients writes all client data to the store
 func (s *AppState) saveClients() error {
-	// Make a copy of clients and encrypt password fields before writing
-	clientsCopy := make([]Client, len(s.clients))
-	copy(clientsCopy, s.clients)
-	if err := encryptClientsInPlace(clientsCopy); err != nil {
+	if s.vaultKey == nil || s.store == nil {
+		return errors.New("vault is locked")
+	}
+	s.touchActivity()
+	if err := s.checkDisk(); err != nil {
 		return err
 	}
 
-	data, err := json.MarshalIndent(clientsCopy, "", "  ")
-	if err != nil {
+	if err := s.store.Save(s.clients); err != nil {
+		return err
+	}
+	return s.afterSave()
+}
+
+// saveClient writes only the client at index, matched in the store by its ID.
+// SQLite'ta tek satır güncellenir; JSON dosyada dosya yine bütün olarak yazılır.
+func (s *AppState) saveClient(index int) error {
+	if err := s.putClient(index); err != nil {
+		return err
+	}
+	return s.afterSave()
+}
+
+// putClient writes the client at index to the store, geçmiş ve yedek adımları olmadan
+func (s *AppState) putClient(index int) error {
+	if s.vaultKey == nil || s.store == nil {
+		return errors.New("vault is locked")
+	}
+	if index < 0 || index >= len(s.clients) {
+		return errors.New(DialogMsgClientNotFound)
+	}
+	s.touchActivity()
+	if err := s.checkDisk(); err != nil {
+		return err
+	}
+
+	return s.store.Put(s.clients[index])
+}
+
+// removeStoredClient removes the client with id from the store, geçmiş ve yedek adımları olmadan
+func (s *AppState) removeStoredClient(id string) error {
+	if s.vaultKey == nil || s.store == nil {
+		return errors.New("vault is locked")
+	}
+	s.touchActivity()
+	if err := s.checkDisk(); err != nil {
 		return err
 	}
 
-	return os.WriteFile(s.currentFile, data, 0644)
+	return s.store.Delete(id)
+}
+
+// afterSave records the written file, writes the change history and takes a backup snapshot if one is due
+func (s *AppState) afterSave() error {
+	s.markSynced()
+	if err := s.saveHistory(); err != nil {
+		return err
+	}
+
+	// Kayıt başarılı; yedek alınamazsa kullanıcı bilgilendirilir ama veri diskte
+	if err := s.backupManager().SnapshotIfDue(s.backupInterval()); err != nil {
+		return fmt.Errorf(DialogMsgBackupFailed, err)
+	}
+	return nil
+}
+
+// rotateVaultKey re-encrypts the open vault under a key derived from newPassword with a fresh salt.
+// Mevcut dosya önce zaman damgalı bir yedeğe kopyalanır, ardından tüm veri yeni anahtarla yazılır.
+// Yedek dosyanın yolunu döner.
+func (s *AppState) rotateVaultKey(currentPassword, newPassword string) (string, error) {
+	if s.vaultKey == nil || s.store == nil {
+		return "", errors.New("vault is locked")
+	}
+
+	// Mevcut şifre aynı KDF parametreleriyle aynı anahtarı türetmeli
+	currentKey, err := vault.DeriveKey(currentPassword, s.vaultKDF)
+	if err != nil {
+		return "", err
+	}
+	if subtle.ConstantTimeCompare(currentKey, s.vaultKey) != 1 {
+		return "", vault.ErrWrongPassword
+	}
+	// Arka planda çalışır; dış değişiklik dialogu dosya izleyicisinden açılır
+	if _, changed := s.changedOnDisk(); changed {
+		return "", errChangedOnDisk
+	}
+
+	params, err := vault.NewKDFParams()
+	if err != nil {
+		return "", err
+	}
+	newKey, err := vault.DeriveKey(newPassword, params)
+	if err != nil {
+		return "", err
+	}
+
+	snap, err := s.backupManager().Snapshot()
+	if err != nil {
+		return "", err
+	}
+	backupPath := snap.Path
+
+	// Seal her gizli alan için yeni nonce ürettiğinden eski anahtarla yazılmış
+	// hiçbir şifreli metin yeni veride kalmaz
+	previous := s.storeKeys()
+	if err := s.store.SetKeys(store.Keys{KDF: params, Key: newKey, Cipher: s.vaultCipher}); err != nil {
+		return "", err
+	}
+	if err := s.store.Save(s.clients); err != nil {
+		s.store.SetKeys(previous)
+		return "", err
+	}
+	s.markSynced()
+
+	// Keyring'deki kayıt eski salt'a bağlı; hatırlanıyorsa yeni anahtarla güncelle
+	remembered := s.hasRememberedKey(s.currentFile)
+
+	for i := range s.vaultKey {
+		s.vaultKey[i] = 0
+	}
+	s.vaultKDF = params
+	s.vaultKey = newKey
+
+	// Geçmiş de eski anahtarla şifreli; yeni anahtarla yeniden yazılır
+	if err := s.saveHistory(); err != nil {
+		return backupPath, err
+	}
+
+	if remembered {
+		if err := s.rememberVaultKey(); err != nil {
+			return backupPath, fmt.Errorf(DialogMsgKeyringUnavailable, err)
+		}
+	}
+	return backupPath, nil
 }
@@ -0,0 +1,133 @@
+package store
+
+import (
+	"errors"
+	"strings"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/vault"
+)
+
+// ErrLocked is returned when a store is used before Unlock or SetKeys
+var ErrLocked = errors.New("vault is locked")
+
+// ErrNotFound is returned by Get and Delete when no client has the given ID
+var ErrNotFound = errors.New("customer not found in store")
+
+// Keys is the key material a store encrypts with
+type Keys struct {
+	KDF    vault.KDFParams
+	Key    []byte
+	Cipher string // vault.CipherAESGCM veya vault.CipherXChaCha
+}
+
+// Clone returns k with its own copy of the key bytes.
+// Store'lar çağıranın dilimini saklamaz; Close kendi kopyalarını siler.
+func (k Keys) Clone() Keys {
+	k.Key = append([]byte(nil), k.Key...)
+	return k
+}
+
+// Wipe zeroes the key bytes and clears k
+func (k *Keys) Wipe() {
+	for i := range k.Key {
+		k.Key[i] = 0
+	}
+	*k = Keys{}
+}
+
+// Store persists the clients of one vault. Client'lar kalıcı ID'leriyle
+// adreslenir (bkz. model.EnsureIDs).
+//
+// Unlock veya SetKeys çağrılmadan okuma/yazma yapılamaz.
+type Store interface {
+	// Path returns the file the store persists to
+	Path() string
+	// Status reports whether the vault is missing, legacy or protected, without decrypting anything
+	Status() (vault.Status, error)
+	// KDF returns the stored key derivation parameters (keyring'deki anahtarı açmak için)
+	KDF() (vault.KDFParams, error)
+	// Unlock verifies ctx against the stored key check, runs pending migrations and returns the decrypted clients
+	Unlock(ctx *vault.MigrationContext) (*vault.Unlocked, error)
+	// SetKeys sets the key material for following writes. Anahtar değiştiyse
+	// (yeni vault, rotasyon) tüm veriyi yeniden şifrelemek için Save çağrılmalıdır.
+	SetKeys(keys Keys) error
+
+	// Load returns all clients in order
+	Load() ([]model.Client, error)
+	// Save replaces all stored clients
+	Save(clients []model.Client) error
+	// Get returns the client with id
+	Get(id string) (model.Client, error)
+	// Put stores c in place of the client with the same ID, or appends it
+	Put(c model.Client) error
+	// Delete removes the client with id
+	Delete(id string) error
+	// Query returns the clients whose company, EBS version or notes contain text (büyük/küçük harf duyarsız)
+	Query(text string) ([]model.Client, error)
+
+	// Close releases the store; önbellekteki çözülmüş veri ve anahtar kopyaları silinir
+	Close() error
+}
+
+// Backend opens stores for the files it recognizes
+type Backend struct {
+	Name  string
+	Match func(path string) bool
+	Open  func(path string) (Store, error)
+}
+
+// backends holds the registered backends; JSON dosya backend'i eşleşme olmazsa kullanılır
+var backends []Backend
+
+// Register adds a backend. Sadece init içinden çağrılmalıdır.
+func Register(b Backend) {
+	backends = append(backends, b)
+}
+
+// Open returns the store for path chosen by the registered backends
+func Open(path string) (Store, error) {
+	return OpenAs(path, path)
+}
+
+// OpenAs opens path with the backend that handles like. Uzantısı vault'tan
+// farklı olan snapshot dosyaları (.backup) bu yolla açılır.
+func OpenAs(like, path string) (Store, error) {
+	for _, b := range backends {
+		if b.Match(like) {
+			return b.Open(path)
+		}
+	}
+	return OpenJSON(path), nil
+}
+
+// DetectStatus opens path only long enough to report its status
+func DetectStatus(path string) (vault.Status, error) {
+	st, err := Open(path)
+	if err != nil {
+		return vault.StatusMissing, err
+	}
+	defer st.Close()
+	return st.Status()
+}
+
+// ReadKDF returns the key derivation parameters stored at path
+func ReadKDF(path string) (vault.KDFParams, error) {
+	st, err := Open(path)
+	if err != nil {
+		return vault.KDFParams{}, err
+	}
+	defer st.Close()
+	return st.KDF()
+}
+
+// MatchesQuery is the in-memory form of Store.Query
+func MatchesQuery(c model.Client, text string) bool {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(c.Company), text) ||
+		strings.Contains(strings.ToLower(c.EBSVersion), text) ||
+		strings.Contains(strings.ToLower(c.Notes), text)
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"time"
+)
+
+// Fingerprint identifies the content of a file at one point in time. Dosya
+// yoksa sıfır değerdir.
+type Fingerprint struct {
+	ModTime time.Time
+	Size    int64
+	Hash    [sha256.Size]byte
+}
+
+// FingerprintOf reads the fingerprint of path; eksik dosya hata değildir
+func FingerprintOf(path string) (Fingerprint, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return Fingerprint{}, nil
+	}
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	return Fingerprint{ModTime: info.ModTime(), Size: info.Size(), Hash: hash}, nil
+}
+
+// Changed reports whether path no longer has the content f was taken from, and returns
+// its current fingerprint. Değişiklik zamanı ve boyut aynıysa dosya okunmaz; paylaşılan
+// sürücülerde sadece zamanı değişen (touch) dosyalar hash ile ayıklanır.
+func (f Fingerprint) Changed(path string) (Fingerprint, bool, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return Fingerprint{}, f != Fingerprint{}, nil
+	}
+	if err != nil {
+		return f, false, err
+	}
+	if info.ModTime().Equal(f.ModTime) && info.Size() == f.Size {
+		return f, false, nil
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return f, false, err
+	}
+	current := Fingerprint{ModTime: info.ModTime(), Size: info.Size(), Hash: hash}
+	return current, hash != f.Hash || f == Fingerprint{}, nil
+}
+
+func hashFile(path string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	file, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return sum, err
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
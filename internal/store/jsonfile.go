@@ -0,0 +1,169 @@
+package store
+
+import (
+	"os"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/vault"
+)
+
+// JSONFile is the original single-file vault: tüm client listesi tek bir
+// vault zarfı olarak yazılır. Tek bir client değişse de dosyanın tamamı
+// (atomik olarak) yeniden yazılır.
+type JSONFile struct {
+	path    string
+	keys    Keys
+	hasKeys bool
+	clients []model.Client // Son okunan/yazılan veri; Put/Delete bunun üzerinden çalışır
+}
+
+// OpenJSON returns the JSON file store for path. Dosya ilk yazmada oluşturulur.
+func OpenJSON(path string) *JSONFile {
+	return &JSONFile{path: path}
+}
+
+// Path returns the vault file
+func (f *JSONFile) Path() string {
+	return f.path
+}
+
+// Status reports the vault status of the file
+func (f *JSONFile) Status() (vault.Status, error) {
+	return vault.DetectStatus(f.path)
+}
+
+// KDF reads the key derivation parameters from the envelope header
+func (f *JSONFile) KDF() (vault.KDFParams, error) {
+	env, err := vault.ReadHeader(f.path)
+	if err != nil {
+		return vault.KDFParams{}, err
+	}
+	return env.KDF, nil
+}
+
+// Unlock reads, migrates and decrypts the file
+func (f *JSONFile) Unlock(ctx *vault.MigrationContext) (*vault.Unlocked, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := vault.Unlock(data, ctx)
+	if err != nil {
+		return nil, err
+	}
+	f.keys = Keys{KDF: unlocked.KDF, Key: unlocked.Key, Cipher: unlocked.Cipher}.Clone()
+	f.hasKeys = true
+	f.clients = vault.CloneClients(unlocked.Clients)
+	return unlocked, nil
+}
+
+// SetKeys sets the key material for following writes
+func (f *JSONFile) SetKeys(keys Keys) error {
+	f.keys = keys.Clone()
+	f.hasKeys = true
+	return nil
+}
+
+// Load returns all clients
+func (f *JSONFile) Load() ([]model.Client, error) {
+	if err := f.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	return vault.CloneClients(f.clients), nil
+}
+
+// Save seals all clients and atomically replaces the file
+func (f *JSONFile) Save(clients []model.Client) error {
+	if !f.hasKeys {
+		return ErrLocked
+	}
+	data, err := vault.Marshal(f.keys.KDF, f.keys.Key, f.keys.Cipher, clients)
+	if err != nil {
+		return err
+	}
+	if err := WriteFile(f.path, data, VaultPerm); err != nil {
+		return err
+	}
+	f.clients = vault.CloneClients(clients)
+	return nil
+}
+
+// Get returns the client with id
+func (f *JSONFile) Get(id string) (model.Client, error) {
+	if err := f.ensureLoaded(); err != nil {
+		return model.Client{}, err
+	}
+	i := model.IndexByID(f.clients, id)
+	if i < 0 {
+		return model.Client{}, ErrNotFound
+	}
+	return model.CloneClient(f.clients[i]), nil
+}
+
+// Put replaces the client with c's ID, or appends c, and rewrites the file
+func (f *JSONFile) Put(c model.Client) error {
+	if err := f.ensureLoaded(); err != nil {
+		return err
+	}
+	clients := vault.CloneClients(f.clients)
+	if i := model.IndexByID(clients, c.ID); i >= 0 {
+		clients[i] = c
+	} else {
+		clients = append(clients, c)
+	}
+	return f.Save(clients)
+}
+
+// Delete removes the client with id and rewrites the file
+func (f *JSONFile) Delete(id string) error {
+	if err := f.ensureLoaded(); err != nil {
+		return err
+	}
+	i := model.IndexByID(f.clients, id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	clients := vault.CloneClients(f.clients)
+	return f.Save(append(clients[:i], clients[i+1:]...))
+}
+
+// Query filters the clients in memory
+func (f *JSONFile) Query(text string) ([]model.Client, error) {
+	if err := f.ensureLoaded(); err != nil {
+		return nil, err
+	}
+	var out []model.Client
+	for _, c := range f.clients {
+		if MatchesQuery(c, text) {
+			out = append(out, model.CloneClient(c))
+		}
+	}
+	return out, nil
+}
+
+// Close wipes the cached clients and the key; kilitli uygulamada düz metin kopya kalmasın
+func (f *JSONFile) Close() error {
+	for i := range f.clients {
+		f.clients[i] = model.Client{}
+	}
+	f.clients = nil
+	f.keys.Wipe()
+	f.hasKeys = false
+	return nil
+}
+
+// ensureLoaded reads the file with the current key if nothing is cached yet
+func (f *JSONFile) ensureLoaded() error {
+	if !f.hasKeys {
+		return ErrLocked
+	}
+	if f.clients != nil {
+		return nil
+	}
+	if _, err := os.Stat(f.path); os.IsNotExist(err) {
+		f.clients = []model.Client{}
+		return nil
+	}
+	_, err := f.Unlock(&vault.MigrationContext{Key: f.keys.Key})
+	return err
+}
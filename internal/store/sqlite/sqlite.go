@@ -0,0 +1,564 @@
+// Package sqlite is a vault store backed by an embedded, pure-Go SQLite database.
+//
+// Her client ayrı bir satırdır ve kalıcı ID'si (uid) ile adreslenir; tek bir
+// alan değiştiğinde sadece o satır yazılır. Firma adı, EBS versiyonu ve notlar arama için açık sütunlardadır,
+// diğer alanlar gizli değerleri boşaltılmış JSON olarak "data" sütununda,
+// gizli değerler ise vault anahtarıyla XChaCha20-Poly1305 ile şifrelenmiş
+// olarak "secrets" sütununda tutulur. Tüm dosya şifreleme modu desteklenmez.
+//
+// Paket import edildiğinde .db, .sqlite ve .sqlite3 uzantılı dosyalar için
+// store.Open tarafından seçilir.
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/store"
+	"clientinfo/internal/vault"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// FormatName identifies client-man SQLite vaults in the meta table
+	FormatName = "client-man-sqlite"
+	// SchemaVersion is the schema written by this package (2: uid sütunu, 3: secrets uid'ye bağlı)
+	SchemaVersion = 3
+	// uidSchemaVersion is the first schema with the uid column
+	uidSchemaVersion = 2
+)
+
+// ErrUnsupportedCipher is returned when whole-file encryption is requested
+var ErrUnsupportedCipher = errors.New("whole-file encryption is not available for SQLite vaults")
+
+// errRekeyPending blocks single-row writes after a key change until all rows are re-encrypted
+var errRekeyPending = errors.New("the vault key changed; the whole vault must be saved first")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS clients (
+	id          INTEGER PRIMARY KEY,
+	uid         TEXT NOT NULL DEFAULT '',
+	pos         INTEGER NOT NULL,
+	company     TEXT NOT NULL,
+	ebs_version TEXT NOT NULL DEFAULT '',
+	notes       TEXT NOT NULL DEFAULT '',
+	data        TEXT NOT NULL,
+	secrets     TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS clients_company ON clients(company);
+CREATE INDEX IF NOT EXISTS clients_pos ON clients(pos);
+`
+
+func init() {
+	store.Register(store.Backend{
+		Name:  "sqlite",
+		Match: Match,
+		Open:  func(path string) (store.Store, error) { return Open(path), nil },
+	})
+}
+
+// Match reports whether path has a SQLite file extension
+func Match(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return true
+	}
+	return false
+}
+
+// DB is a SQLite vault store. Veritabanı ilk erişimde açılır, dosya ilk
+// yazmada oluşturulur.
+type DB struct {
+	path    string
+	db      *sql.DB
+	keys    store.Keys
+	hasKeys bool
+	rowKey  []byte // Satırların ve meta'nın şifreli olduğu anahtar (yeni dosyada nil)
+	rekeyed bool   // keys.Key != rowKey: tek satır yazmadan önce her şey yeniden şifrelenmeli
+	// legacyAAD: şema 3 öncesi dosyada secrets firma adına bağlı; ilk tam kayıtta uid'ye taşınır
+	legacyAAD bool
+}
+
+// Open returns the store for path without touching the file
+func Open(path string) *DB {
+	return &DB{path: path}
+}
+
+// Path returns the database file
+func (d *DB) Path() string {
+	return d.path
+}
+
+// conn opens the database and creates the schema on first use
+func (d *DB) conn() (*sql.DB, error) {
+	if d.db != nil {
+		return d.db, nil
+	}
+	db, err := sql.Open("sqlite", d.path)
+	if err != nil {
+		return nil, err
+	}
+	// Tek bağlantı: işlemler sıralı, dosya kilidi basit kalır
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := upgradeSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := os.Chmod(d.path, store.VaultPerm); err != nil {
+		db.Close()
+		return nil, err
+	}
+	d.db = db
+	return db, nil
+}
+
+// upgradeSchema adds the columns of newer schema versions to an existing database.
+// Eski satırların uid'si boş kalır; uygulama açılışta ID atayıp tümünü yeniden yazar.
+func upgradeSchema(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info('clients')")
+	if err != nil {
+		return err
+	}
+	hasUID := false
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		hasUID = hasUID || name == "uid"
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if !hasUID {
+		if _, err := db.Exec(`ALTER TABLE clients ADD COLUMN uid TEXT NOT NULL DEFAULT ''`); err != nil {
+			return err
+		}
+		if _, err := db.Exec(`UPDATE meta SET value = ? WHERE key = 'version'`, strconv.Itoa(uidSchemaVersion)); err != nil {
+			return err
+		}
+	}
+	_, err = db.Exec("CREATE INDEX IF NOT EXISTS clients_uid ON clients(uid)")
+	return err
+}
+
+// Status reports StatusMissing for a missing or uninitialized database
+func (d *DB) Status() (vault.Status, error) {
+	if _, err := os.Stat(d.path); os.IsNotExist(err) {
+		return vault.StatusMissing, nil
+	}
+	meta, err := d.readMeta()
+	if err != nil {
+		return vault.StatusMissing, err
+	}
+	if meta["check"] == "" {
+		return vault.StatusMissing, nil
+	}
+	return vault.StatusProtected, nil
+}
+
+// KDF returns the key derivation parameters from the meta table
+func (d *DB) KDF() (vault.KDFParams, error) {
+	meta, err := d.readMeta()
+	if err != nil {
+		return vault.KDFParams{}, err
+	}
+	var params vault.KDFParams
+	if err := json.Unmarshal([]byte(meta["kdf"]), &params); err != nil {
+		return vault.KDFParams{}, fmt.Errorf("invalid SQLite vault header: %w", err)
+	}
+	return params, nil
+}
+
+// Info is what a SQLite vault shows without a password
+type Info struct {
+	Version   int // Şema sürümü; SchemaVersion'dan küçükse tam kayıtla yükseltilir
+	Customers int
+}
+
+// Inspect reads the schema version and the number of rows, hiçbir şey çözülmeden
+func (d *DB) Inspect() (Info, error) {
+	meta, err := d.readMeta()
+	if err != nil {
+		return Info{}, err
+	}
+	if meta["format"] != FormatName {
+		return Info{}, fmt.Errorf("%s is not a client-man SQLite vault", d.path)
+	}
+	info := Info{}
+	info.Version, _ = strconv.Atoi(meta["version"])
+	if err := d.db.QueryRow("SELECT COUNT(*) FROM clients").Scan(&info.Customers); err != nil {
+		return Info{}, err
+	}
+	return info, nil
+}
+
+// Unlock verifies the key against the stored check value and loads all rows
+func (d *DB) Unlock(ctx *vault.MigrationContext) (*vault.Unlocked, error) {
+	meta, err := d.readMeta()
+	if err != nil {
+		return nil, err
+	}
+	if meta["format"] != FormatName {
+		return nil, fmt.Errorf("%s is not a client-man SQLite vault", d.path)
+	}
+	version, _ := strconv.Atoi(meta["version"])
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("SQLite vault schema %d is newer than this program supports", version)
+	}
+	params, err := d.KDF()
+	if err != nil {
+		return nil, err
+	}
+
+	key := ctx.Key
+	if key == nil {
+		key, err = vault.DeriveKey(ctx.Password, params)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := vault.VerifyKeyCheck(meta["check"], key); err != nil {
+		return nil, err
+	}
+
+	d.keys = store.Keys{KDF: params, Key: key, Cipher: vault.CipherAESGCM}.Clone()
+	d.hasKeys = true
+	d.rowKey = d.keys.Key
+	// Eski şemada okuma firma adıyla yapılır; tek satır yazmadan önce tümü uid'ye bağlanarak yeniden yazılmalı
+	d.legacyAAD = version < SchemaVersion
+	d.rekeyed = d.legacyAAD
+
+	clients, err := d.Load()
+	if err != nil {
+		return nil, err
+	}
+	return &vault.Unlocked{
+		Clients:     clients,
+		KDF:         params,
+		Key:         key,
+		Cipher:      vault.CipherAESGCM,
+		FromVersion: vault.FormatVersion,
+		NeedsSave:   d.legacyAAD,
+	}, nil
+}
+
+// SetKeys sets the key material; farklı bir anahtar tüm satırların Save ile yeniden yazılmasını gerektirir
+func (d *DB) SetKeys(keys store.Keys) error {
+	if keys.Cipher != vault.CipherAESGCM {
+		return ErrUnsupportedCipher
+	}
+	d.keys = keys.Clone()
+	d.hasKeys = true
+	d.rekeyed = d.legacyAAD || d.rowKey == nil || string(d.rowKey) != string(keys.Key)
+	return nil
+}
+
+// Load returns all clients ordered by position
+func (d *DB) Load() ([]model.Client, error) {
+	return d.query("", nil)
+}
+
+// Query searches the plaintext columns and decrypts only the matching rows
+func (d *DB) Query(text string) ([]model.Client, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return d.query("", nil)
+	}
+	like := "%" + escapeLike(text) + "%"
+	return d.query(`WHERE lower(company) LIKE ? ESCAPE '\' OR lower(ebs_version) LIKE ? ESCAPE '\' OR lower(notes) LIKE ? ESCAPE '\'`,
+		[]interface{}{like, like, like})
+}
+
+// Get returns the client with id
+func (d *DB) Get(id string) (model.Client, error) {
+	clients, err := d.query("WHERE uid = ?", []interface{}{id})
+	if err != nil {
+		return model.Client{}, err
+	}
+	if len(clients) == 0 {
+		return model.Client{}, store.ErrNotFound
+	}
+	return clients[0], nil
+}
+
+// Save replaces every row in one transaction (yeni anahtarla yeniden şifreleme dahil)
+func (d *DB) Save(clients []model.Client) error {
+	return d.write(true, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM clients"); err != nil {
+			return err
+		}
+		for i, c := range clients {
+			r, err := d.encodeRow(c)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`INSERT INTO clients (uid, pos, company, ebs_version, notes, data, secrets) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				c.ID, i, c.Company, c.EBSVersion, c.Notes, r.data, r.secrets); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Put updates the row with c's ID, or appends a new row
+func (d *DB) Put(c model.Client) error {
+	return d.write(false, func(tx *sql.Tx) error {
+		r, err := d.encodeRow(c)
+		if err != nil {
+			return err
+		}
+		if c.ID != "" {
+			res, err := tx.Exec(`UPDATE clients SET company = ?, ebs_version = ?, notes = ?, data = ?, secrets = ? WHERE uid = ?`,
+				c.Company, c.EBSVersion, c.Notes, r.data, r.secrets, c.ID)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n > 0 {
+				return nil
+			}
+		}
+		_, err = tx.Exec(`INSERT INTO clients (uid, pos, company, ebs_version, notes, data, secrets)
+			VALUES (?, (SELECT COALESCE(MAX(pos), -1) + 1 FROM clients), ?, ?, ?, ?, ?)`,
+			c.ID, c.Company, c.EBSVersion, c.Notes, r.data, r.secrets)
+		return err
+	})
+}
+
+// Delete removes the row with id
+func (d *DB) Delete(id string) error {
+	if id == "" {
+		return store.ErrNotFound
+	}
+	return d.write(false, func(tx *sql.Tx) error {
+		res, err := tx.Exec(`DELETE FROM clients WHERE uid = ?`, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return store.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// Close closes the database and wipes its copies of the key
+func (d *DB) Close() error {
+	// rowKey önceki bir SetKeys'in kopyası olabilir; ikisi de silinir
+	for i := range d.rowKey {
+		d.rowKey[i] = 0
+	}
+	d.keys.Wipe()
+	d.hasKeys = false
+	d.rowKey = nil
+	if d.db == nil {
+		return nil
+	}
+	err := d.db.Close()
+	d.db = nil
+	return err
+}
+
+// write runs fn in a transaction and stores the meta header first when the keys changed.
+// full false ise (tek satır) anahtar değişikliğinden sonra reddedilir.
+func (d *DB) write(full bool, fn func(tx *sql.Tx) error) error {
+	if !d.hasKeys {
+		return store.ErrLocked
+	}
+	if d.rekeyed && !full {
+		return errRekeyPending
+	}
+	db, err := d.conn()
+	if err != nil {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if d.rekeyed {
+		if err := d.writeMeta(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	d.rowKey = d.keys.Key
+	d.rekeyed = false
+	d.legacyAAD = false
+	return nil
+}
+
+func (d *DB) writeMeta(tx *sql.Tx) error {
+	kdf, err := json.Marshal(d.keys.KDF)
+	if err != nil {
+		return err
+	}
+	check, err := vault.NewKeyCheck(d.keys.Key)
+	if err != nil {
+		return err
+	}
+	meta := map[string]string{
+		"format":  FormatName,
+		"version": strconv.Itoa(SchemaVersion),
+		"kdf":     string(kdf),
+		"check":   check,
+	}
+	for k, v := range meta {
+		if _, err := tx.Exec(`INSERT INTO meta (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value`, k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *DB) readMeta() (map[string]string, error) {
+	if _, err := os.Stat(d.path); err != nil {
+		return nil, err
+	}
+	db, err := d.conn()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query("SELECT key, value FROM meta")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	meta := map[string]string{}
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, err
+		}
+		meta[k] = v
+	}
+	return meta, rows.Err()
+}
+
+// query loads and decrypts the rows selected by where (sıralı)
+func (d *DB) query(where string, args []interface{}) ([]model.Client, error) {
+	if !d.hasKeys {
+		return nil, store.ErrLocked
+	}
+	if _, err := os.Stat(d.path); os.IsNotExist(err) {
+		return []model.Client{}, nil
+	}
+	db, err := d.conn()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query("SELECT uid, company, data, secrets FROM clients "+where+" ORDER BY pos", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clients := []model.Client{}
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.uid, &r.company, &r.data, &r.secrets); err != nil {
+			return nil, err
+		}
+		c, err := d.decodeRow(r)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	return clients, rows.Err()
+}
+
+// row is the stored form of a client
+type row struct {
+	uid     string
+	company string
+	data    string
+	secrets string
+}
+
+// encodeRow splits c into public JSON and the sealed secret values
+func (d *DB) encodeRow(c model.Client) (row, error) {
+	public, secrets := model.SplitSecrets(c)
+	data, err := json.Marshal(public)
+	if err != nil {
+		return row{}, err
+	}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return row{}, err
+	}
+	sealed, err := vault.SealBlob(plain, secretsAAD(c.ID), d.keys.Key)
+	if err != nil {
+		return row{}, err
+	}
+	return row{uid: c.ID, company: c.Company, data: string(data), secrets: sealed}, nil
+}
+
+// decodeRow reverses encodeRow; secrets sütunu satırın değişmez uid'sine bağlı olduğundan
+// satırlar arası kopyalanmış gizli değerler ErrTampered verir
+func (d *DB) decodeRow(r row) (model.Client, error) {
+	var c model.Client
+	if err := json.Unmarshal([]byte(r.data), &c); err != nil {
+		return model.Client{}, fmt.Errorf("customer %q: %w", r.company, err)
+	}
+	if r.secrets == "" {
+		return c, nil
+	}
+	aad := secretsAAD(r.uid)
+	if d.legacyAAD {
+		aad = legacySecretsAAD(r.company)
+	}
+	plain, err := vault.OpenBlob(r.secrets, aad, d.keys.Key)
+	if err != nil {
+		return model.Client{}, fmt.Errorf("customer %q: %w", r.company, err)
+	}
+	var secrets model.Client
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return model.Client{}, fmt.Errorf("customer %q: %w", r.company, err)
+	}
+	model.MergeSecrets(&c, secrets)
+	return c, nil
+}
+
+// secretsAAD binds the secrets of a row to the client's uid
+func secretsAAD(uid string) []byte {
+	return []byte(FormatName + "/secrets/uid/" + uid)
+}
+
+// legacySecretsAAD is the binding of schema 2 and older, firma adına
+func legacySecretsAAD(company string) []byte {
+	return []byte(FormatName + "/secrets/" + company)
+}
+
+// escapeLike escapes LIKE wildcards with a backslash
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
@@ -0,0 +1,77 @@
+// Package store persists vaults and writes files crash-safely.
+//
+// Store arayüzü vault verisinin nerede ve nasıl tutulduğunu soyutlar; JSON dosya
+// backend'i bu pakette, SQLite backend'i store/sqlite paketindedir ve dosya
+// uzantısına göre seçilir.
+//
+// WriteFile ise her yazmayı aynı dizindeki geçici bir dosyaya yapar, fsync eder,
+// hedefin üzerine rename eder ve dizini fsync eder; yarıda kalan bir yazma
+// orijinal dosyayı asla bozmaz.
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// VaultPerm is the mode for vault files, backups and exports holding secrets
+const VaultPerm os.FileMode = 0600
+
+// WriteFile atomically replaces path with data.
+// Dosya yeni oluşturulsa da üzerine yazılsa da izinleri perm olur.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	fail := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := tmp.Chmod(perm); err != nil {
+		return fail(err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fail(err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir makes the rename durable. Windows dizin fsync'i desteklemez; orada
+// MoveFileEx zaten meta veriyi diske yazar.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// CopyFile atomically copies src to dst with perm
+func CopyFile(src, dst string, perm os.FileMode) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return WriteFile(dst, data, perm)
+}
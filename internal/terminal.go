@@ -0,0 +1,309 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/sshclient"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	fynetooltip "github.com/dweymouth/fyne-tooltip"
+	"golang.org/x/crypto/ssh"
+)
+
+// terminalWindow is the SSH terminal window of one environment; her sekme ayrı bir oturumdur
+type terminalWindow struct {
+	state    *AppState
+	window   fyne.Window
+	tabs     *container.DocTabs
+	clientID string
+	appID    string
+	sessions map[*container.TabItem]*terminalSession
+	opened   int // Sekme numarası için açılan oturum sayısı
+}
+
+// terminalSession is one shell of a terminal window
+type terminalSession struct {
+	view *terminalView
+	tab  *container.TabItem
+
+	mu     sync.Mutex
+	shell  *sshclient.Shell
+	closed bool
+	done   chan struct{} // Oturum kapanınca kapatılır; bekleyen host key sorusunu iptal eder
+}
+
+// openTerminal opens a new SSH session of an environment in the embedded terminal.
+// Ortamın penceresi açıksa yeni sekme eklenir; parola panoya hiç kopyalanmaz.
+func (s *AppState) openTerminal(clientID, appID string) {
+	client, app, err := s.terminalApp(clientID, appID)
+	if err != nil {
+		dialog.ShowInformation(DialogTitleSSH, err.Error(), s.window)
+		return
+	}
+	if w, ok := s.terminals[appID]; ok {
+		w.addSession()
+		w.window.RequestFocus()
+		return
+	}
+
+	title := fmt.Sprintf(TerminalWindowTitle, client.Company, fallback(app.Type), fallback(app.Name))
+	w := &terminalWindow{
+		state:    s,
+		window:   s.myApp.NewWindow(title),
+		clientID: clientID,
+		appID:    appID,
+		sessions: map[*container.TabItem]*terminalSession{},
+	}
+	w.tabs = container.NewDocTabs()
+	w.tabs.CreateTab = w.newSession
+	w.tabs.OnClosed = func(tab *container.TabItem) {
+		if sess, ok := w.sessions[tab]; ok {
+			sess.close()
+			delete(w.sessions, tab)
+		}
+		if len(w.tabs.Items) == 0 {
+			w.window.Close()
+		}
+	}
+
+	iconSize := fyne.NewSize(18, 18)
+	toolbar := container.NewHBox(
+		NewIconButtonSimple(theme.ContentAddIcon(), "", iconSize, TerminalTipNewSession, w.addSession),
+		NewIconButtonSimple(theme.ContentCopyIcon(), "", iconSize, TerminalTipCopy, func() {
+			if sess := w.current(); sess != nil {
+				sess.view.copySelection()
+			}
+		}),
+		NewIconButtonSimple(theme.ContentPasteIcon(), "", iconSize, TerminalTipPaste, func() {
+			if sess := w.current(); sess != nil {
+				sess.view.pasteClipboard()
+			}
+		}),
+		widget.NewSeparator(),
+		NewIconButtonSimple(theme.ComputerIcon(), "", iconSize, TerminalTipExternal, func() {
+			if i := model.IndexByID(s.clients, clientID); i >= 0 {
+				if a := s.clients[i].AppByID(appID); a != nil {
+					s.openSSHShell(s.clients[i], *a)
+				}
+			}
+		}),
+	)
+
+	content := container.NewBorder(toolbar, nil, nil, nil, w.tabs)
+	w.window.SetContent(fynetooltip.AddWindowToolTipLayer(content, w.window.Canvas()))
+	w.window.SetIcon(resourceAppiconPng)
+	w.window.Resize(fyne.NewSize(TerminalWindowWidth, TerminalWindowHeight))
+	w.window.SetOnClosed(func() {
+		for _, sess := range w.sessions {
+			sess.close()
+		}
+		w.sessions = map[*container.TabItem]*terminalSession{}
+		delete(s.terminals, appID)
+	})
+
+	if s.terminals == nil {
+		s.terminals = map[string]*terminalWindow{}
+	}
+	s.terminals[appID] = w
+	w.addSession()
+	w.window.Show()
+}
+
+// closeTerminals ends every SSH session, örn. vault kilitlenirken
+func (s *AppState) closeTerminals() {
+	for _, w := range s.terminals {
+		w.window.Close()
+	}
+	s.terminals = nil
+}
+
+// terminalApp returns an environment with its current data, if it can be connected to
+func (s *AppState) terminalApp(clientID, appID string) (Client, AppInfo, error) {
+	i := model.IndexByID(s.clients, clientID)
+	if i < 0 {
+		return Client{}, AppInfo{}, errors.New(DialogMsgSSHConfig)
+	}
+	app := s.clients[i].AppByID(appID)
+	if app == nil || strings.TrimSpace(app.AppServerIP) == "" || strings.TrimSpace(app.AppServerUser) == "" {
+		return Client{}, AppInfo{}, errors.New(DialogMsgSSHConfig)
+	}
+	return s.clients[i], *app, nil
+}
+
+// appSSHParams parses the SSH params of an environment; boş alanın "—" gösterimi de boş sayılır
+func appSSHParams(app AppInfo) (sshclient.Params, error) {
+	params, err := sshclient.ParseParams(stripFallback(app.SSHParams))
+	if err != nil {
+		return params, fmt.Errorf(DialogMsgSSHParamsInvalid, err)
+	}
+	return params, nil
+}
+
+// validateSSHParams is the validator of the SSH Params edit box
+func validateSSHParams(text string) error {
+	_, err := appSSHParams(AppInfo{SSHParams: text})
+	return err
+}
+
+// appTarget returns the SSH connection of an environment's app server.
+// SSH params'tan port, kimlik dosyaları ve cipher listesi kullanılır; diğer -o seçenekleri yalnızca harici ssh içindir.
+func appTarget(app AppInfo) (sshclient.Target, error) {
+	params, err := appSSHParams(app)
+	if err != nil {
+		return sshclient.Target{}, err
+	}
+	return sshclient.Target{
+		Host:     strings.TrimSpace(app.AppServerIP),
+		Port:     params.Port,
+		User:     strings.TrimSpace(app.AppServerUser),
+		Password: app.AppServerPass,
+		KeyFiles: params.IdentityFiles,
+		Ciphers:  params.CipherList(),
+	}, nil
+}
+
+// current returns the session of the selected tab
+func (w *terminalWindow) current() *terminalSession {
+	if tab := w.tabs.Selected(); tab != nil {
+		return w.sessions[tab]
+	}
+	return nil
+}
+
+// addSession opens a new session tab and focuses it
+func (w *terminalWindow) addSession() {
+	tab := w.newSession()
+	w.tabs.Append(tab)
+	w.tabs.Select(tab)
+	w.window.Canvas().Focus(w.sessions[tab].view)
+}
+
+// newSession creates the tab of a new session and starts connecting in the background
+func (w *terminalWindow) newSession() *container.TabItem {
+	w.opened++
+	view := newTerminalView()
+	sess := &terminalSession{view: view, done: make(chan struct{})}
+	sess.tab = container.NewTabItem(fmt.Sprintf(TerminalSessionTitle, w.opened), view)
+	w.sessions[sess.tab] = sess
+	view.send = sess.write
+	view.onResize = sess.resize
+
+	// Kimlik bilgileri ve host anahtarları her oturumda güncel kayıttan okunur
+	client, app, err := w.state.terminalApp(w.clientID, w.appID)
+	if err == nil {
+		var route sshRoute
+		if route, err = appRoute(client, app); err == nil {
+			target := route.dialTarget(func(addr string, lines []string, bastionID string) (ssh.HostKeyCallback, []string) {
+				return w.hostKeyCheck(sess, addr, lines, bastionID)
+			})
+			go w.run(sess, target)
+			return sess.tab
+		}
+	}
+	fmt.Fprintf(view, "%v\r\n", err)
+	w.markEnded(sess)
+	return sess.tab
+}
+
+// run connects, then copies the shell's output into the view until the session ends
+func (w *terminalWindow) run(sess *terminalSession, target sshclient.Target) {
+	view := sess.view
+	fmt.Fprintf(view, TerminalMsgConnecting+"\r\n", target)
+	for _, jump := range target.Jumps {
+		fmt.Fprintf(view, TerminalMsgVia+"\r\n", jump)
+	}
+
+	client, err := sshclient.Dial(target)
+	if err != nil {
+		fmt.Fprintf(view, "\r\n%v\r\n", err)
+		w.markEnded(sess)
+		return
+	}
+	rows, cols := view.screen.Size()
+	shell, err := sshclient.OpenShell(client, rows, cols)
+	if err != nil {
+		client.Close()
+		fmt.Fprintf(view, "\r\n%v\r\n", err)
+		w.markEnded(sess)
+		return
+	}
+
+	sess.mu.Lock()
+	if sess.closed {
+		sess.mu.Unlock()
+		shell.Close()
+		return
+	}
+	sess.shell = shell
+	sess.mu.Unlock()
+
+	// Bağlantı sırasında pencere boyutu değişmiş olabilir
+	if r, c := view.screen.Size(); r != rows || c != cols {
+		shell.Resize(r, c)
+	}
+	view.screen.OnReply(func(b []byte) { shell.Write(b) })
+
+	_, err = io.Copy(view, shell)
+	if err != nil && !sess.isClosed() {
+		fmt.Fprintf(view, "\r\n%v", err)
+	}
+	fmt.Fprint(view, "\r\n"+TerminalMsgClosed+"\r\n")
+	shell.Close()
+	w.markEnded(sess)
+}
+
+// markEnded shows in the tab title that the session is over
+func (w *terminalWindow) markEnded(sess *terminalSession) {
+	fyne.Do(func() {
+		if !strings.HasSuffix(sess.tab.Text, TerminalSessionEndedSuffix) {
+			sess.tab.Text += TerminalSessionEndedSuffix
+			w.tabs.Refresh()
+		}
+	})
+}
+
+func (sess *terminalSession) write(data []byte) {
+	sess.mu.Lock()
+	shell := sess.shell
+	sess.mu.Unlock()
+	if shell != nil {
+		shell.Write(data)
+	}
+}
+
+func (sess *terminalSession) resize(rows, cols int) {
+	sess.mu.Lock()
+	shell := sess.shell
+	sess.mu.Unlock()
+	if shell != nil {
+		go shell.Resize(rows, cols)
+	}
+}
+
+func (sess *terminalSession) isClosed() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.closed
+}
+
+// close ends the session; birden çok kez çağrılabilir
+func (sess *terminalSession) close() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.closed {
+		return
+	}
+	sess.closed = true
+	close(sess.done)
+	if sess.shell != nil {
+		sess.shell.Close()
+	}
+}
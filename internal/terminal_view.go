@@ -0,0 +1,471 @@
+package main
+
+import (
+	"image/color"
+	"math"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"clientinfo/internal/vt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Terminal renkleri: tema ne olursa olsun koyu zemin
+var (
+	terminalBackground = color.NRGBA{R: 0x1e, G: 0x1e, B: 0x1e, A: 0xff}
+	terminalForeground = color.NRGBA{R: 0xd4, G: 0xd4, B: 0xd4, A: 0xff}
+	terminalSelection  = color.NRGBA{R: 0x26, G: 0x4f, B: 0x78, A: 0xff}
+)
+
+// terminalPalette holds the 16 basic ANSI colors (xterm varsayılanları)
+var terminalPalette = [16]color.NRGBA{
+	{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x31, 0x31, 0xff}, {0x0d, 0xbc, 0x79, 0xff}, {0xe5, 0xe5, 0x10, 0xff},
+	{0x24, 0x72, 0xc8, 0xff}, {0xbc, 0x3f, 0xbc, 0xff}, {0x11, 0xa8, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+	{0x66, 0x66, 0x66, 0xff}, {0xf1, 0x4c, 0x4c, 0xff}, {0x23, 0xd1, 0x8b, 0xff}, {0xf5, 0xf5, 0x43, 0xff},
+	{0x3b, 0x8e, 0xea, 0xff}, {0xd6, 0x70, 0xd6, 0xff}, {0x29, 0xb8, 0xdb, 0xff}, {0xff, 0xff, 0xff, 0xff},
+}
+
+// gridPos is a cell of the visible grid
+type gridPos struct{ row, col int }
+
+func (p gridPos) before(o gridPos) bool {
+	return p.row < o.row || (p.row == o.row && p.col < o.col)
+}
+
+// terminalView draws a vt.Screen and turns keyboard input into bytes for the remote shell.
+// Kopyalama fareyle seçip Ctrl+Shift+C (seçim varken Ctrl+C de), yapıştırma Ctrl+Shift+V / Ctrl+V ile.
+type terminalView struct {
+	widget.BaseWidget
+
+	screen     *vt.Screen
+	grid       *widget.TextGrid
+	background *canvas.Rectangle
+
+	send     func([]byte)         // Uzak kabuğa yazar; bağlantı yoksa nil olabilir
+	onResize func(rows, cols int) // Satır/sütun sayısı değişince
+
+	rows, cols int
+	offset     int // Scrollback'te kaç satır yukarıda
+	focused    bool
+
+	hasSelection bool
+	selecting    bool
+	selFrom      gridPos
+	selTo        gridPos
+
+	styles        map[vt.Style]*widget.CustomTextGridStyle
+	refreshQueued atomic.Bool
+}
+
+func newTerminalView() *terminalView {
+	t := &terminalView{
+		screen:     vt.New(TerminalDefaultRows, TerminalDefaultCols),
+		grid:       widget.NewTextGrid(),
+		background: canvas.NewRectangle(terminalBackground),
+		rows:       TerminalDefaultRows,
+		cols:       TerminalDefaultCols,
+		styles:     map[vt.Style]*widget.CustomTextGridStyle{},
+	}
+	t.grid.Scroll = fyne.ScrollNone
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *terminalView) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewStack(t.background, t.grid))
+}
+
+// MinSize is fixed so that the grid's content does not grow the window
+func (t *terminalView) MinSize() fyne.Size {
+	cell := terminalCellSize()
+	return fyne.NewSize(cell.Width*TerminalMinCols, cell.Height*TerminalMinRows)
+}
+
+// Resize fits the screen to the new size and tells the remote side
+func (t *terminalView) Resize(size fyne.Size) {
+	t.BaseWidget.Resize(size)
+	cell := terminalCellSize()
+	cols := max(int(size.Width/cell.Width), 1)
+	rows := max(int(size.Height/cell.Height), 1)
+	if rows != t.rows || cols != t.cols {
+		t.rows, t.cols = rows, cols
+		t.screen.Resize(rows, cols)
+		t.hasSelection = false
+		if t.onResize != nil {
+			t.onResize(rows, cols)
+		}
+	}
+	t.redraw()
+}
+
+// terminalCellSize returns the size of one monospace cell, TextGrid ile aynı hesap
+func terminalCellSize() fyne.Size {
+	size := fyne.MeasureText("M", theme.TextSize(), fyne.TextStyle{Monospace: true})
+	return fyne.NewSize(float32(math.Round(float64(size.Width))), float32(math.Round(float64(size.Height))))
+}
+
+// Write feeds remote output into the screen and schedules a redraw.
+// Okuma goroutine'inden çağrılır; art arda gelen çıktılar tek çizimde birleşir.
+func (t *terminalView) Write(p []byte) (int, error) {
+	n, err := t.screen.Write(p)
+	if t.refreshQueued.CompareAndSwap(false, true) {
+		fyne.Do(func() {
+			t.refreshQueued.Store(false)
+			t.redraw()
+		})
+	}
+	return n, err
+}
+
+// redraw copies the screen into the text grid
+func (t *terminalView) redraw() {
+	snap := t.screen.Snapshot(t.offset)
+	from, to := t.selFrom, t.selTo
+	if to.before(from) {
+		from, to = to, from
+	}
+
+	rows := make([]widget.TextGridRow, len(snap.Lines))
+	for y, line := range snap.Lines {
+		cells := make([]widget.TextGridCell, len(line))
+		for x, c := range line {
+			style := c.Style
+			pos := gridPos{y, x}
+			selected := t.hasSelection && !pos.before(from) && !to.before(pos)
+			cursor := t.focused && snap.CursorVisible && y == snap.CursorY && x == snap.CursorX
+			cells[x] = widget.TextGridCell{Rune: c.Rune, Style: t.gridStyle(style, selected, cursor)}
+		}
+		rows[y] = widget.TextGridRow{Cells: cells}
+	}
+	t.grid.Rows = rows
+	t.grid.Refresh()
+}
+
+// gridStyle returns the (önbellekteki) TextGrid style of a cell
+func (t *terminalView) gridStyle(style vt.Style, selected, cursor bool) widget.TextGridStyle {
+	key := style
+	if cursor {
+		key.Reverse = !key.Reverse
+	}
+	if selected {
+		// Seçim zemini için BG'yi, paletle çakışmayan özel bir değerle işaretle
+		key.BG = vt.RGBColor(terminalSelection.R, terminalSelection.G, terminalSelection.B)
+		key.Reverse = false
+	}
+	if s, ok := t.styles[key]; ok {
+		return s
+	}
+
+	fg, bg := terminalColor(key.FG, terminalForeground), terminalColor(key.BG, terminalBackground)
+	if key.Reverse {
+		fg, bg = bg, fg
+	}
+	s := &widget.CustomTextGridStyle{
+		FGColor:   fg,
+		BGColor:   bg,
+		TextStyle: fyne.TextStyle{Monospace: true, Bold: key.Bold, Underline: key.Underline},
+	}
+	t.styles[key] = s
+	return s
+}
+
+// terminalColor resolves a vt color; def terminalin kendi rengidir
+func terminalColor(c vt.Color, def color.NRGBA) color.NRGBA {
+	if r, g, b, ok := c.RGB(); ok {
+		return color.NRGBA{R: r, G: g, B: b, A: 0xff}
+	}
+	index, ok := c.Palette()
+	if !ok {
+		return def
+	}
+	switch {
+	case index < 16:
+		return terminalPalette[index]
+	case index < 232:
+		// 6x6x6 renk küpü
+		level := func(v uint8) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		i := index - 16
+		return color.NRGBA{R: level(i / 36), G: level(i / 6 % 6), B: level(i % 6), A: 0xff}
+	default:
+		gray := 8 + (index-232)*10
+		return color.NRGBA{R: gray, G: gray, B: gray, A: 0xff}
+	}
+}
+
+// input sends bytes typed by the user; canlı ekrana döner
+func (t *terminalView) input(data []byte) {
+	if t.send == nil || len(data) == 0 {
+		return
+	}
+	// Terminal ayrı bir penceredir; yazmak da auto-lock'u erteler
+	userActivity.Touch()
+	if t.offset != 0 || t.hasSelection {
+		t.offset = 0
+		t.hasSelection = false
+		t.redraw()
+	}
+	t.send(data)
+}
+
+// paste sends clipboard text; bracketed paste açıksa uzak taraf metni komut olarak çalıştırmaz
+func (t *terminalView) paste(text string) {
+	if text == "" {
+		return
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\r")
+	text = strings.ReplaceAll(text, "\n", "\r")
+	if t.screen.BracketedPaste() {
+		text = "\x1b[200~" + text + "\x1b[201~"
+	}
+	t.input([]byte(text))
+}
+
+// selectedText returns the text of the selection
+func (t *terminalView) selectedText() string {
+	if !t.hasSelection {
+		return ""
+	}
+	return t.screen.Snapshot(t.offset).Text(t.selFrom.row, t.selFrom.col, t.selTo.row, t.selTo.col)
+}
+
+func (t *terminalView) copySelection() {
+	if text := t.selectedText(); text != "" {
+		fyne.CurrentApp().Clipboard().SetContent(text)
+	}
+}
+
+func (t *terminalView) pasteClipboard() {
+	t.paste(fyne.CurrentApp().Clipboard().Content())
+}
+
+// FocusGained, FocusLost, TypedRune and TypedKey make the view fyne.Focusable
+func (t *terminalView) FocusGained() {
+	t.focused = true
+	t.redraw()
+}
+
+func (t *terminalView) FocusLost() {
+	t.focused = false
+	t.redraw()
+}
+
+func (t *terminalView) TypedRune(r rune) {
+	buf := make([]byte, utf8.UTFMax)
+	t.input(buf[:utf8.EncodeRune(buf, r)])
+}
+
+func (t *terminalView) TypedKey(ev *fyne.KeyEvent) {
+	if seq := t.keySequence(ev.Name); seq != "" {
+		t.input([]byte(seq))
+	}
+}
+
+// AcceptsTab keeps Tab in the terminal instead of moving focus
+func (t *terminalView) AcceptsTab() bool {
+	return true
+}
+
+// keySequence returns what xterm sends for a special key
+func (t *terminalView) keySequence(name fyne.KeyName) string {
+	arrow := func(c string) string {
+		if t.screen.AppCursorKeys() {
+			return "\x1bO" + c
+		}
+		return "\x1b[" + c
+	}
+	switch name {
+	case fyne.KeyReturn, fyne.KeyEnter:
+		return "\r"
+	case fyne.KeyBackspace:
+		return "\x7f"
+	case fyne.KeyTab:
+		return "\t"
+	case fyne.KeyEscape:
+		return "\x1b"
+	case fyne.KeyUp:
+		return arrow("A")
+	case fyne.KeyDown:
+		return arrow("B")
+	case fyne.KeyRight:
+		return arrow("C")
+	case fyne.KeyLeft:
+		return arrow("D")
+	case fyne.KeyHome:
+		return arrow("H")
+	case fyne.KeyEnd:
+		return arrow("F")
+	case fyne.KeyInsert:
+		return "\x1b[2~"
+	case fyne.KeyDelete:
+		return "\x1b[3~"
+	case fyne.KeyPageUp:
+		return "\x1b[5~"
+	case fyne.KeyPageDown:
+		return "\x1b[6~"
+	case fyne.KeyF1:
+		return "\x1bOP"
+	case fyne.KeyF2:
+		return "\x1bOQ"
+	case fyne.KeyF3:
+		return "\x1bOR"
+	case fyne.KeyF4:
+		return "\x1bOS"
+	case fyne.KeyF5:
+		return "\x1b[15~"
+	case fyne.KeyF6:
+		return "\x1b[17~"
+	case fyne.KeyF7:
+		return "\x1b[18~"
+	case fyne.KeyF8:
+		return "\x1b[19~"
+	case fyne.KeyF9:
+		return "\x1b[20~"
+	case fyne.KeyF10:
+		return "\x1b[21~"
+	case fyne.KeyF11:
+		return "\x1b[23~"
+	case fyne.KeyF12:
+		return "\x1b[24~"
+	}
+	return ""
+}
+
+// TypedShortcut receives Ctrl/Alt combinations. Fyne Ctrl+C/V/X/A/Z/Y'yi kısayola çevirir;
+// terminalde bunlar kontrol karakteridir, Ctrl+C yalnızca seçim varken kopyalar.
+func (t *terminalView) TypedShortcut(s fyne.Shortcut) {
+	switch sc := s.(type) {
+	case *fyne.ShortcutCopy:
+		if t.hasSelection {
+			t.copySelection()
+			t.hasSelection = false
+			t.redraw()
+		} else {
+			t.input([]byte{0x03})
+		}
+	case *fyne.ShortcutPaste:
+		t.pasteClipboard()
+	case *fyne.ShortcutCut:
+		t.input([]byte{0x18})
+	case *fyne.ShortcutSelectAll:
+		t.input([]byte{0x01})
+	case *fyne.ShortcutUndo:
+		t.input([]byte{0x1a})
+	case *fyne.ShortcutRedo:
+		t.input([]byte{0x19})
+	case *desktop.CustomShortcut:
+		t.customShortcut(sc)
+	}
+}
+
+func (t *terminalView) customShortcut(sc *desktop.CustomShortcut) {
+	name := string(sc.KeyName)
+	switch sc.Modifier {
+	case fyne.KeyModifierControl | fyne.KeyModifierShift:
+		switch sc.KeyName {
+		case fyne.KeyC:
+			t.copySelection()
+		case fyne.KeyV:
+			t.pasteClipboard()
+		}
+	case fyne.KeyModifierControl:
+		switch {
+		case len(name) == 1 && name[0] >= 'A' && name[0] <= 'Z':
+			t.input([]byte{name[0] - 'A' + 1})
+		case sc.KeyName == fyne.KeySpace:
+			t.input([]byte{0})
+		case sc.KeyName == fyne.KeyLeftBracket:
+			t.input([]byte{0x1b})
+		case sc.KeyName == fyne.KeyBackslash:
+			t.input([]byte{0x1c})
+		case sc.KeyName == fyne.KeyRightBracket:
+			t.input([]byte{0x1d})
+		}
+	case fyne.KeyModifierAlt:
+		// Meta tuşu: ESC öneki
+		if len(name) == 1 {
+			t.input([]byte("\x1b" + strings.ToLower(name)))
+		} else if seq := t.keySequence(sc.KeyName); seq != "" {
+			t.input([]byte("\x1b" + seq))
+		}
+	}
+}
+
+// Tapped focuses the terminal and clears the selection
+func (t *terminalView) Tapped(_ *fyne.PointEvent) {
+	if c := fyne.CurrentApp().Driver().CanvasForObject(t); c != nil {
+		c.Focus(t)
+	}
+	if t.hasSelection {
+		t.hasSelection = false
+		t.redraw()
+	}
+}
+
+// TappedSecondary shows copy and paste in a context menu
+func (t *terminalView) TappedSecondary(ev *fyne.PointEvent) {
+	c := fyne.CurrentApp().Driver().CanvasForObject(t)
+	if c == nil {
+		return
+	}
+	copyItem := fyne.NewMenuItem(TerminalMenuCopy, t.copySelection)
+	copyItem.Disabled = !t.hasSelection
+	menu := fyne.NewMenu("", copyItem, fyne.NewMenuItem(TerminalMenuPaste, t.pasteClipboard))
+	widget.ShowPopUpMenuAtPosition(menu, c, ev.AbsolutePosition)
+}
+
+// Dragged selects text with the mouse
+func (t *terminalView) Dragged(ev *fyne.DragEvent) {
+	if !t.selecting {
+		t.selecting = true
+		t.hasSelection = true
+		t.selFrom = t.cellAt(ev.Position.Subtract(ev.Dragged))
+	}
+	t.selTo = t.cellAt(ev.Position)
+	t.redraw()
+}
+
+func (t *terminalView) DragEnd() {
+	t.selecting = false
+}
+
+// Scrolled moves through the scrollback
+func (t *terminalView) Scrolled(ev *fyne.ScrollEvent) {
+	lines := int(ev.Scrolled.DY / terminalCellSize().Height)
+	if lines == 0 {
+		if ev.Scrolled.DY > 0 {
+			lines = 1
+		} else if ev.Scrolled.DY < 0 {
+			lines = -1
+		}
+	}
+	offset := max(0, min(t.offset+lines, t.screen.ScrollbackLen()))
+	if offset != t.offset {
+		t.offset = offset
+		t.hasSelection = false
+		t.redraw()
+	}
+}
+
+// Cursor shows the text cursor over the terminal
+func (t *terminalView) Cursor() desktop.Cursor {
+	return desktop.TextCursor
+}
+
+func (t *terminalView) cellAt(pos fyne.Position) gridPos {
+	cell := terminalCellSize()
+	return gridPos{
+		row: max(0, min(int(pos.Y/cell.Height), t.rows-1)),
+		col: max(0, min(int(pos.X/cell.Width), t.cols-1)),
+	}
+}
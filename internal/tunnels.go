@@ -0,0 +1,477 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"clientinfo/internal/model"
+	"clientinfo/internal/sshclient"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/crypto/ssh"
+)
+
+// tunnelRun is a started tunnel of an environment; durdurulsa da son durumu göstermek için tutulur
+type tunnelRun struct {
+	*sshclient.Tunnel
+	clientID string
+	appID    string
+}
+
+// tunnelRow holds the widgets of a tunnel that change with its state
+type tunnelRow struct {
+	status  *widget.Label
+	stats   *widget.Label
+	errText *widget.Label
+	start   *IconButton
+	stop    *IconButton
+}
+
+// update shows st in the row
+func (r *tunnelRow) update(st sshclient.TunnelStats) {
+	r.status.SetText("● " + tunnelStateText(st.State))
+	switch st.State {
+	case sshclient.TunnelRunning:
+		r.status.Importance = widget.SuccessImportance
+	case sshclient.TunnelConnecting, sshclient.TunnelReconnecting:
+		r.status.Importance = widget.WarningImportance
+	case sshclient.TunnelFailed:
+		r.status.Importance = widget.DangerImportance
+	default:
+		r.status.Importance = widget.LowImportance
+	}
+	r.status.Refresh()
+	r.stats.SetText(fmt.Sprintf(TunnelStatsFormat, st.Active, st.Total, formatBytes(st.BytesIn), formatBytes(st.BytesOut)))
+
+	if st.Err != nil && st.State != sshclient.TunnelRunning {
+		r.errText.SetText(st.Err.Error())
+		r.errText.Show()
+	} else {
+		r.errText.Hide()
+	}
+	if tunnelActive(st.State) {
+		r.start.Hide()
+		r.stop.Show()
+	} else {
+		r.stop.Hide()
+		r.start.Show()
+	}
+}
+
+// tunnelActive reports whether a tunnel in state holds its local port
+func tunnelActive(state sshclient.TunnelState) bool {
+	return state != sshclient.TunnelStopped && state != sshclient.TunnelFailed
+}
+
+func tunnelStateText(state sshclient.TunnelState) string {
+	switch state {
+	case sshclient.TunnelConnecting:
+		return TunnelStateConnecting
+	case sshclient.TunnelRunning:
+		return TunnelStateRunning
+	case sshclient.TunnelReconnecting:
+		return TunnelStateReconnecting
+	case sshclient.TunnelFailed:
+		return TunnelStateFailed
+	}
+	return TunnelStateStopped
+}
+
+// tunnelLabel is the name of a tunnel in the list and dialogs
+func tunnelLabel(t Tunnel) string {
+	if name := stripFallback(t.Name); name != "" {
+		return name
+	}
+	return TunnelUnnamed
+}
+
+// formatBytes returns n as a human readable size, örn. "12.3 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// createTunnelsCard lists the tunnels of an environment with their state and traffic
+func (s *AppState) createTunnelsCard(clientID string, app AppInfo) fyne.CanvasObject {
+	appID := app.ID
+	list := container.NewVBox()
+	for _, t := range app.Tunnels {
+		list.Add(s.createTunnelRow(clientID, app, t))
+		list.Add(widget.NewSeparator())
+	}
+	if len(app.Tunnels) == 0 {
+		none := widget.NewLabel(TunnelNone)
+		none.Importance = widget.LowImportance
+		none.Wrapping = fyne.TextWrapWord
+		list.Add(none)
+	}
+
+	title := widget.NewLabel(TunnelsTitle)
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	addBtn := NewIconButtonSimple(theme.ContentAddIcon(), TunnelAddText, fyne.NewSize(18, 18), TunnelAddTip, func() {
+		s.editTunnel(clientID, appID, Tunnel{})
+	})
+	header := container.NewBorder(nil, nil, nil, addBtn, title)
+	return widget.NewCard("", "", container.NewVBox(header, widget.NewSeparator(), list))
+}
+
+// createTunnelRow is one tunnel of the Tunnels card; satır s.tunnelRows'a kaydedilir ve durum değiştikçe güncellenir
+func (s *AppState) createTunnelRow(clientID string, app AppInfo, t Tunnel) fyne.CanvasObject {
+	appID, tunnelID := app.ID, t.ID
+	iconSize := fyne.NewSize(18, 18)
+
+	row := &tunnelRow{
+		status:  widget.NewLabel(""),
+		stats:   widget.NewLabel(""),
+		errText: widget.NewLabel(""),
+	}
+	row.errText.Importance = widget.DangerImportance
+	row.errText.Wrapping = fyne.TextWrapWord
+	row.start = NewIconButtonSimple(theme.MediaPlayIcon(), "", iconSize, TunnelStartTip, func() {
+		s.startTunnel(clientID, appID, tunnelID)
+	})
+	row.stop = NewIconButtonSimple(theme.MediaStopIcon(), "", iconSize, TunnelStopTip, func() {
+		s.stopTunnel(tunnelID)
+	})
+	editBtn := NewIconButtonSimple(theme.DocumentCreateIcon(), "", iconSize, TunnelEditTip, func() {
+		s.editTunnel(clientID, appID, t)
+	})
+	deleteBtn := NewIconButtonSimple(theme.DeleteIcon(), "", iconSize, TunnelDeleteTip, func() {
+		s.deleteTunnel(clientID, appID, tunnelID)
+	})
+
+	// Değer OnChanged atanmadan verilir; aksi halde satır her çizildiğinde kayıt yapılırdı
+	autoReconnect := widget.NewCheck(FormLabelTunnelAutoReconnect, nil)
+	autoReconnect.SetChecked(t.AutoReconnect)
+	autoReconnect.OnChanged = func(on bool) {
+		err := s.applyClientEdit(clientID, func(c *Client) {
+			if a := c.AppByID(appID); a != nil {
+				if tt := a.TunnelByID(tunnelID); tt != nil {
+					tt.AutoReconnect = on
+				}
+			}
+		})
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		if run := s.tunnels[tunnelID]; run != nil {
+			run.SetAutoReconnect(on)
+		}
+		s.filterClients(s.searchEntry.Text)
+	}
+
+	name := widget.NewLabelWithStyle(tunnelLabel(t), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	addr := widget.NewLabelWithStyle(fmt.Sprintf(TunnelAddrFormat, t.LocalPort, stripFallback(t.RemoteHost), t.RemotePort), fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	addr.Truncation = fyne.TextTruncateEllipsis
+	buttons := container.NewHBox(autoReconnect, row.start, row.stop, editBtn, deleteBtn)
+	top := container.NewBorder(nil, nil, container.NewHBox(row.status, name), buttons, addr)
+
+	if s.tunnelRows == nil {
+		s.tunnelRows = map[string]*tunnelRow{}
+	}
+	s.tunnelRows[tunnelID] = row
+	s.refreshTunnelRow(tunnelID)
+	return container.NewVBox(top, row.stats, row.errText)
+}
+
+// refreshTunnelRow shows the current state of a tunnel in its row, satır ekranda değilse bir şey yapmaz
+func (s *AppState) refreshTunnelRow(tunnelID string) {
+	row := s.tunnelRows[tunnelID]
+	if row == nil {
+		return
+	}
+	var st sshclient.TunnelStats
+	if run := s.tunnels[tunnelID]; run != nil {
+		st = run.Stats()
+	}
+	row.update(st)
+}
+
+// startTunnelRefresh starts updating the traffic counters once for the lifetime of the window.
+// Sayaçlar her baytta bildirilmez; satırlar çalışan tünel varken aralıklarla yenilenir.
+func (s *AppState) startTunnelRefresh() {
+	if s.tunnelRefreshStarted {
+		return
+	}
+	s.tunnelRefreshStarted = true
+
+	go func() {
+		ticker := time.NewTicker(TunnelRefreshInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(func() {
+				for id := range s.tunnels {
+					s.refreshTunnelRow(id)
+				}
+			})
+		}
+	}()
+}
+
+// tunnelTarget returns the SSH connection a tunnel of an environment goes through, güncel kayıttan
+func (s *AppState) tunnelTarget(clientID, appID string) (sshclient.Target, error) {
+	client, app, err := s.terminalApp(clientID, appID)
+	if err != nil {
+		return sshclient.Target{}, err
+	}
+	route, err := appRoute(client, app)
+	if err != nil {
+		return sshclient.Target{}, err
+	}
+	return route.dialTarget(s.tunnelHostKeyCheck(clientID, appID)), nil
+}
+
+// startTunnel opens the local port of a tunnel and connects it through the environment's app server.
+// Çalışan tünel yeni ayarlarla yeniden başlatılır.
+func (s *AppState) startTunnel(clientID, appID, tunnelID string) {
+	i := model.IndexByID(s.clients, clientID)
+	if i < 0 {
+		return
+	}
+	app := s.clients[i].AppByID(appID)
+	if app == nil {
+		return
+	}
+	t := app.TunnelByID(tunnelID)
+	if t == nil {
+		return
+	}
+	if _, err := s.tunnelTarget(clientID, appID); err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+	if old := s.tunnels[tunnelID]; old != nil {
+		old.Stop()
+	}
+
+	run := &tunnelRun{
+		Tunnel: &sshclient.Tunnel{
+			Local:         net.JoinHostPort("127.0.0.1", strconv.Itoa(t.LocalPort)),
+			Remote:        net.JoinHostPort(stripFallback(t.RemoteHost), strconv.Itoa(t.RemotePort)),
+			AutoReconnect: t.AutoReconnect,
+		},
+		clientID: clientID,
+		appID:    appID,
+	}
+	// Kimlik bilgileri ve host anahtarları her bağlantıda UI goroutine'inde güncel kayıttan okunur
+	run.Dial = func() (*ssh.Client, error) {
+		var target sshclient.Target
+		var err error
+		done := make(chan struct{})
+		fyne.Do(func() {
+			defer close(done)
+			target, err = s.tunnelTarget(clientID, appID)
+		})
+		<-done
+		if err != nil {
+			return nil, err
+		}
+		return sshclient.Dial(target)
+	}
+	run.OnChange = func() {
+		fyne.Do(func() { s.refreshTunnelRow(tunnelID) })
+	}
+
+	if err := run.Start(); err != nil {
+		dialog.ShowError(fmt.Errorf(DialogMsgTunnelListen, run.Local, err), s.window)
+		return
+	}
+	if s.tunnels == nil {
+		s.tunnels = map[string]*tunnelRun{}
+	}
+	s.tunnels[tunnelID] = run
+	s.startTunnelRefresh()
+	s.refreshTunnelRow(tunnelID)
+}
+
+// stopTunnel closes a tunnel and its connections
+func (s *AppState) stopTunnel(tunnelID string) {
+	if run := s.tunnels[tunnelID]; run != nil {
+		run.Stop()
+		delete(s.tunnels, tunnelID)
+	}
+	s.refreshTunnelRow(tunnelID)
+}
+
+// stopAppTunnels closes the tunnels of an environment, örn. ortam silinirken
+func (s *AppState) stopAppTunnels(appID string) {
+	for id, run := range s.tunnels {
+		if run.appID == appID {
+			s.stopTunnel(id)
+		}
+	}
+}
+
+// stopTunnels closes every tunnel, örn. vault kilitlenirken
+func (s *AppState) stopTunnels() {
+	for _, run := range s.tunnels {
+		run.Stop()
+	}
+	s.tunnels = nil
+	s.tunnelRows = nil
+}
+
+// editTunnel opens the form of a tunnel; t.ID boşsa yeni tünel eklenir.
+// Çalışan tünel kaydedildiğinde yeni ayarlarla yeniden başlatılır.
+func (s *AppState) editTunnel(clientID, appID string, t Tunnel) {
+	i := model.IndexByID(s.clients, clientID)
+	if i < 0 {
+		return
+	}
+	app := s.clients[i].AppByID(appID)
+	if app == nil {
+		return
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(stripFallback(t.Name))
+	localEntry := widget.NewEntry()
+	localEntry.Validator = validateTunnelPort
+	remoteHostEntry := widget.NewEntry()
+	remoteHostEntry.Validator = validateTunnelHost
+	remotePortEntry := widget.NewEntry()
+	remotePortEntry.Validator = validateTunnelPort
+	if t.ID != "" {
+		localEntry.SetText(strconv.Itoa(t.LocalPort))
+		remoteHostEntry.SetText(stripFallback(t.RemoteHost))
+		remotePortEntry.SetText(strconv.Itoa(t.RemotePort))
+	}
+	autoReconnect := widget.NewCheck("", nil)
+	autoReconnect.SetChecked(t.AutoReconnect)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(FormLabelTunnelName, nameEntry),
+		widget.NewFormItem(FormLabelTunnelLocalPort, localEntry),
+		widget.NewFormItem(FormLabelTunnelRemoteHost, remoteHostEntry),
+		widget.NewFormItem(FormLabelTunnelRemotePort, remotePortEntry),
+		widget.NewFormItem(FormLabelTunnelAutoReconnect, autoReconnect),
+	}
+	title := DialogTitleEditTunnel
+	if t.ID == "" {
+		title = DialogTitleAddTunnel
+		// Hazır ayarlar: veritabanı ortamın DB IP'sine, WebLogic konsolu app server'ın kendisine
+		dbHost := stripFallback(app.DBServerIP)
+		if dbHost == "" {
+			dbHost = "localhost"
+		}
+		preset := widget.NewSelect([]string{TunnelPresetDB, TunnelPresetWebLogic}, func(v string) {
+			switch v {
+			case TunnelPresetDB:
+				nameEntry.SetText(TunnelPresetDB)
+				localEntry.SetText("15210")
+				remoteHostEntry.SetText(dbHost)
+				remotePortEntry.SetText("1521")
+			case TunnelPresetWebLogic:
+				nameEntry.SetText(TunnelPresetWebLogic)
+				localEntry.SetText("17001")
+				remoteHostEntry.SetText("localhost")
+				remotePortEntry.SetText("7001")
+			}
+		})
+		preset.PlaceHolder = TunnelPresetPlaceholder
+		items = append([]*widget.FormItem{widget.NewFormItem(FormLabelTunnelPreset, preset)}, items...)
+	}
+
+	form := dialog.NewForm(title, "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		t.Name = strings.TrimSpace(nameEntry.Text)
+		t.LocalPort, _ = strconv.Atoi(strings.TrimSpace(localEntry.Text)) // Form doğruladı
+		t.RemoteHost = strings.TrimSpace(remoteHostEntry.Text)
+		t.RemotePort, _ = strconv.Atoi(strings.TrimSpace(remotePortEntry.Text))
+		t.AutoReconnect = autoReconnect.Checked
+
+		isNew := t.ID == ""
+		if isNew {
+			t.ID = model.NewID()
+		}
+		err := s.applyClientEdit(clientID, func(c *Client) {
+			a := c.AppByID(appID)
+			if a == nil {
+				return
+			}
+			if isNew {
+				a.Tunnels = append(a.Tunnels, t)
+			} else if tt := a.TunnelByID(t.ID); tt != nil {
+				*tt = t
+			}
+		})
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		if run := s.tunnels[t.ID]; run != nil && tunnelActive(run.Stats().State) {
+			s.startTunnel(clientID, appID, t.ID)
+		}
+		s.filterClients(s.searchEntry.Text)
+	}, s.window)
+	form.Resize(fyne.NewSize(420, form.MinSize().Height))
+	form.Show()
+}
+
+// deleteTunnel stops and removes a tunnel after confirmation
+func (s *AppState) deleteTunnel(clientID, appID, tunnelID string) {
+	i := model.IndexByID(s.clients, clientID)
+	if i < 0 {
+		return
+	}
+	app := s.clients[i].AppByID(appID)
+	if app == nil || app.TunnelByID(tunnelID) == nil {
+		return
+	}
+	dialog.ShowConfirm(DialogTitleDeleteTunnel, fmt.Sprintf(DialogMsgDeleteTunnel, tunnelLabel(*app.TunnelByID(tunnelID))), func(ok bool) {
+		if !ok {
+			return
+		}
+		err := s.applyClientEdit(clientID, func(c *Client) {
+			if a := c.AppByID(appID); a != nil {
+				if j := a.TunnelIndex(tunnelID); j >= 0 {
+					a.Tunnels = append(a.Tunnels[:j], a.Tunnels[j+1:]...)
+				}
+			}
+		})
+		if err != nil {
+			dialog.ShowError(err, s.window)
+			return
+		}
+		s.stopTunnel(tunnelID)
+		delete(s.tunnelRows, tunnelID)
+		s.filterClients(s.searchEntry.Text)
+	}, s.window)
+}
+
+// validateTunnelPort is the validator of the port boxes of the tunnel form
+func validateTunnelPort(text string) error {
+	port, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || port < 1 || port > 65535 {
+		return errors.New(DialogMsgTunnelPort)
+	}
+	return nil
+}
+
+// validateTunnelHost is the validator of the remote host box of the tunnel form
+func validateTunnelHost(text string) error {
+	host := strings.TrimSpace(text)
+	if host == "" || strings.ContainsFunc(host, unicode.IsSpace) || strings.ContainsAny(host, "/@") {
+		return errors.New(DialogMsgTunnelHost)
+	}
+	return nil
+}
@@ -5,20 +5,23 @@ import (
 	"path/filepath"
 	"strings"
 
+	"clientinfo/internal/vault"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	fynetooltip "github.com/dweymouth/fyne-tooltip"
 )
 
 // createEditableLabel düzenlenebilir label ve kopyalama butonu oluşturur
-func (s *AppState) createEditableLabel(text string, multiLine bool, clientIndex int, updateFunc func(*Client, string)) fyne.CanvasObject {
+func (s *AppState) createEditableLabel(text string, multiLine bool, clientID string, updateFunc func(*Client, string)) fyne.CanvasObject {
 	// Eğer text hala encrypted ise (enc: prefix varsa), decrypt et
-	if isEncrypted(text) {
-		decrypted, err := decryptString(text)
+	if vault.IsEncrypted(text) {
+		decrypted, err := vault.DecryptString(text, s.vaultKey)
 		if err == nil {
 			text = decrypted
 		}
@@ -26,11 +29,8 @@ func (s *AppState) createEditableLabel(text string, multiLine bool, clientIndex
 	}
 
 	editLabel := newEditableLabel(text, multiLine, func(newText string) {
-		if clientIndex >= 0 && clientIndex < len(s.clients) {
-			updateFunc(&s.clients[clientIndex], newText)
-			if err := s.saveClients(); err != nil {
-				dialog.ShowError(err, s.window)
-			}
+		if err := s.applyClientEdit(clientID, func(c *Client) { updateFunc(c, newText) }); err != nil {
+			dialog.ShowError(err, s.window)
 		}
 	})
 
@@ -49,10 +49,10 @@ func (s *AppState) createEditableLabel(text string, multiLine bool, clientIndex
 }
 
 // createClickableURLLabel tıklanabilir URL label oluşturur
-func (s *AppState) createClickableURLLabel(text string, clientIndex int, updateFunc func(*Client, string)) fyne.CanvasObject {
+func (s *AppState) createClickableURLLabel(text string, clientID string, updateFunc func(*Client, string)) fyne.CanvasObject {
 	// Eğer text hala encrypted ise (enc: prefix varsa), decrypt et
-	if isEncrypted(text) {
-		decrypted, err := decryptString(text)
+	if vault.IsEncrypted(text) {
+		decrypted, err := vault.DecryptString(text, s.vaultKey)
 		if err == nil {
 			text = decrypted
 		}
@@ -60,11 +60,8 @@ func (s *AppState) createClickableURLLabel(text string, clientIndex int, updateF
 	}
 
 	urlLabel := newClickableURLLabel(text, func(newText string) {
-		if clientIndex >= 0 && clientIndex < len(s.clients) {
-			updateFunc(&s.clients[clientIndex], newText)
-			if err := s.saveClients(); err != nil {
-				dialog.ShowError(err, s.window)
-			}
+		if err := s.applyClientEdit(clientID, func(c *Client) { updateFunc(c, newText) }); err != nil {
+			dialog.ShowError(err, s.window)
 		}
 	})
 
@@ -83,24 +80,33 @@ func (s *AppState) createClickableURLLabel(text string, clientIndex int, updateF
 }
 
 // createAppUsersWidget kullanıcı/şifre listesi oluşturur
-func (s *AppState) createAppUsersWidget(appUsers []string, companyName string, appIdx int) *appUsersWidget {
+func (s *AppState) createAppUsersWidget(appUsers []string, clientID, appID string) *appUsersWidget {
 	usersWidget := newAppUsersWidget(appUsers, func(newUsers []string) {
-		// Gerçek client'ı firma adına göre bul
-		for i := range s.clients {
-			if s.clients[i].Company == companyName {
-				if appIdx >= 0 && appIdx < len(s.clients[i].Apps) {
-					s.clients[i].Apps[appIdx].AppUsers = newUsers
-					if err := s.saveClients(); err != nil {
-						dialog.ShowError(err, s.window)
-					}
-				}
-				break
+		// Client ve ortam ID ile bulunur; araya giren silme / yeniden adlandırma etkilemez
+		err := s.applyClientEdit(clientID, func(c *Client) {
+			if app := c.AppByID(appID); app != nil {
+				app.AppUsers = newUsers
 			}
+		})
+		if err != nil {
+			dialog.ShowError(err, s.window)
 		}
 	})
 	return usersWidget
 }
 
+// showMainUI ana arayüzü pencereye yerleştirir
+func (s *AppState) showMainUI() {
+	s.window.SetTitle(fmt.Sprintf("%s — %s", AppName, filepath.Base(s.currentFile)))
+
+	content := s.watchActivity(s.buildUI())
+	// Tooltip layer'ını ekle
+	contentWithTooltips := fynetooltip.AddWindowToolTipLayer(content, s.window.Canvas())
+	s.window.SetContent(contentWithTooltips)
+	s.installHistoryShortcuts()
+	s.startFileWatch()
+}
+
 // buildUI ana arayüzü oluşturur
 func (s *AppState) buildUI() fyne.CanvasObject {
 	// Search box
@@ -116,7 +122,7 @@ func (s *AppState) buildUI() fyne.CanvasObject {
 
 	// Create hamburger button that shows menu items
 	var hamburgerBtn *widget.Button
-	hamburgerBtn = widget.NewButtonWithIcon("", theme.MenuIcon(), func() {
+	hamburgerBtn = widget.NewButtonWithIcon("", theme.MenuIcon(), userActivity.Tap(func() {
 		// Show popup menu with options
 		newFirmaItem := fyne.NewMenuItem("New Customer", func() {
 			s.addClient()
@@ -128,13 +134,112 @@ func (s *AppState) buildUI() fyne.CanvasObject {
 		})
 		importItem.Icon = theme.DownloadIcon()
 
+		importSheetItem := fyne.NewMenuItem(MenuImportSpreadsheet, func() {
+			s.importSpreadsheet()
+		})
+		importSheetItem.Icon = theme.FileIcon()
+
+		exportSheetItem := fyne.NewMenuItem(MenuExportSpreadsheet, func() {
+			s.exportSpreadsheet()
+		})
+		exportSheetItem.Icon = theme.UploadIcon()
+
+		importPassItem := fyne.NewMenuItem(MenuImportPasswordManager, func() {
+			s.importPasswordManager()
+		})
+		importPassItem.Icon = theme.LoginIcon()
+
+		exportPassItem := fyne.NewMenuItem(MenuExportPasswordManager, func() {
+			s.exportPasswordManager()
+		})
+		exportPassItem.Icon = theme.LogoutIcon()
+
+		importHostKeysItem := fyne.NewMenuItem(MenuImportHostKeys, func() {
+			s.importKnownHosts()
+		})
+		importHostKeysItem.Icon = theme.ComputerIcon()
+
+		exportKeyItem := fyne.NewMenuItem(MenuMyExportKey, func() {
+			s.showMyExportKey()
+		})
+		exportKeyItem.Icon = theme.AccountIcon()
+
+		openItem := fyne.NewMenuItem(MenuOpenVault, func() {
+			s.openFile()
+		})
+		openItem.Icon = theme.FolderOpenIcon()
+
+		wholeFileItem := fyne.NewMenuItem(MenuWholeFileEncryption, func() {
+			s.toggleWholeFileEncryption()
+		})
+		wholeFileItem.Checked = s.vaultCipher == vault.CipherXChaCha
+
+		rotateItem := fyne.NewMenuItem(MenuChangeMasterPassword, func() {
+			s.showChangeMasterPassword()
+		})
+		rotateItem.Icon = theme.ViewRefreshIcon()
+
+		restoreItem := fyne.NewMenuItem(MenuRestoreBackup, func() {
+			s.showRestoreBackup()
+		})
+		restoreItem.Icon = theme.HistoryIcon()
+
+		undoItem := fyne.NewMenuItem(MenuUndo, func() {
+			s.undoLastChange()
+		})
+		undoItem.Icon = theme.ContentUndoIcon()
+		undoItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault}
+		_, canUndo := s.history.NextUndo()
+		undoItem.Disabled = !canUndo
+
+		redoItem := fyne.NewMenuItem(MenuRedo, func() {
+			s.redoLastChange()
+		})
+		redoItem.Icon = theme.ContentRedoIcon()
+		redoItem.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: fyne.KeyModifierShortcutDefault}
+		_, canRedo := s.history.NextRedo()
+		redoItem.Disabled = !canRedo
+
 		menu := fyne.NewMenu("",
 			newFirmaItem,
 			importItem,
+			importSheetItem,
+			exportSheetItem,
+			importPassItem,
+			exportPassItem,
+			importHostKeysItem,
+			exportKeyItem,
+			openItem,
+			fyne.NewMenuItemSeparator(),
+			undoItem,
+			redoItem,
+			fyne.NewMenuItemSeparator(),
+			wholeFileItem,
+			rotateItem,
+			restoreItem,
 		)
+		settingsItem := fyne.NewMenuItem(MenuSecuritySettings, func() {
+			s.showSecuritySettings()
+		})
+		settingsItem.Icon = theme.SettingsIcon()
+
+		lockItem := fyne.NewMenuItem(MenuLockNow, func() {
+			s.lockVault()
+		})
+		lockItem.Icon = theme.LogoutIcon()
+
+		menu.Items = append(menu.Items, settingsItem, lockItem)
+
+		if s.hasRememberedKey(s.currentFile) {
+			forgetItem := fyne.NewMenuItem(MenuForgetKey, func() {
+				s.forgetRememberedKey()
+			})
+			forgetItem.Icon = theme.VisibilityOffIcon()
+			menu.Items = append(menu.Items, forgetItem)
+		}
 		pos := fyne.NewPos(hamburgerBtn.Position().X, hamburgerBtn.Position().Y+hamburgerBtn.Size().Height)
 		widget.NewPopUpMenu(menu, s.window.Canvas()).ShowAtPosition(pos)
-	})
+	}))
 
 	// Search bar with hamburger menu on the right
 	searchBar := container.NewBorder(
@@ -145,7 +250,13 @@ func (s *AppState) buildUI() fyne.CanvasObject {
 	)
 
 	// Toolbar with file path
+	// Pano geri sayımı (şifre kopyalandığında)
+	clipboardStatus := widget.NewLabel("")
+	clipboardStatus.Importance = widget.WarningImportance
+	secretClipboard.setStatusLabel(clipboardStatus)
+
 	toolbar := container.NewHBox(
+		clipboardStatus,
 		layout.NewSpacer(),
 		widget.NewLabel(fmt.Sprintf("📁 %s", filepath.Base(s.currentFile))),
 	)
@@ -165,9 +276,8 @@ func (s *AppState) buildUI() fyne.CanvasObject {
 func (s *AppState) buildClientList() {
 	s.listContainer.Objects = nil
 
-	for i, client := range s.filteredClients {
-		clientIndex := i
-		item := s.createExpandableClientItem(client, clientIndex)
+	for _, client := range s.filteredClients {
+		item := s.createExpandableClientItem(client)
 		s.listContainer.Objects = append(s.listContainer.Objects, item)
 	}
 
@@ -175,7 +285,7 @@ func (s *AppState) buildClientList() {
 }
 
 // createExpandableClientItem genişletilebilir firma item'ı oluşturur
-func (s *AppState) createExpandableClientItem(client Client, index int) fyne.CanvasObject {
+func (s *AppState) createExpandableClientItem(client Client) fyne.CanvasObject {
 	// Başlık metni - Renkli text badge'lerle
 	companyLabel := widget.NewLabel(client.Company)
 	companyLabel.TextStyle = fyne.TextStyle{Bold: true}
@@ -224,7 +334,7 @@ func (s *AppState) createExpandableClientItem(client Client, index int) fyne.Can
 				s.window.Canvas().Overlays().Remove(menuOverlay)
 				menuOverlay = nil
 			}
-			s.exportClientForCustomer(index)
+			s.exportClientForCustomer(client.ID)
 		})
 
 		deleteItem := newMenuItemWithIcon(theme.DeleteIcon(), "Delete", func() {
@@ -232,7 +342,7 @@ func (s *AppState) createExpandableClientItem(client Client, index int) fyne.Can
 				s.window.Canvas().Overlays().Remove(menuOverlay)
 				menuOverlay = nil
 			}
-			s.deleteClient(index)
+			s.deleteClient(client.ID)
 		})
 
 		// Menü içeriği
@@ -286,7 +396,7 @@ func (s *AppState) createExpandableClientItem(client Client, index int) fyne.Can
 	})
 
 	// Detay içeriği oluştur
-	detailContent := s.createClientDetails(client, index)
+	detailContent := s.createClientDetails(client)
 
 	// Firma başlığı için custom header oluştur (badge'ler + hamburger menü)
 	// accordionHeader yerine kendi header'ımızı oluşturalım
@@ -315,7 +425,7 @@ func (s *AppState) createExpandableClientItem(client Client, index int) fyne.Can
 	// accordionHeader'ın layout'unu taklit edelim
 
 	// Önceki expand durumunu geri yükle
-	if s.expandedCompanies[client.Company] {
+	if s.expandedClients[client.ID] {
 		expandableItem.SetExpanded(true)
 	}
 
@@ -326,7 +436,7 @@ func (s *AppState) createExpandableClientItem(client Client, index int) fyne.Can
 			originalOnTap()
 		}
 		// Durumu kaydet
-		s.expandedCompanies[client.Company] = expandableItem.IsExpanded()
+		s.expandedClients[client.ID] = expandableItem.IsExpanded()
 	}
 
 	// Ana container - arka plan ile
@@ -338,20 +448,20 @@ func (s *AppState) createExpandableClientItem(client Client, index int) fyne.Can
 	detailContainer := container.NewVBox(detailContent)
 
 	// Expand durumunu kontrol et
-	if !s.expandedCompanies[client.Company] {
+	if !s.expandedClients[client.ID] {
 		detailContainer.Hide()
 	}
 
 	// Header'a tıklama event'i ekle
-	tappableHeader := widget.NewButton("", func() {
+	tappableHeader := widget.NewButton("", userActivity.Tap(func() {
 		// Toggle expand
-		s.expandedCompanies[client.Company] = !s.expandedCompanies[client.Company]
-		if s.expandedCompanies[client.Company] {
+		s.expandedClients[client.ID] = !s.expandedClients[client.ID]
+		if s.expandedClients[client.ID] {
 			detailContainer.Show()
 		} else {
 			detailContainer.Hide()
 		}
-	})
+	}))
 	tappableHeader.Importance = widget.LowImportance
 
 	// Button'ın görünümünü özelleştir - header content'i ile
@@ -385,44 +495,44 @@ func wrapWithBlueBackground(content fyne.CanvasObject) fyne.CanvasObject {
 }
 
 // createClientDetails firma detaylarını (tabs) oluşturur
-func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObject {
+func (s *AppState) createClientDetails(client Client) fyne.CanvasObject {
 	// Tabs container
 	tabs := container.NewAppTabs()
 
 	// Firma Tab
 	firmaContent := widget.NewForm(
-		s.createCustomTextBoxItem("Company Name", client.Company, false, false, false, index, func(c *Client, v string) { c.Company = v }),
-		s.createCustomTextBoxItem("EBS Version", fallback(client.EBSVersion), false, false, false, index, func(c *Client, v string) { c.EBSVersion = v }),
-		s.createCustomTextBoxItem("Note", fallback(client.Notes), false, true, false, index, func(c *Client, v string) { c.Notes = v }),
+		s.createCustomTextBoxItem("Company Name", client.Company, false, false, false, client.ID, func(c *Client, v string) { c.Company = v }),
+		s.createCustomTextBoxItem("EBS Version", fallback(client.EBSVersion), false, false, false, client.ID, func(c *Client, v string) { c.EBSVersion = v }),
+		s.createCustomTextBoxItem("Note", fallback(client.Notes), false, true, false, client.ID, func(c *Client, v string) { c.Notes = v }),
 	)
 	tabs.Append(container.NewTabItemWithIcon(TabNameCompany, theme.InfoIcon(), wrapWithBlueBackground(firmaContent)))
 
 	// VPN Tab
 	vpnForm := widget.NewForm(
-		s.createCustomTextBoxItem("Application", fallback(client.VPN.App), false, false, false, index, func(c *Client, v string) { c.VPN.App = v }),
-		s.createCustomTextBoxItem("Host", fallback(client.VPN.Host), false, false, false, index, func(c *Client, v string) { c.VPN.Host = v }),
-		s.createCustomTextBoxItem("User", fallback(client.VPN.User), false, false, false, index, func(c *Client, v string) { c.VPN.User = v }),
-		s.createCustomTextBoxItem("Password", fallback(client.VPN.Password), true, false, false, index, func(c *Client, v string) { c.VPN.Password = v }),
-		s.createCustomTextBoxItem("2FA Auth", fallback(client.VPN.TwoFATokenApp), false, false, false, index, func(c *Client, v string) { c.VPN.TwoFATokenApp = v }),
-		s.createCustomTextBoxItem("Note", fallback(client.VPN.Notes), false, true, false, index, func(c *Client, v string) { c.VPN.Notes = v }),
+		s.createCustomTextBoxItem("Application", fallback(client.VPN.App), false, false, false, client.ID, func(c *Client, v string) { c.VPN.App = v }),
+		s.createCustomTextBoxItem("Host", fallback(client.VPN.Host), false, false, false, client.ID, func(c *Client, v string) { c.VPN.Host = v }),
+		s.createCustomTextBoxItem("User", fallback(client.VPN.User), false, false, false, client.ID, func(c *Client, v string) { c.VPN.User = v }),
+		s.createCustomTextBoxItem("Password", fallback(client.VPN.Password), true, false, false, client.ID, func(c *Client, v string) { c.VPN.Password = v }),
+		s.createCustomTextBoxItem("2FA Auth", fallback(client.VPN.TwoFATokenApp), false, false, false, client.ID, func(c *Client, v string) { c.VPN.TwoFATokenApp = v }),
+		s.createCustomTextBoxItem("Note", fallback(client.VPN.Notes), false, true, false, client.ID, func(c *Client, v string) { c.VPN.Notes = v }),
 	)
 	tabs.Append(container.NewTabItem(TabNameVPN, wrapWithBlueBackground(vpnForm)))
 
 	// Data Accordion
 	dataContent := widget.NewForm(
-		s.createCustomTextBoxItem("Jira URI", fallback(client.Data.JiraURI), false, false, true, index, func(c *Client, v string) { c.Data.JiraURI = v }),
-		s.createCustomTextBoxItem("Jira User", fallback(client.Data.JiraUser), false, false, false, index, func(c *Client, v string) { c.Data.JiraUser = v }),
-		s.createCustomTextBoxItem("Jira Pass", fallback(client.Data.JiraPassword), true, false, false, index, func(c *Client, v string) { c.Data.JiraPassword = v }),
-		s.createCustomTextBoxItem("User", fallback(client.Data.User), false, false, false, index, func(c *Client, v string) { c.Data.User = v }),
-		s.createCustomTextBoxItem("Pass Reset Info", fallback(client.Data.PasswordReset), false, false, false, index, func(c *Client, v string) { c.Data.PasswordReset = v }),
+		s.createCustomTextBoxItem("Jira URI", fallback(client.Data.JiraURI), false, false, true, client.ID, func(c *Client, v string) { c.Data.JiraURI = v }),
+		s.createCustomTextBoxItem("Jira User", fallback(client.Data.JiraUser), false, false, false, client.ID, func(c *Client, v string) { c.Data.JiraUser = v }),
+		s.createCustomTextBoxItem("Jira Pass", fallback(client.Data.JiraPassword), true, false, false, client.ID, func(c *Client, v string) { c.Data.JiraPassword = v }),
+		s.createCustomTextBoxItem("User", fallback(client.Data.User), false, false, false, client.ID, func(c *Client, v string) { c.Data.User = v }),
+		s.createCustomTextBoxItem("Pass Reset Info", fallback(client.Data.PasswordReset), false, false, false, client.ID, func(c *Client, v string) { c.Data.PasswordReset = v }),
 	)
 
 	// RDC - Custom Expandable Item
 	rdcContainer := container.NewVBox()
 	if len(client.Data.RDC) > 0 {
 		rdcTextBox := NewCustomTextBox(strings.Join(client.Data.RDC, "\n"), false, true, false, func(v string) {
-			client.Data.RDC = strings.Split(strings.TrimSpace(v), "\n")
-			if err := s.saveClients(); err != nil {
+			lines := strings.Split(strings.TrimSpace(v), "\n")
+			if err := s.applyClientEdit(client.ID, func(c *Client) { c.Data.RDC = lines }); err != nil {
 				dialog.ShowError(err, s.window)
 			}
 		}, func() fyne.Window {
@@ -439,8 +549,8 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 	hostsContainer := container.NewVBox()
 	if len(client.Data.Hosts) > 0 {
 		hostsTextBox := NewCustomTextBox(strings.Join(client.Data.Hosts, "\n"), false, true, false, func(v string) {
-			client.Data.Hosts = strings.Split(strings.TrimSpace(v), "\n")
-			if err := s.saveClients(); err != nil {
+			lines := strings.Split(strings.TrimSpace(v), "\n")
+			if err := s.applyClientEdit(client.ID, func(c *Client) { c.Data.Hosts = lines }); err != nil {
 				dialog.ShowError(err, s.window)
 			}
 		}, func() fyne.Window {
@@ -464,19 +574,27 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 	// Apps - Custom Expandable Items
 	//if len(client.Apps) > 0 {
 	appsContainer := container.NewVBox()
-	for appIdx, app := range client.Apps {
-		idx := appIdx // Capture the index value to avoid closure issues
+	for _, app := range client.Apps {
+		appID := app.ID
+		// Alan düzenlemeleri ortamı ID ile bulur; liste sırası değişse de doğru ortama yazılır
+		onApp := func(set func(*AppInfo, string)) func(*Client, string) {
+			return func(c *Client, v string) {
+				if a := c.AppByID(appID); a != nil {
+					set(a, v)
+				}
+			}
+		}
 		appTypeOptions := []string{"DEV", "TEST", "UAT", "PREP", "PROD"}
 
 		// Genel bilgiler grubu
 		generalForm := widget.NewForm(
-			s.createCustomComboBoxItem("Env Type", fallback(app.Type), appTypeOptions, index, func(c *Client, v string) { c.Apps[idx].Type = v }),
-			s.createCustomTextBoxItem("Env Name", fallback(app.Name), false, false, false, index, func(c *Client, v string) { c.Apps[idx].Name = v }),
-			s.createCustomTextBoxItem("App Link", fallback(app.AppURI), false, false, true, index, func(c *Client, v string) { c.Apps[idx].AppURI = v }),
+			s.createCustomComboBoxItem("Env Type", fallback(app.Type), appTypeOptions, client.ID, onApp(func(a *AppInfo, v string) { a.Type = v })),
+			s.createCustomTextBoxItem("Env Name", fallback(app.Name), false, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.Name = v })),
+			s.createCustomTextBoxItem("App Link", fallback(app.AppURI), false, false, true, client.ID, onApp(func(a *AppInfo, v string) { a.AppURI = v })),
 		)
 
 		// App Users için expandable item oluştur
-		usersWidget := s.createAppUsersWidget(app.AppUsers, client.Company, appIdx)
+		usersWidget := s.createAppUsersWidget(app.AppUsers, client.ID, appID)
 		currentUsersWidget := usersWidget
 		editBtn := NewIconButtonSimple(theme.DocumentCreateIcon(), "Düzenle", fyne.NewSize(18, 18), "Düzenle - Kullanıcı adı ve şifreleri düzenle", func() {
 			currentUsersWidget.startEdit()
@@ -496,10 +614,10 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 
 		// Database grubu
 		dbForm := widget.NewForm(
-			s.createCustomTextBoxItem("DB User", fallback(app.User), false, false, false, index, func(c *Client, v string) { c.Apps[idx].User = v }),
-			s.createCustomTextBoxItem("DB Pass", fallback(app.Password), true, false, false, index, func(c *Client, v string) { c.Apps[idx].Password = v }),
-			s.createCustomTextBoxItem("DB IP", fallback(app.DBServerIP), false, false, false, index, func(c *Client, v string) { c.Apps[idx].DBServerIP = v }),
-			s.createCustomTextBoxItem("TNS", fallback(app.TNS), false, false, false, index, func(c *Client, v string) { c.Apps[idx].TNS = v }),
+			s.createCustomTextBoxItem("DB User", fallback(app.User), false, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.User = v })),
+			s.createCustomTextBoxItem("DB Pass", fallback(app.Password), true, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.Password = v })),
+			s.createCustomTextBoxItem("DB IP", fallback(app.DBServerIP), false, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.DBServerIP = v })),
+			s.createCustomTextBoxItem("TNS", fallback(app.TNS), false, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.TNS = v })),
 		)
 		// Başlık ve çizgi
 		dbTitle := widget.NewLabel("Database")
@@ -508,14 +626,27 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 		dbWithHeader := container.NewVBox(dbTitle, dbLine, dbForm)
 		dbCard := widget.NewCard("", "", dbWithHeader)
 
-		// App Server grubu
+		// App Server grubu; SSH params kaydedilmeden önce ayrıştırılarak doğrulanır
+		sshParamsItem := s.createCustomTextBoxItem(FormLabelSSHParams, fallback(app.SSHParams), false, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.SSHParams = v }))
+		sshParamsItem.Widget.(*CustomTextBox).SetValidator(validateSSHParams)
 		appServerForm := widget.NewForm(
-			s.createCustomTextBoxItem("Server IP", fallback(app.AppServerIP), false, false, false, index, func(c *Client, v string) { c.Apps[idx].AppServerIP = v }),
-			s.createCustomTextBoxItem("Server URI", fallback(app.AppServerURI), false, false, true, index, func(c *Client, v string) { c.Apps[idx].AppServerURI = v }),
-			s.createCustomTextBoxItem("Server User", fallback(app.AppServerUser), false, false, false, index, func(c *Client, v string) { c.Apps[idx].AppServerUser = v }),
-			s.createCustomTextBoxItem("Server Pass", fallback(app.AppServerPass), true, false, false, index, func(c *Client, v string) { c.Apps[idx].AppServerPass = v }),
-			s.createCustomTextBoxItem("Weblogic Pass", fallback(app.WeblogicPass), true, false, false, index, func(c *Client, v string) { c.Apps[idx].WeblogicPass = v }),
-			s.createCustomTextBoxItem("SSH Params", fallback(app.SSHParams), false, false, false, index, func(c *Client, v string) { c.Apps[idx].SSHParams = v }),
+			s.createCustomTextBoxItem("Server IP", fallback(app.AppServerIP), false, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.AppServerIP = v })),
+			s.createCustomTextBoxItem("Server URI", fallback(app.AppServerURI), false, false, true, client.ID, onApp(func(a *AppInfo, v string) { a.AppServerURI = v })),
+			s.createCustomTextBoxItem("Server User", fallback(app.AppServerUser), false, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.AppServerUser = v })),
+			s.createCustomTextBoxItem("Server Pass", fallback(app.AppServerPass), true, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.AppServerPass = v })),
+			s.createCustomTextBoxItem("Weblogic Pass", fallback(app.WeblogicPass), true, false, false, client.ID, onApp(func(a *AppInfo, v string) { a.WeblogicPass = v })),
+			sshParamsItem,
+			s.bastionSelectItem(FormLabelBastion, client, app.Bastion, "", func(c *Client, id string) {
+				if a := c.AppByID(appID); a != nil {
+					a.Bastion = id
+				}
+			}),
+			s.routeFormItem(client, app),
+			s.hostKeyFormItem(client.ID, app.HostKeys, func(c *Client) {
+				if a := c.AppByID(appID); a != nil {
+					a.HostKeys = nil
+				}
+			}),
 		)
 		// Başlık ve çizgi
 		appServerTitle := widget.NewLabel("App Server")
@@ -524,10 +655,14 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 		appServerWithHeader := container.NewVBox(appServerTitle, appServerLine, appServerForm)
 		appServerCard := widget.NewCard("", "", appServerWithHeader)
 
+		// Tüneller app server üzerinden açılır
+		tunnelsCard := s.createTunnelsCard(client.ID, app)
+
 		// Tüm kartları birleştir
 		allForms := container.NewVBox(
 			generalCard,
 			appServerCard,
+			tunnelsCard,
 			dbCard,
 		)
 
@@ -538,7 +673,7 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 			fyne.NewSize(18, 18),
 			"Sil - Bu ortamı ve tüm verilerini kalıcı olarak sil",
 			func() {
-				s.deleteApp(index, idx)
+				s.deleteApp(client.ID, appID)
 			},
 		)
 
@@ -553,12 +688,10 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 				theme.ComputerIcon(),
 				"",
 				fyne.NewSize(18, 18),
-				"SSH - Sunucuya SSH bağlantısı aç",
+				"SSH - Sunucuya uygulama içi terminalde bağlan",
 				func() {
-					// AppIndex'den doğru app'i al
-					if idx < len(s.clients[index].Apps) {
-						s.openSSHShell(s.clients[index].Apps[idx])
-					}
+					// Güncel ortam bilgisi oturum açılırken ID ile alınır
+					s.openTerminal(client.ID, appID)
 				},
 			)
 			headerButtons = append(headerButtons, sshBtn)
@@ -586,10 +719,7 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 		expandableApp := newExpandableItem(header, contentWithBg)
 
 		// Önceki expand durumunu geri yükle
-		if s.expandedApps[client.Company] == nil {
-			s.expandedApps[client.Company] = make(map[int]bool)
-		}
-		if s.expandedApps[client.Company][appIdx] {
+		if s.expandedApps[appID] {
 			expandableApp.SetExpanded(true)
 		}
 
@@ -600,10 +730,7 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 				originalOnTap()
 			}
 			// Durumu kaydet
-			if s.expandedApps[client.Company] == nil {
-				s.expandedApps[client.Company] = make(map[int]bool)
-			}
-			s.expandedApps[client.Company][idx] = expandableApp.IsExpanded()
+			s.expandedApps[appID] = expandableApp.IsExpanded()
 		}
 
 		// Container'a ekle - MaxLayout ile tam genişlik
@@ -613,7 +740,7 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 
 	// Yeni ortam ekleme düğmesi - IconButton ile
 	addAppBtn := NewIconButtonSimple(theme.ContentAddIcon(), "New App Env", fyne.NewSize(24, 24), "New App Env - Add a new environment (dev, test, prod, etc.) under the customer", func() {
-		s.addApp(index)
+		s.addApp(client.ID)
 	})
 
 	// Container ve butonı container'a koy
@@ -626,8 +753,16 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 	tabs.Append(container.NewTabItem(TabNameEnvironments, wrapWithBlueBackground(appsWithButton)))
 	//}
 
+	// Bastion'lar - ortamlar bunlar üzerinden bağlanır
+	tabs.Append(container.NewTabItemWithIcon(TabNameBastions, theme.LoginIcon(), wrapWithBlueBackground(s.createBastionsTab(client))))
+
+	// Değişiklik geçmişi - sekme her açıldığında yeniden doldurulur
+	historyBox := s.createHistoryTab(client)
+	historyTab := container.NewTabItemWithIcon(TabNameHistory, theme.HistoryIcon(), wrapWithBlueBackground(historyBox))
+	tabs.Append(historyTab)
+
 	// Önceki aktif tab'ı geri yükle
-	if savedTabIndex, ok := s.activeTabIndex[client.Company]; ok {
+	if savedTabIndex, ok := s.activeTabIndex[client.ID]; ok {
 		if savedTabIndex >= 0 && savedTabIndex < len(tabs.Items) {
 			tabs.SelectIndex(savedTabIndex)
 		}
@@ -635,10 +770,13 @@ func (s *AppState) createClientDetails(client Client, index int) fyne.CanvasObje
 
 	// Tab değiştiğinde kaydet
 	tabs.OnSelected = func(item *container.TabItem) {
+		if item == historyTab {
+			s.fillHistoryTab(historyBox, client)
+		}
 		// Mevcut tab index'ini bul
 		for i, tabItem := range tabs.Items {
 			if tabItem == item {
-				s.activeTabIndex[client.Company] = i
+				s.activeTabIndex[client.ID] = i
 				break
 			}
 		}
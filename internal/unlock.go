@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"path/filepath"
+
+	"clientinfo/internal/vault"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showUnlockScreen replaces the window content with the master password prompt for path.
+// onUnlocked (opsiyonel) vault açıldıktan ve ana arayüz gösterildikten sonra çağrılır.
+func (s *AppState) showUnlockScreen(path string, status vault.Status, onUnlocked func()) {
+	title := widget.NewLabel(UnlockTitleUnlock)
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	title.Alignment = fyne.TextAlignCenter
+
+	info := widget.NewLabel(UnlockMsgUnlock)
+	switch status {
+	case vault.StatusMissing:
+		title.SetText(UnlockTitleCreate)
+		info.SetText(UnlockMsgCreate)
+	case vault.StatusLegacy:
+		title.SetText(UnlockTitleMigrate)
+		info.SetText(UnlockMsgMigrate)
+	}
+	info.Wrapping = fyne.TextWrapWord
+	info.Alignment = fyne.TextAlignCenter
+
+	fileLabel := widget.NewLabel(fmt.Sprintf("📁 %s", filepath.Base(path)))
+	fileLabel.Alignment = fyne.TextAlignCenter
+
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("Master password...")
+
+	// Yeni vault veya migration durumunda şifre tekrarı istenir
+	needsConfirm := status != vault.StatusProtected
+	confirmEntry := widget.NewPasswordEntry()
+	confirmEntry.SetPlaceHolder("Repeat master password...")
+	if !needsConfirm {
+		confirmEntry.Hide()
+	}
+
+	// Anahtar zaten hatırlanıyorsa kutu işaretli gelir; işaret kaldırılırsa kayıt silinir
+	rememberCheck := widget.NewCheck(UnlockRememberKey, nil)
+	rememberCheck.SetChecked(s.hasRememberedKey(path))
+
+	errorLabel := widget.NewLabel("")
+	errorLabel.Importance = widget.DangerImportance
+	errorLabel.Alignment = fyne.TextAlignCenter
+	errorLabel.Wrapping = fyne.TextWrapWord
+	errorLabel.Hide()
+
+	showError := func(msg string) {
+		errorLabel.SetText(msg)
+		errorLabel.Show()
+	}
+
+	var unlockBtn *widget.Button
+	submit := func() {
+		password := passwordEntry.Text
+		if needsConfirm {
+			if len([]rune(password)) < MasterPasswordMinLength {
+				showError(fmt.Sprintf(UnlockMsgTooShort, MasterPasswordMinLength))
+				return
+			}
+			if password != confirmEntry.Text {
+				showError(UnlockMsgMismatch)
+				return
+			}
+		} else if password == "" {
+			return
+		}
+
+		errorLabel.Hide()
+		unlockBtn.Disable()
+		unlockBtn.SetText(UnlockButtonWorking)
+
+		// Argon2id bilinçli olarak yavaş, UI donmasın diye arka planda çalıştır
+		go func() {
+			var err error
+			if status == vault.StatusMissing {
+				err = s.createVault(path, password)
+			} else {
+				err = s.loadClients(path, password)
+			}
+
+			fyne.Do(func() {
+				unlockBtn.Enable()
+				unlockBtn.SetText(UnlockButtonUnlock)
+				if err != nil {
+					if errors.Is(err, vault.ErrWrongPassword) {
+						showError(UnlockMsgWrongPassword)
+					} else if errors.Is(err, vault.ErrTampered) {
+						showError(UnlockMsgTampered)
+					} else {
+						showError(err.Error())
+					}
+					passwordEntry.SetText("")
+					s.window.Canvas().Focus(passwordEntry)
+					return
+				}
+
+				s.showMainUI()
+				if rememberCheck.Checked {
+					// Keyring yoksa sadece bilgi ver, vault yine de açık kalır
+					if err := s.rememberVaultKey(); err != nil {
+						dialog.ShowInformation(DialogTitleInfo, fmt.Sprintf(DialogMsgKeyringUnavailable, err), s.window)
+					}
+				} else if s.hasRememberedKey(path) {
+					if err := s.forgetVaultKey(path); err != nil {
+						dialog.ShowError(err, s.window)
+					}
+				}
+				if onUnlocked != nil {
+					onUnlocked()
+				}
+			})
+		}()
+	}
+
+	unlockBtn = widget.NewButton(UnlockButtonUnlock, submit)
+	unlockBtn.Importance = widget.HighImportance
+	passwordEntry.OnSubmitted = func(string) {
+		if needsConfirm {
+			s.window.Canvas().Focus(confirmEntry)
+			return
+		}
+		submit()
+	}
+	confirmEntry.OnSubmitted = func(string) { submit() }
+
+	buttons := []fyne.CanvasObject{unlockBtn}
+	// Başka bir dosya açılırken mevcut vault'a geri dönebilmek için
+	if s.vaultKey != nil {
+		cancelBtn := widget.NewButton("Cancel", func() {
+			s.showMainUI()
+		})
+		buttons = append(buttons, cancelBtn)
+	}
+
+	icon := canvas.NewImageFromResource(resourceAppiconPng)
+	icon.FillMode = canvas.ImageFillContain
+	icon.SetMinSize(fyne.NewSize(96, 96))
+
+	// Formun genişliğini sabitle, kelime kaydırmalı label'lar daralmasın
+	widthHolder := canvas.NewRectangle(color.Transparent)
+	widthHolder.SetMinSize(fyne.NewSize(UnlockFormWidth, 0))
+
+	form := container.NewVBox(
+		widthHolder,
+		container.NewCenter(icon),
+		title,
+		info,
+		fileLabel,
+		passwordEntry,
+		confirmEntry,
+		rememberCheck,
+		errorLabel,
+		container.NewGridWithColumns(len(buttons), buttons...),
+	)
+
+	bg := canvas.NewRectangle(colorDarkBlue)
+	card := container.NewStack(bg, container.NewPadded(form))
+
+	s.window.SetContent(container.NewCenter(card))
+	s.window.Canvas().Focus(passwordEntry)
+}
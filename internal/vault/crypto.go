@@ -0,0 +1,272 @@
+// Package vault implements the encrypted on-disk format of the client data file:
+// key derivation, the versioned envelope, its migrations and secret field encryption.
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"clientinfo/internal/model"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	encryptedPrefix = "enc:"
+	// Hardcoded salt for key derivation (legacy, master password öncesi dosyalar ve export'lar için)
+	encryptionSalt = "client-manager-secret-salt-2024-v1"
+
+	// Argon2id varsayılan parametreleri
+	kdfNameArgon2id   = "argon2id"
+	kdfDefaultTime    = 3
+	kdfDefaultMemory  = 64 * 1024 // KiB
+	kdfDefaultThreads = 4
+	kdfSaltLength     = 16
+
+	// KeyLength is the size of a derived vault key in bytes
+	KeyLength = 32
+
+	// vaultCheckPlaintext is encrypted into the file header to verify the master password
+	vaultCheckPlaintext = "client-man-vault-check"
+)
+
+var (
+	// ErrWrongPassword is returned when the master password does not match the vault header
+	ErrWrongPassword = errors.New("wrong master password")
+	// ErrTampered is returned when an encrypted payload fails authentication
+	ErrTampered = errors.New("vault data failed authentication (file was modified or corrupted)")
+)
+
+// KDFParams holds the key derivation settings stored in the vault file header
+type KDFParams struct {
+	Name    string `json:"name"`
+	Salt    string `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// NewKDFParams creates Argon2id parameters with a fresh random salt
+func NewKDFParams() (KDFParams, error) {
+	salt := make([]byte, kdfSaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return KDFParams{}, err
+	}
+	return KDFParams{
+		Name:    kdfNameArgon2id,
+		Salt:    base64.StdEncoding.EncodeToString(salt),
+		Time:    kdfDefaultTime,
+		Memory:  kdfDefaultMemory,
+		Threads: kdfDefaultThreads,
+	}, nil
+}
+
+// DeriveKey derives the 32-byte AES key from the master password
+func DeriveKey(password string, params KDFParams) ([]byte, error) {
+	if params.Name != kdfNameArgon2id {
+		return nil, fmt.Errorf("unsupported kdf: %s", params.Name)
+	}
+	salt, err := base64.StdEncoding.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kdf salt: %w", err)
+	}
+	if len(salt) == 0 || params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+		return nil, errors.New("invalid kdf parameters")
+	}
+	return argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, KeyLength), nil
+}
+
+// LegacyKey derives the fixed 32-byte key from the hardcoded salt.
+// Sadece eski dosyaları migrate etmek ve export/import uyumluluğu için kullanılır.
+func LegacyKey() []byte {
+	hash := sha256.Sum256([]byte(encryptionSalt))
+	return hash[:32]
+}
+
+// IsEncrypted checks if a string is already encrypted (has enc: prefix)
+func IsEncrypted(s string) bool {
+	return len(s) >= len(encryptedPrefix) && s[:len(encryptedPrefix)] == encryptedPrefix
+}
+
+// EncryptString encrypts plaintext with key and returns a string with prefix enc:
+func EncryptString(plain string, key []byte) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+	if IsEncrypted(plain) {
+		// already encrypted
+		return plain, nil
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	ct := gcm.Seal(nil, nonce, []byte(plain), nil)
+	out := append(nonce, ct...)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(out), nil
+}
+
+// DecryptString reverses EncryptString if string is prefixed with enc:
+func DecryptString(s string, key []byte) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	if !IsEncrypted(s) {
+		// not encrypted
+		return s, nil
+	}
+	b64 := s[len(encryptedPrefix):]
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	ns := gcm.NonceSize()
+	if len(data) < ns {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce := data[:ns]
+	ct := data[ns:]
+	pt, err := gcm.Open(nil, nonce, ct, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(pt), nil
+}
+
+// SealBlob encrypts plaintext with XChaCha20-Poly1305, binding aad, and returns base64(nonce|ciphertext)
+func SealBlob(plaintext, aad, key []byte) (string, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	out := aead.Seal(nonce, nonce, plaintext, aad)
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// OpenBlob reverses SealBlob; any change to the ciphertext or aad yields ErrTampered
+func OpenBlob(blob string, aad, key []byte) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(blob)
+	if err != nil {
+		return nil, ErrTampered
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	ns := aead.NonceSize()
+	if len(data) < ns+aead.Overhead() {
+		return nil, ErrTampered
+	}
+	pt, err := aead.Open(nil, data[:ns], data[ns:], aad)
+	if err != nil {
+		return nil, ErrTampered
+	}
+	return pt, nil
+}
+
+// NewKeyCheck encrypts the check value stored in the vault header
+func NewKeyCheck(key []byte) (string, error) {
+	return EncryptString(vaultCheckPlaintext, key)
+}
+
+// VerifyKeyCheck returns ErrWrongPassword if key cannot open the header check value
+func VerifyKeyCheck(check string, key []byte) error {
+	plain, err := DecryptString(check, key)
+	if err != nil || plain != vaultCheckPlaintext {
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+// CloneClients returns a deep copy so encryption never touches the in-memory slices
+func CloneClients(clients []model.Client) []model.Client {
+	out := make([]model.Client, len(clients))
+	for i, c := range clients {
+		out[i] = c
+		out[i].Data.RDC = cloneStrings(c.Data.RDC)
+		out[i].Data.Hosts = cloneStrings(c.Data.Hosts)
+		if c.Apps != nil {
+			out[i].Apps = make([]model.AppInfo, len(c.Apps))
+		}
+		for j, app := range c.Apps {
+			out[i].Apps[j] = app
+			out[i].Apps[j].AppUsers = cloneStrings(app.AppUsers)
+			out[i].Apps[j].HostKeys = cloneStrings(app.HostKeys)
+			if app.Tunnels != nil {
+				out[i].Apps[j].Tunnels = append([]model.Tunnel{}, app.Tunnels...)
+			}
+		}
+		if c.Bastions != nil {
+			out[i].Bastions = make([]model.Bastion, len(c.Bastions))
+		}
+		for j, b := range c.Bastions {
+			out[i].Bastions[j] = b
+			out[i].Bastions[j].HostKeys = cloneStrings(b.HostKeys)
+		}
+	}
+	return out
+}
+
+// cloneStrings copies a string slice, keeping nil and empty distinct for JSON output
+func cloneStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
+
+// EncryptClients encrypts every `secret` tagged field in clients slice in-place before saving.
+func EncryptClients(clients []model.Client, key []byte) error {
+	return model.TransformSecrets(clients, func(v string) (string, error) {
+		return EncryptString(v, key)
+	})
+}
+
+// DecryptClients decrypts every `secret` tagged field in clients slice in-place after loading.
+func DecryptClients(clients []model.Client, key []byte) error {
+	return model.TransformSecrets(clients, func(v string) (string, error) {
+		return DecryptString(v, key)
+	})
+}
+
+// HasPlaintextSecrets reports whether any `secret` tagged field is stored without the enc: prefix
+func HasPlaintextSecrets(clients []model.Client) bool {
+	found := false
+	model.VisitSecrets(clients, func(_ *model.Client, _ string, value *string) error {
+		if !IsEncrypted(*value) {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,226 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"clientinfo/internal/model"
+)
+
+const (
+	// FormatName identifies client-man vault files
+	FormatName = "client-man-vault"
+	// FormatVersion is the envelope version written by Seal
+	FormatVersion = 2
+	// CipherAESGCM: alan bazlı AES-256-GCM, "enc:" önekli değerler
+	CipherAESGCM = "aes-256-gcm"
+	// CipherXChaCha: tüm client listesi tek bir XChaCha20-Poly1305 bloğu olarak saklanır
+	CipherXChaCha = "xchacha20-poly1305"
+)
+
+// Status describes what kind of data file is found at a path
+type Status int
+
+const (
+	StatusMissing   Status = iota // Dosya yok, yeni vault oluşturulacak
+	StatusLegacy                  // Eski format: sabit anahtarla şifreli []Client dizisi
+	StatusProtected               // Master password ile korunan vault
+)
+
+// Envelope is the versioned on-disk container.
+//
+//	version 0: bare []Client array encrypted with the shared legacy key
+//	version 1: {kdf, check, clients} header without format/version fields
+//	version 2: full envelope with format, version and cipher
+type Envelope struct {
+	Format  string          `json:"format"`
+	Version int             `json:"version"`
+	KDF     KDFParams       `json:"kdf"`
+	Cipher  string          `json:"cipher"`
+	Check   string          `json:"check"`
+	Clients json.RawMessage `json:"clients,omitempty"`
+	Payload string          `json:"payload,omitempty"` // Sadece CipherXChaCha modunda
+}
+
+// envelopeHeader is the header data authenticated together with a whole-file payload
+type envelopeHeader struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	KDF     KDFParams `json:"kdf"`
+	Cipher  string    `json:"cipher"`
+}
+
+// headerAAD serializes the envelope header so changing any KDF or cipher field breaks authentication
+func (env *Envelope) headerAAD() ([]byte, error) {
+	return json.Marshal(envelopeHeader{
+		Format:  env.Format,
+		Version: env.Version,
+		KDF:     env.KDF,
+		Cipher:  env.Cipher,
+	})
+}
+
+// DetectStatus inspects the file at path without decrypting anything
+func DetectStatus(path string) (Status, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return StatusMissing, nil
+		}
+		return StatusMissing, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return StatusMissing, nil
+	}
+
+	env, err := ParseEnvelope(data)
+	if err != nil {
+		return StatusMissing, err
+	}
+	if env.Version == 0 {
+		return StatusLegacy, nil
+	}
+	return StatusProtected, nil
+}
+
+// ReadHeader reads and parses the envelope at path (şifre çözmeden, sadece başlık için)
+func ReadHeader(path string) (*Envelope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEnvelope(data)
+}
+
+// ParseEnvelope reads any known file version into an envelope without decrypting it
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	switch trimmed[0] {
+	case '[':
+		// Eski format: doğrudan client dizisi
+		var clients []model.Client
+		if err := json.Unmarshal(trimmed, &clients); err != nil {
+			return nil, err
+		}
+		return &Envelope{Version: 0, Clients: json.RawMessage(trimmed)}, nil
+	case '{':
+		var env Envelope
+		if err := json.Unmarshal(trimmed, &env); err != nil {
+			return nil, err
+		}
+		if env.Format == "" {
+			// user-001 başlık formatı: format/version alanları yok
+			env.Version = 1
+		} else if env.Format != FormatName {
+			return nil, fmt.Errorf("unknown file format: %s", env.Format)
+		}
+		if env.Version > FormatVersion {
+			return nil, fmt.Errorf("vault version %d is newer than this application supports (%d)", env.Version, FormatVersion)
+		}
+		if env.Version > 0 && (env.KDF.Name == "" || env.Check == "") {
+			return nil, errors.New("vault header is missing kdf parameters")
+		}
+		return &env, nil
+	}
+	return nil, errors.New("unknown file format")
+}
+
+// Seal encrypts clients with the given cipher mode and wraps them in a current-version envelope
+func Seal(params KDFParams, key []byte, cipherName string, clients []model.Client) (*Envelope, error) {
+	check, err := NewKeyCheck(key)
+	if err != nil {
+		return nil, err
+	}
+	env := &Envelope{
+		Format:  FormatName,
+		Version: FormatVersion,
+		KDF:     params,
+		Cipher:  cipherName,
+		Check:   check,
+	}
+
+	switch cipherName {
+	case CipherAESGCM:
+		encrypted := CloneClients(clients)
+		if err := EncryptClients(encrypted, key); err != nil {
+			return nil, err
+		}
+		env.Clients, err = json.Marshal(encrypted)
+		if err != nil {
+			return nil, err
+		}
+	case CipherXChaCha:
+		plain, err := json.Marshal(clients)
+		if err != nil {
+			return nil, err
+		}
+		aad, err := env.headerAAD()
+		if err != nil {
+			return nil, err
+		}
+		env.Payload, err = SealBlob(plain, aad, key)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported cipher: %s", cipherName)
+	}
+	return env, nil
+}
+
+// CheckKey returns ErrWrongPassword if key does not belong to the vault described by env
+func CheckKey(env *Envelope, key []byte) error {
+	return VerifyKeyCheck(env.Check, key)
+}
+
+// Open verifies the key against the header and returns decrypted clients
+func Open(env *Envelope, key []byte) ([]model.Client, error) {
+	if err := CheckKey(env, key); err != nil {
+		return nil, err
+	}
+
+	var clients []model.Client
+	switch env.Cipher {
+	case CipherAESGCM:
+		if err := json.Unmarshal(env.Clients, &clients); err != nil {
+			return nil, err
+		}
+		if err := DecryptClients(clients, key); err != nil {
+			return nil, err
+		}
+	case CipherXChaCha:
+		aad, err := env.headerAAD()
+		if err != nil {
+			return nil, err
+		}
+		plain, err := OpenBlob(env.Payload, aad, key)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(plain, &clients); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported cipher: %s", env.Cipher)
+	}
+	return clients, nil
+}
+
+// EnvelopeHasPlaintextSecrets detects secret fields left unencrypted in a per-field vault (örn. elle düzenlenmiş dosya)
+func EnvelopeHasPlaintextSecrets(env *Envelope) bool {
+	if env.Cipher != CipherAESGCM {
+		return false
+	}
+	var clients []model.Client
+	if err := json.Unmarshal(env.Clients, &clients); err != nil {
+		return false
+	}
+	return HasPlaintextSecrets(clients)
+}
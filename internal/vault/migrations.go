@@ -0,0 +1,142 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"clientinfo/internal/model"
+)
+
+// MigrationContext carries what a migration step may need besides the envelope itself
+type MigrationContext struct {
+	Password string // Vault'u açan master password
+	Key      []byte // Hazır anahtar (keyring) veya bir adımın türettiği yeni anahtar; tekrar türetilmez
+}
+
+// Migration upgrades an envelope from version From to From+1
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(env *Envelope, ctx *MigrationContext) error
+}
+
+// vaultMigrations holds the registered steps, kept sorted by From
+var vaultMigrations []Migration
+
+// registerVaultMigration adds a migration step. Her sürüm için tek bir adım olmalıdır.
+func registerVaultMigration(m Migration) {
+	for _, existing := range vaultMigrations {
+		if existing.From == m.From {
+			panic(fmt.Sprintf("vault migration from version %d registered twice", m.From))
+		}
+	}
+	vaultMigrations = append(vaultMigrations, m)
+	sort.Slice(vaultMigrations, func(i, j int) bool {
+		return vaultMigrations[i].From < vaultMigrations[j].From
+	})
+}
+
+// Migrate runs every registered step from env.Version up to FormatVersion in order.
+// Herhangi bir adım uygulandıysa true döner.
+func Migrate(env *Envelope, ctx *MigrationContext) (bool, error) {
+	migrated := false
+	for env.Version < FormatVersion {
+		step, ok := findVaultMigration(env.Version)
+		if !ok {
+			return migrated, fmt.Errorf("no migration registered for vault version %d", env.Version)
+		}
+		if err := step.Apply(env, ctx); err != nil {
+			return migrated, fmt.Errorf("migration %d→%d (%s): %w", step.From, step.From+1, step.Description, err)
+		}
+		env.Version = step.From + 1
+		migrated = true
+	}
+	return migrated, nil
+}
+
+// Pending returns the registered steps that Migrate would apply to a file at version (sırayla)
+func Pending(version int) []Migration {
+	var steps []Migration
+	for _, m := range vaultMigrations {
+		if m.From >= version && m.From < FormatVersion {
+			steps = append(steps, m)
+		}
+	}
+	return steps
+}
+
+func findVaultMigration(from int) (Migration, bool) {
+	for _, m := range vaultMigrations {
+		if m.From == from {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+func init() {
+	registerVaultMigration(Migration{
+		From:        0,
+		Description: "re-encrypt legacy shared-key array under the master password",
+		Apply:       migrateLegacyArray,
+	})
+	registerVaultMigration(Migration{
+		From:        1,
+		Description: "add format and cipher fields to the vault header",
+		Apply:       migrateHeaderToEnvelope,
+	})
+}
+
+// migrateLegacyArray decrypts a bare []model.Client with the hardcoded key and re-encrypts it with a key derived from the master password
+func migrateLegacyArray(env *Envelope, ctx *MigrationContext) error {
+	if ctx.Password == "" {
+		return errors.New("a master password is required to migrate a legacy file")
+	}
+
+	var clients []model.Client
+	if err := json.Unmarshal(env.Clients, &clients); err != nil {
+		return err
+	}
+
+	// Eski dosyada hem plaintext hem de sabit anahtarla şifrelenmiş alanlar olabilir
+	if err := DecryptClients(clients, LegacyKey()); err != nil {
+		return err
+	}
+
+	params, err := NewKDFParams()
+	if err != nil {
+		return err
+	}
+	key, err := DeriveKey(ctx.Password, params)
+	if err != nil {
+		return err
+	}
+
+	// Sürüm 1 düzenini üret; sonraki adımlar buradan devam eder
+	if err := EncryptClients(clients, key); err != nil {
+		return err
+	}
+	check, err := NewKeyCheck(key)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(clients)
+	if err != nil {
+		return err
+	}
+
+	env.KDF = params
+	env.Check = check
+	env.Clients = raw
+	ctx.Key = key
+	return nil
+}
+
+// migrateHeaderToEnvelope stamps a version 1 header with the format name and field cipher
+func migrateHeaderToEnvelope(env *Envelope, _ *MigrationContext) error {
+	env.Format = FormatName
+	env.Cipher = CipherAESGCM
+	return nil
+}
@@ -0,0 +1,96 @@
+package vault
+
+import (
+	"encoding/json"
+
+	"clientinfo/internal/model"
+)
+
+// Unlocked is a decrypted vault together with the key material needed to seal it again
+type Unlocked struct {
+	Clients     []model.Client
+	KDF         KDFParams
+	Key         []byte
+	Cipher      string
+	FromVersion int  // Dosyanın diskteki orijinal sürümü
+	NeedsSave   bool // Migration uygulandı veya şifrelenmemiş gizli alan bulundu
+}
+
+// Unlock parses, migrates and decrypts raw vault file data.
+// ctx.Key doluysa doğrudan kullanılır, değilse ctx.Password'den türetilir.
+func Unlock(data []byte, ctx *MigrationContext) (*Unlocked, error) {
+	env, err := ParseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+
+	fromVersion := env.Version
+	migrated, err := Migrate(env, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := ctx.Key
+	if key == nil {
+		key, err = DeriveKey(ctx.Password, env.KDF)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Şifrelenmemiş gizli alan varsa açtıktan sonra hemen şifreleyip kaydetmek gerekir
+	hasPlaintext := EnvelopeHasPlaintextSecrets(env)
+
+	clients, err := Open(env, key)
+	if err != nil {
+		return nil, err
+	}
+	if clients == nil {
+		clients = []model.Client{}
+	}
+
+	return &Unlocked{
+		Clients:     clients,
+		KDF:         env.KDF,
+		Key:         key,
+		Cipher:      env.Cipher,
+		FromVersion: fromVersion,
+		NeedsSave:   migrated || hasPlaintext,
+	}, nil
+}
+
+// Marshal seals clients and returns the file contents to write
+func Marshal(params KDFParams, key []byte, cipherName string, clients []model.Client) ([]byte, error) {
+	env, err := Seal(params, key, cipherName, clients)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// Rotate derives a new key from newPassword with a fresh salt and re-encrypts the
+// unlocked clients under it. Seal her gizli alan için yeni nonce ürettiğinden eski
+// anahtarla yazılmış hiçbir şifreli metin yeni dosyada kalmaz.
+func Rotate(u *Unlocked, newPassword string) (*Unlocked, []byte, error) {
+	params, err := NewKDFParams()
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := DeriveKey(newPassword, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := Marshal(params, key, u.Cipher, u.Clients)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &Unlocked{
+		Clients:     u.Clients,
+		KDF:         params,
+		Key:         key,
+		Cipher:      u.Cipher,
+		FromVersion: FormatVersion,
+	}, data, nil
+}
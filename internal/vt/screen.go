@@ -0,0 +1,814 @@
+// Package vt is a small VT100/xterm screen emulator for the embedded SSH terminal.
+// Uzak kabuğun çıktısını bir hücre ızgarasına işler; çizim GUI tarafındadır.
+package vt
+
+import (
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// ScrollbackLines is how many lines scrolled off the main screen are kept
+const ScrollbackLines = 2000
+
+// Color is a cell color: DefaultColor, a 256 color palette index or a 24 bit RGB value
+type Color uint32
+
+// DefaultColor is the terminal's own foreground or background
+const DefaultColor Color = 0
+
+const (
+	paletteFlag Color = 1 << 24
+	rgbFlag     Color = 2 << 24
+)
+
+// PaletteColor returns the color at index of the xterm 256 color palette
+func PaletteColor(index uint8) Color { return paletteFlag | Color(index) }
+
+// RGBColor returns a 24 bit color
+func RGBColor(r, g, b uint8) Color { return rgbFlag | Color(r)<<16 | Color(g)<<8 | Color(b) }
+
+// Palette reports whether c is a palette color and its index
+func (c Color) Palette() (uint8, bool) { return uint8(c), c&^0xffffff == paletteFlag }
+
+// RGB reports whether c is a 24 bit color and its components
+func (c Color) RGB() (r, g, b uint8, ok bool) {
+	return uint8(c >> 16), uint8(c >> 8), uint8(c), c&^0xffffff == rgbFlag
+}
+
+// Style is the rendition of a cell (SGR)
+type Style struct {
+	FG, BG    Color
+	Bold      bool
+	Underline bool
+	Reverse   bool
+}
+
+// Cell is one character position of the screen; silinmiş hücrelerin Rune'u 0'dır
+type Cell struct {
+	Rune  rune
+	Style Style
+}
+
+// parser states
+const (
+	stateGround = iota
+	stateEscape
+	stateCSI
+	stateOSC
+	stateOSCEscape
+	stateCharset
+)
+
+// Screen is the state of a terminal: the visible grid, cursor, modes and scrollback.
+// Write çıktıyı işler; Snapshot GUI'nin çizeceği kopyayı verir. Tüm metotlar eşzamanlı güvenlidir.
+type Screen struct {
+	mu sync.Mutex
+
+	rows, cols int
+	lines      [][]Cell
+	mainLines  [][]Cell // alternate screen açıkken ana ekran burada bekler
+	scrollback [][]Cell
+
+	cx, cy       int
+	savedX       int
+	savedY       int
+	savedStyle   Style
+	wrapPending  bool
+	top, bottom  int // kaydırma bölgesi, dahil
+	style        Style
+	cursorHidden bool
+	appCursor    bool
+	bracketPaste bool
+	title        string
+
+	state   int
+	params  []int
+	param   int
+	hasNum  bool
+	private byte
+	osc     []byte
+	pending []byte // yarım gelmiş UTF-8 baytları
+
+	reply func([]byte)
+}
+
+// New returns a blank screen of rows x cols
+func New(rows, cols int) *Screen {
+	if rows < 1 {
+		rows = 1
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	s := &Screen{rows: rows, cols: cols, bottom: rows - 1}
+	s.lines = newLines(rows, cols)
+	return s
+}
+
+func newLines(rows, cols int) [][]Cell {
+	lines := make([][]Cell, rows)
+	for i := range lines {
+		lines[i] = make([]Cell, cols)
+	}
+	return lines
+}
+
+// OnReply sets the function that receives answers to terminal queries (imleç konumu, cihaz özellikleri).
+// Write içinden, kilit bırakıldıktan sonra çağrılır.
+func (s *Screen) OnReply(reply func([]byte)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reply = reply
+}
+
+// Size returns the rows and columns of the screen
+func (s *Screen) Size() (rows, cols int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rows, s.cols
+}
+
+// Title returns the window title set by the remote side (OSC 0/2)
+func (s *Screen) Title() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.title
+}
+
+// AppCursorKeys reports whether arrow keys must be sent in application mode (ESC O A)
+func (s *Screen) AppCursorKeys() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.appCursor
+}
+
+// BracketedPaste reports whether pasted text must be wrapped in ESC[200~ ... ESC[201~
+func (s *Screen) BracketedPaste() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bracketPaste
+}
+
+// ScrollbackLen returns the number of lines that can be scrolled back to
+func (s *Screen) ScrollbackLen() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mainLines != nil {
+		return 0
+	}
+	return len(s.scrollback)
+}
+
+// Snapshot is a copy of the visible lines for drawing
+type Snapshot struct {
+	Lines         [][]Cell
+	CursorX       int
+	CursorY       int // offset kaydırmasında ekran dışındaysa -1
+	CursorVisible bool
+}
+
+// Snapshot copies the screen as seen scrolled back by offset lines (0 = canlı ekran)
+func (s *Screen) Snapshot(offset int) Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.mainLines != nil || offset < 0 {
+		offset = 0
+	}
+	if offset > len(s.scrollback) {
+		offset = len(s.scrollback)
+	}
+
+	snap := Snapshot{Lines: make([][]Cell, 0, s.rows), CursorX: s.cx, CursorY: s.cy + offset, CursorVisible: !s.cursorHidden}
+	from := len(s.scrollback) - offset
+	for _, line := range s.scrollback[from : from+min(offset, s.rows)] {
+		snap.Lines = append(snap.Lines, fitLine(line, s.cols))
+	}
+	for _, line := range s.lines[:s.rows-min(offset, s.rows)] {
+		snap.Lines = append(snap.Lines, append([]Cell(nil), line...))
+	}
+	if snap.CursorY >= s.rows {
+		snap.CursorY = -1
+	}
+	return snap
+}
+
+// fitLine copies line padded or cut to cols
+func fitLine(line []Cell, cols int) []Cell {
+	out := make([]Cell, cols)
+	copy(out, line)
+	return out
+}
+
+// Resize changes the size of the screen. Ana ekranda imlecin altında kalan
+// satırlar korunur; üstten taşan satırlar scrollback'e geçer.
+func (s *Screen) Resize(rows, cols int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rows < 1 {
+		rows = 1
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	if rows == s.rows && cols == s.cols {
+		return
+	}
+
+	s.lines = s.resizeLines(s.lines, rows, cols, true, s.mainLines == nil)
+	if s.mainLines != nil {
+		s.mainLines = s.resizeLines(s.mainLines, rows, cols, false, false)
+	}
+	s.rows, s.cols = rows, cols
+	s.top, s.bottom = 0, rows-1
+	s.cx = min(s.cx, cols-1)
+	s.cy = min(s.cy, rows-1)
+	s.savedX = min(s.savedX, cols-1)
+	s.savedY = min(s.savedY, rows-1)
+	s.wrapPending = false
+}
+
+// resizeLines fits lines to rows x cols. Satır azalıyorsa önce imlecin altındaki
+// satırlar, sonra üstteki satırlar atılır (toScrollback ise scrollback'e taşınır).
+func (s *Screen) resizeLines(lines [][]Cell, rows, cols int, hasCursor, toScrollback bool) [][]Cell {
+	if drop := len(lines) - rows; drop > 0 {
+		cut := drop
+		if hasCursor {
+			cut = min(drop, len(lines)-1-s.cy)
+		}
+		lines = lines[:len(lines)-cut]
+		drop -= cut
+		if drop > 0 {
+			if toScrollback {
+				for _, line := range lines[:drop] {
+					s.pushScrollback(line)
+				}
+			}
+			lines = lines[drop:]
+			s.cy -= drop
+		}
+	}
+	out := make([][]Cell, rows)
+	for i := range out {
+		if i < len(lines) {
+			out[i] = fitLine(lines[i], cols)
+		} else {
+			out[i] = make([]Cell, cols)
+		}
+	}
+	return out
+}
+
+func (s *Screen) pushScrollback(line []Cell) {
+	s.scrollback = append(s.scrollback, line)
+	// Her satırda kopyalamamak için sınır çeyrek kadar aşılınca kırpılır
+	if over := len(s.scrollback) - ScrollbackLines; over > ScrollbackLines/4 {
+		s.scrollback = append(s.scrollback[:0:0], s.scrollback[over:]...)
+	}
+}
+
+// Write feeds output of the remote side into the screen
+func (s *Screen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	var replies [][]byte
+	for _, b := range p {
+		if r := s.feed(b); r != nil {
+			replies = append(replies, r)
+		}
+	}
+	reply := s.reply
+	s.mu.Unlock()
+
+	if reply != nil {
+		for _, r := range replies {
+			reply(r)
+		}
+	}
+	return len(p), nil
+}
+
+// feed processes one byte; terminal sorgusuna cevap gerekiyorsa onu döner
+func (s *Screen) feed(b byte) []byte {
+	switch s.state {
+	case stateEscape:
+		s.escape(b)
+		return nil
+	case stateCSI:
+		return s.csiByte(b)
+	case stateOSC:
+		switch b {
+		case 0x07:
+			s.endOSC()
+		case 0x1b:
+			s.state = stateOSCEscape
+		default:
+			if len(s.osc) < 4096 {
+				s.osc = append(s.osc, b)
+			}
+		}
+		return nil
+	case stateOSCEscape:
+		// ESC \ (ST) OSC'yi bitirir
+		s.endOSC()
+		if b != '\\' {
+			s.state = stateEscape
+			s.escape(b)
+		}
+		return nil
+	case stateCharset:
+		// ESC ( B gibi karakter seti seçimleri yok sayılır
+		s.state = stateGround
+		return nil
+	}
+
+	if len(s.pending) > 0 || b >= 0x80 {
+		s.pending = append(s.pending, b)
+		if !utf8.FullRune(s.pending) {
+			return nil
+		}
+		r, _ := utf8.DecodeRune(s.pending)
+		s.pending = s.pending[:0]
+		s.put(r)
+		return nil
+	}
+
+	switch b {
+	case 0x1b:
+		s.state = stateEscape
+	case '\r':
+		s.cx = 0
+		s.wrapPending = false
+	case '\n', 0x0b, 0x0c:
+		s.lineFeed()
+	case 0x08:
+		if s.cx > 0 {
+			s.cx--
+		}
+		s.wrapPending = false
+	case '\t':
+		s.cx = min((s.cx/8+1)*8, s.cols-1)
+		s.wrapPending = false
+	default:
+		if b >= 0x20 && b < 0x7f {
+			s.put(rune(b))
+		}
+		// Diğer kontrol karakterleri (BEL, SO/SI, DEL) yok sayılır
+	}
+	return nil
+}
+
+// put writes a printable rune at the cursor; son sütunda satır kaydırması bir sonraki karaktere ertelenir
+func (s *Screen) put(r rune) {
+	if s.wrapPending {
+		s.cx = 0
+		s.lineFeed()
+	}
+	s.lines[s.cy][s.cx] = Cell{Rune: r, Style: s.style}
+	if s.cx == s.cols-1 {
+		s.wrapPending = true
+	} else {
+		s.cx++
+	}
+}
+
+func (s *Screen) lineFeed() {
+	s.wrapPending = false
+	switch {
+	case s.cy == s.bottom:
+		s.scrollUp(s.top, 1, true)
+	case s.cy < s.rows-1:
+		s.cy++
+	}
+}
+
+func (s *Screen) reverseIndex() {
+	s.wrapPending = false
+	switch {
+	case s.cy == s.top:
+		s.scrollDown(s.top, 1)
+	case s.cy > 0:
+		s.cy--
+	}
+}
+
+// scrollUp moves the lines from top to the bottom of the scroll region up by n.
+// keep ise ana ekranın tepesinden çıkan satırlar scrollback'e gider.
+func (s *Screen) scrollUp(top, n int, keep bool) {
+	n = min(n, s.bottom-top+1)
+	for i := 0; i < n; i++ {
+		line := s.lines[top]
+		if keep && top == 0 && s.mainLines == nil {
+			s.pushScrollback(line)
+			line = make([]Cell, s.cols)
+		} else {
+			clear(line)
+		}
+		copy(s.lines[top:s.bottom], s.lines[top+1:s.bottom+1])
+		s.lines[s.bottom] = line
+	}
+}
+
+// scrollDown moves the lines from top to the bottom of the scroll region down by n
+func (s *Screen) scrollDown(top, n int) {
+	n = min(n, s.bottom-top+1)
+	for i := 0; i < n; i++ {
+		line := s.lines[s.bottom]
+		clear(line)
+		copy(s.lines[top+1:s.bottom+1], s.lines[top:s.bottom])
+		s.lines[top] = line
+	}
+}
+
+func (s *Screen) escape(b byte) {
+	s.state = stateGround
+	switch b {
+	case '[':
+		s.state = stateCSI
+		s.params = s.params[:0]
+		s.param = 0
+		s.hasNum = false
+		s.private = 0
+	case ']':
+		s.state = stateOSC
+		s.osc = s.osc[:0]
+	case '(', ')', '*', '+':
+		s.state = stateCharset
+	case '7':
+		s.saveCursor()
+	case '8':
+		s.restoreCursor()
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.cx = 0
+		s.lineFeed()
+	case 'M':
+		s.reverseIndex()
+	case 'c':
+		s.reset()
+	}
+	// ESC = / ESC > (keypad modları) ve bilinmeyenler yok sayılır
+}
+
+func (s *Screen) reset() {
+	s.lines = newLines(s.rows, s.cols)
+	s.mainLines = nil
+	s.cx, s.cy = 0, 0
+	s.top, s.bottom = 0, s.rows-1
+	s.style = Style{}
+	s.cursorHidden = false
+	s.appCursor = false
+	s.bracketPaste = false
+	s.wrapPending = false
+}
+
+func (s *Screen) saveCursor() {
+	s.savedX, s.savedY, s.savedStyle = s.cx, s.cy, s.style
+}
+
+func (s *Screen) restoreCursor() {
+	s.cx, s.cy, s.style = s.savedX, s.savedY, s.savedStyle
+	s.wrapPending = false
+}
+
+func (s *Screen) endOSC() {
+	s.state = stateGround
+	text := string(s.osc)
+	if len(text) > 2 && (text[:2] == "0;" || text[:2] == "2;") {
+		s.title = text[2:]
+	}
+}
+
+func (s *Screen) csiByte(b byte) []byte {
+	switch {
+	case b >= '0' && b <= '9':
+		s.param = s.param*10 + int(b-'0')
+		if s.param > 9999 {
+			s.param = 9999
+		}
+		s.hasNum = true
+	case b == ';' || b == ':':
+		s.pushParam()
+	case b == '?' || b == '>' || b == '=' || b == '<':
+		s.private = b
+	case b >= 0x20 && b <= 0x2f:
+		// Ara baytlar (örn. ESC [ 0 SP q) yok sayılır
+	case b >= 0x40 && b <= 0x7e:
+		s.pushParam()
+		s.state = stateGround
+		return s.dispatch(b)
+	default:
+		// CSI içinde kontrol karakteri: xterm gibi hemen işlenir
+		if b == 0x1b {
+			s.state = stateEscape
+		}
+	}
+	return nil
+}
+
+func (s *Screen) pushParam() {
+	if len(s.params) < 16 {
+		if s.hasNum {
+			s.params = append(s.params, s.param)
+		} else {
+			s.params = append(s.params, -1)
+		}
+	}
+	s.param = 0
+	s.hasNum = false
+}
+
+// arg returns parameter i, or def when it is missing or zero
+func (s *Screen) arg(i, def int) int {
+	if i >= len(s.params) || s.params[i] <= 0 {
+		return def
+	}
+	return s.params[i]
+}
+
+func (s *Screen) dispatch(final byte) []byte {
+	if s.private == '?' {
+		switch final {
+		case 'h':
+			s.setModes(true)
+		case 'l':
+			s.setModes(false)
+		}
+		return nil
+	}
+	if s.private != 0 {
+		// ESC [ > c (ikincil cihaz özellikleri) vb.
+		return nil
+	}
+
+	n := s.arg(0, 1)
+	if final != 'r' {
+		s.wrapPending = false
+	}
+	switch final {
+	case '@':
+		line := s.lines[s.cy]
+		n = min(n, s.cols-s.cx)
+		copy(line[s.cx+n:], line[s.cx:])
+		clear(line[s.cx : s.cx+n])
+	case 'A':
+		s.cy = max(s.cy-n, s.limitTop())
+	case 'B', 'e':
+		s.cy = min(s.cy+n, s.limitBottom())
+	case 'C', 'a':
+		s.cx = min(s.cx+n, s.cols-1)
+	case 'D':
+		s.cx = max(s.cx-n, 0)
+	case 'E':
+		s.cx = 0
+		s.cy = min(s.cy+n, s.limitBottom())
+	case 'F':
+		s.cx = 0
+		s.cy = max(s.cy-n, s.limitTop())
+	case 'G', '`':
+		s.cx = clamp(n-1, 0, s.cols-1)
+	case 'd':
+		s.cy = clamp(n-1, 0, s.rows-1)
+	case 'H', 'f':
+		s.cy = clamp(s.arg(0, 1)-1, 0, s.rows-1)
+		s.cx = clamp(s.arg(1, 1)-1, 0, s.cols-1)
+	case 'J':
+		s.eraseDisplay(s.arg(0, 0))
+	case 'K':
+		s.eraseLine(s.arg(0, 0))
+	case 'X':
+		line := s.lines[s.cy]
+		clear(line[s.cx:min(s.cx+n, s.cols)])
+	case 'P':
+		line := s.lines[s.cy]
+		n = min(n, s.cols-s.cx)
+		copy(line[s.cx:], line[s.cx+n:])
+		clear(line[s.cols-n:])
+	case 'L':
+		if s.cy >= s.top && s.cy <= s.bottom {
+			s.scrollDown(s.cy, n)
+			s.cx = 0
+		}
+	case 'M':
+		// Silinen satırlar scrollback'e gitmez
+		if s.cy >= s.top && s.cy <= s.bottom {
+			s.scrollUp(s.cy, n, false)
+			s.cx = 0
+		}
+	case 'S':
+		s.scrollUp(s.top, n, true)
+	case 'T':
+		s.scrollDown(s.top, n)
+	case 'm':
+		s.sgr()
+	case 'r':
+		top := s.arg(0, 1) - 1
+		bottom := s.arg(1, s.rows) - 1
+		if top < bottom && bottom < s.rows {
+			s.top, s.bottom = top, bottom
+			s.cx, s.cy = 0, 0
+			s.wrapPending = false
+		}
+	case 's':
+		s.saveCursor()
+	case 'u':
+		s.restoreCursor()
+	case 'n':
+		switch s.arg(0, 0) {
+		case 5:
+			return []byte("\x1b[0n")
+		case 6:
+			return []byte(fmt.Sprintf("\x1b[%d;%dR", s.cy+1, s.cx+1))
+		}
+	case 'c':
+		// VT102 olarak tanıt
+		return []byte("\x1b[?6c")
+	}
+	// 't' (pencere işlemleri), 'q', 'h'/'l' (ANSI modları) yok sayılır
+	return nil
+}
+
+// limitTop and limitBottom keep vertical moves inside the scroll region when the cursor is in it
+func (s *Screen) limitTop() int {
+	if s.cy >= s.top {
+		return s.top
+	}
+	return 0
+}
+
+func (s *Screen) limitBottom() int {
+	if s.cy <= s.bottom {
+		return s.bottom
+	}
+	return s.rows - 1
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+func (s *Screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		s.eraseLine(0)
+		for _, line := range s.lines[s.cy+1:] {
+			clear(line)
+		}
+	case 1:
+		s.eraseLine(1)
+		for _, line := range s.lines[:s.cy] {
+			clear(line)
+		}
+	case 2, 3:
+		for _, line := range s.lines {
+			clear(line)
+		}
+		if mode == 3 {
+			s.scrollback = nil
+		}
+	}
+}
+
+func (s *Screen) eraseLine(mode int) {
+	line := s.lines[s.cy]
+	switch mode {
+	case 0:
+		clear(line[s.cx:])
+	case 1:
+		clear(line[:s.cx+1])
+	case 2:
+		clear(line)
+	}
+}
+
+func (s *Screen) setModes(on bool) {
+	for _, mode := range s.params {
+		switch mode {
+		case 1:
+			s.appCursor = on
+		case 25:
+			s.cursorHidden = !on
+		case 2004:
+			s.bracketPaste = on
+		case 47, 1047, 1049:
+			s.setAltScreen(on, mode == 1049)
+		}
+		// Fare izleme (1000-1006) desteklenmez; uygulamalar klavyeyle çalışmaya devam eder
+	}
+}
+
+func (s *Screen) setAltScreen(on, saveCursor bool) {
+	if on == (s.mainLines != nil) {
+		return
+	}
+	if on {
+		if saveCursor {
+			s.saveCursor()
+		}
+		s.mainLines = s.lines
+		s.lines = newLines(s.rows, s.cols)
+	} else {
+		s.lines = s.mainLines
+		s.mainLines = nil
+		if saveCursor {
+			s.restoreCursor()
+		}
+	}
+	s.top, s.bottom = 0, s.rows-1
+	s.wrapPending = false
+}
+
+// sgr applies Select Graphic Rendition parameters
+func (s *Screen) sgr() {
+	params := s.params
+	if len(params) == 0 {
+		params = []int{0}
+	}
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p <= 0:
+			s.style = Style{}
+		case p == 1:
+			s.style.Bold = true
+		case p == 4:
+			s.style.Underline = true
+		case p == 7:
+			s.style.Reverse = true
+		case p == 22:
+			s.style.Bold = false
+		case p == 24:
+			s.style.Underline = false
+		case p == 27:
+			s.style.Reverse = false
+		case p >= 30 && p <= 37:
+			s.style.FG = PaletteColor(uint8(p - 30))
+		case p == 39:
+			s.style.FG = DefaultColor
+		case p >= 40 && p <= 47:
+			s.style.BG = PaletteColor(uint8(p - 40))
+		case p == 49:
+			s.style.BG = DefaultColor
+		case p >= 90 && p <= 97:
+			s.style.FG = PaletteColor(uint8(p - 90 + 8))
+		case p >= 100 && p <= 107:
+			s.style.BG = PaletteColor(uint8(p - 100 + 8))
+		case p == 38 || p == 48:
+			c, used := extendedColor(params[i+1:])
+			i += used
+			if p == 38 {
+				s.style.FG = c
+			} else {
+				s.style.BG = c
+			}
+		}
+	}
+}
+
+// extendedColor reads "5;n" or "2;r;g;b" after SGR 38/48 and returns how many parameters it used
+func extendedColor(params []int) (Color, int) {
+	if len(params) >= 2 && params[0] == 5 {
+		return PaletteColor(uint8(clamp(params[1], 0, 255))), 2
+	}
+	if len(params) >= 4 && params[0] == 2 {
+		c := func(v int) uint8 { return uint8(clamp(v, 0, 255)) }
+		return RGBColor(c(params[1]), c(params[2]), c(params[3])), 4
+	}
+	return DefaultColor, len(params)
+}
+
+// Text returns the text between two cell positions of a snapshot, satır sonları \n ile.
+// Satır sonlarındaki boş hücreler atlanır.
+func (snap Snapshot) Text(fromRow, fromCol, toRow, toCol int) string {
+	if toRow < fromRow || (toRow == fromRow && toCol < fromCol) {
+		fromRow, fromCol, toRow, toCol = toRow, toCol, fromRow, fromCol
+	}
+	var out []rune
+	for row := max(fromRow, 0); row <= toRow && row < len(snap.Lines); row++ {
+		line := snap.Lines[row]
+		start, end := 0, len(line)
+		if row == fromRow {
+			start = min(fromCol, len(line))
+		}
+		if row == toRow {
+			end = min(toCol+1, len(line))
+		}
+		// Satırın sonundaki boş hücreleri at
+		last := end
+		for last > start && line[last-1].Rune == 0 {
+			last--
+		}
+		for _, c := range line[start:last] {
+			if c.Rune == 0 {
+				out = append(out, ' ')
+			} else {
+				out = append(out, c.Rune)
+			}
+		}
+		if row != toRow {
+			out = append(out, '\n')
+		}
+	}
+	return string(out)
+}
@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"strings"
 
+	"clientinfo/internal/vault"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
@@ -189,6 +190,7 @@ func newIconButtonRenderer(b *IconButton) *iconButtonRenderer {
 }
 
 func (b *IconButton) Tapped(_ *fyne.PointEvent) {
+	userActivity.Touch()
 	if b.onTapped != nil {
 		b.onTapped()
 	}
@@ -493,15 +495,15 @@ func (ul *clickableURLLabel) startEdit() {
 	ul.entry.SetText(ul.text)
 
 	// Kaydet butonu (✓)
-	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), func() {
+	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), userActivity.Tap(func() {
 		ul.saveEdit(ul.entry.Text)
-	})
+	}))
 	saveBtn.Importance = widget.HighImportance
 
 	// İptal butonu (✕)
-	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), userActivity.Tap(func() {
 		ul.cancelEdit()
-	})
+	}))
 	cancelBtn.Importance = widget.LowImportance
 
 	ul.entry.OnSubmitted = func(newText string) {
@@ -609,7 +611,7 @@ func (w *appUsersWidget) buildUserList() *fyne.Container {
 		// Göster/Gizle butonu
 		var showBtn *widget.Button
 		isVisible := false
-		showBtn = widget.NewButtonWithIcon("", theme.VisibilityIcon(), func() {
+		showBtn = widget.NewButtonWithIcon("", theme.VisibilityIcon(), userActivity.Tap(func() {
 			isVisible = !isVisible
 			if isVisible {
 				passLabel.SetText("🔒 " + password)
@@ -618,13 +620,13 @@ func (w *appUsersWidget) buildUserList() *fyne.Container {
 				passLabel.SetText("🔒 " + strings.Repeat("•", len(password)))
 				showBtn.SetIcon(theme.VisibilityIcon())
 			}
-		})
+		}))
 		showBtn.Importance = widget.LowImportance
 
 		// Kopyalama butonu
-		copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
-			fyne.CurrentApp().Driver().AllWindows()[0].Clipboard().SetContent(password)
-		})
+		copyBtn := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), userActivity.Tap(func() {
+			copySecret(fyne.CurrentApp().Driver().AllWindows()[0], password)
+		}))
 		copyBtn.Importance = widget.LowImportance
 
 		// Satır layout
@@ -661,7 +663,7 @@ func (w *appUsersWidget) startEdit() {
 	w.entry.Wrapping = fyne.TextWrapWord
 
 	// Kaydet butonu
-	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), func() {
+	saveBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), userActivity.Tap(func() {
 		text := strings.TrimSpace(w.entry.Text)
 		var newUsers []string
 
@@ -684,11 +686,11 @@ func (w *appUsersWidget) startEdit() {
 		if w.onSave != nil {
 			w.onSave(newUsers)
 		}
-	})
+	}))
 	saveBtn.Importance = widget.HighImportance
 
 	// İptal butonu
-	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), userActivity.Tap(func() {
 		// Liste container'ı yeniden oluştur
 		newList := w.buildUserList()
 		if w.list != nil {
@@ -696,7 +698,7 @@ func (w *appUsersWidget) startEdit() {
 			w.list.Refresh()
 		}
 		w.editing = false
-	})
+	}))
 	cancelBtn.Importance = widget.LowImportance
 
 	editContainer := container.NewBorder(
@@ -765,7 +767,7 @@ func (el *editableLabel) DoubleTapped(_ *fyne.PointEvent) {
 	el.entry.SetText(el.text)
 
 	// OK düğmesi
-	okBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), func() {
+	okBtn := widget.NewButtonWithIcon("", theme.ConfirmIcon(), userActivity.Tap(func() {
 		el.text = el.entry.Text
 		if el.onSave != nil {
 			el.onSave(el.text)
@@ -774,15 +776,15 @@ func (el *editableLabel) DoubleTapped(_ *fyne.PointEvent) {
 		el.editing = false
 		el.container.Objects = []fyne.CanvasObject{el.label}
 		el.container.Refresh()
-	})
+	}))
 	okBtn.Importance = widget.HighImportance
 
 	// Cancel düğmesi
-	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+	cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), userActivity.Tap(func() {
 		el.editing = false
 		el.container.Objects = []fyne.CanvasObject{el.label}
 		el.container.Refresh()
-	})
+	}))
 	cancelBtn.Importance = widget.LowImportance
 
 	buttons := container.NewHBox(okBtn, cancelBtn)
@@ -842,7 +844,7 @@ func createPasswordLabelWithCopy(text string, window fyne.Window) fyne.CanvasObj
 		fyne.NewSize(18, 18),
 		"Kopyala - Şifreyi panoya kopyala",
 		func() {
-			window.Clipboard().SetContent(text)
+			copySecret(window, text)
 		},
 	)
 
@@ -868,12 +870,12 @@ func createPasswordLabelWithCopy(text string, window fyne.Window) fyne.CanvasObj
 	return container.NewBorder(nil, nil, copyBtn, eyeBtn, editLabel)
 }
 
-func (s *AppState) createEditablePasswordLabel(text string, clientIndex int, updateFunc func(*Client, string)) fyne.CanvasObject {
+func (s *AppState) createEditablePasswordLabel(text string, clientID string, updateFunc func(*Client, string)) fyne.CanvasObject {
 	hidden := true
 
 	// Eğer şifre hala encrypted ise (enc: prefix varsa), decrypt et
-	if isEncrypted(text) {
-		decrypted, err := decryptString(text)
+	if vault.IsEncrypted(text) {
+		decrypted, err := vault.DecryptString(text, s.vaultKey)
 		if err == nil {
 			text = decrypted
 		}
@@ -881,11 +883,8 @@ func (s *AppState) createEditablePasswordLabel(text string, clientIndex int, upd
 	}
 
 	editLabel := newEditableLabel(strings.Repeat("•", len(text)), false, func(newText string) {
-		if clientIndex >= 0 && clientIndex < len(s.clients) {
-			updateFunc(&s.clients[clientIndex], newText)
-			if err := s.saveClients(); err != nil {
-				dialog.ShowError(err, s.window)
-			}
+		if err := s.applyClientEdit(clientID, func(c *Client) { updateFunc(c, newText) }); err != nil {
+			dialog.ShowError(err, s.window)
 		}
 	})
 
@@ -896,7 +895,7 @@ func (s *AppState) createEditablePasswordLabel(text string, clientIndex int, upd
 		fyne.NewSize(18, 18),
 		"Kopyala - Şifreyi panoya kopyala",
 		func() {
-			s.window.Clipboard().SetContent(text)
+			copySecret(s.window, text)
 		},
 	)
 
@@ -977,10 +976,10 @@ func (ts *tappableSelect) Tapped(_ *fyne.PointEvent) {}
 func (ts *tappableSelect) TappedSecondary(_ *fyne.PointEvent) {}
 
 // Select dropdown ile düzenlenebilir alan
-func (s *AppState) createEditableSelect(text string, options []string, clientIndex int, updateFunc func(*Client, string)) fyne.CanvasObject {
+func (s *AppState) createEditableSelect(text string, options []string, clientID string, updateFunc func(*Client, string)) fyne.CanvasObject {
 	// Eğer text hala encrypted ise (enc: prefix varsa), decrypt et
-	if isEncrypted(text) {
-		decrypted, err := decryptString(text)
+	if vault.IsEncrypted(text) {
+		decrypted, err := vault.DecryptString(text, s.vaultKey)
 		if err == nil {
 			text = decrypted
 		}
@@ -988,11 +987,8 @@ func (s *AppState) createEditableSelect(text string, options []string, clientInd
 	}
 
 	tappable := newTappableSelect(options, text, func(selected string) {
-		if clientIndex >= 0 && clientIndex < len(s.clients) {
-			updateFunc(&s.clients[clientIndex], selected)
-			if err := s.saveClients(); err != nil {
-				dialog.ShowError(err, s.window)
-			}
+		if err := s.applyClientEdit(clientID, func(c *Client) { updateFunc(c, selected) }); err != nil {
+			dialog.ShowError(err, s.window)
 		}
 	})
 
@@ -1108,7 +1104,7 @@ func (h *accordionHeader) CreateRenderer() fyne.WidgetRenderer {
 	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
 
 	// Expand/collapse ikonu
-	h.expandIcon = widget.NewButtonWithIcon("", theme.MenuDropDownIcon(), func() {
+	h.expandIcon = widget.NewButtonWithIcon("", theme.MenuDropDownIcon(), userActivity.Tap(func() {
 		h.expanded = !h.expanded
 		if h.expanded {
 			h.expandIcon.SetIcon(theme.MenuDropUpIcon())
@@ -1118,7 +1114,7 @@ func (h *accordionHeader) CreateRenderer() fyne.WidgetRenderer {
 		if h.onTap != nil {
 			h.onTap()
 		}
-	})
+	}))
 	h.expandIcon.Importance = widget.LowImportance
 
 	// Sağ taraf: badge + butonlar + expand icon